@@ -15,16 +15,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"ReviewAssigner/internal/config"
-	"ReviewAssigner/internal/database"
 	"ReviewAssigner/internal/handler"
-	"ReviewAssigner/internal/repository"
+	"ReviewAssigner/internal/jwt"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/outbox"
 	"ReviewAssigner/internal/service"
+	"ReviewAssigner/internal/storage"
 	"ReviewAssigner/logger"
 
 	_ "ReviewAssigner/docs"
@@ -44,27 +47,83 @@ import (
 func main() {
 	cfg := config.Load()
 
-	db, err := database.NewPostgresDB()
+	store, err := storage.Open(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to open storage: %v", err)
 	}
-	defer db.Close()
+	defer store.Close()
 
 	// репозитории
-	userRepo := repository.NewUserRepository(db)
-	teamRepo := repository.NewTeamRepository(db)
-	prRepo := repository.NewPRRepository(db)
+	userRepo := store.Users
+	teamRepo := store.Teams
+	prRepo := store.PRs
+	webhookRepo := store.Webhooks
+	eventRepo := store.Events
+	txManager := store.Tx
 
 	logger.Init("development") // или "production"
 	// сервисы
-	reviewService := service.NewReviewService(userRepo, prRepo, logger.Logger)
-	prService := service.NewPRService(prRepo, userRepo, reviewService, logger.Logger)
-	userService := service.NewUserService(userRepo, teamRepo, prRepo, reviewService, logger.Logger)
-	teamService := service.NewTeamService(teamRepo, userRepo, logger.Logger)
+	webhookService := service.NewWebhookService(webhookRepo, logger.Logger)
+	reviewService := service.NewReviewService(userRepo, prRepo, teamRepo, logger.Logger)
+	prService := service.NewPRService(prRepo, userRepo, teamRepo, reviewService, txManager, logger.Logger)
+	userService := service.NewUserService(userRepo, teamRepo, prRepo, reviewService, txManager, logger.Logger)
+	teamService := service.NewTeamService(teamRepo, userRepo, txManager, logger.Logger)
+	idempotencyService := service.NewIdempotencyService(store.Idempotency, cfg.IdempotencyTTL, logger.Logger)
+	idempotencyService.SetLease(cfg.IdempotencyLease)
+	var sso *service.SSOConfig
+	if cfg.JWKS != "" {
+		verifier, err := jwt.NewVerifier(context.Background(), jwt.Config{
+			JWKS:        cfg.JWKS,
+			Issuer:      cfg.JWTIssuer,
+			Audience:    cfg.JWTAudience,
+			GroupsClaim: cfg.JWTGroupsClaim,
+		})
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+		sso = &service.SSOConfig{
+			Verifier:     verifier,
+			GroupScopes:  cfg.JWTGroupScopes,
+			DefaultScope: cfg.JWTDefaultScope,
+		}
+	}
+	authService := service.NewAuthService(store.Tokens, store.Teams, txManager, sso, logger.Logger)
+	accessService := service.NewAccessService(userRepo, prRepo, logger.Logger)
+	auditService := service.NewAuditService(store.Audit, logger.Logger)
+
+	// первый админский токен, остальные выдаются через /api/v1/tokens или cmd/token
+	if cfg.AdminToken != "" {
+		if err := authService.EnsureToken(context.Background(), "bootstrap-admin", cfg.AdminToken, []string{models.ScopeAdmin}); err != nil {
+			log.Fatalf("Failed to register admin token: %v", err)
+		}
+	}
+
+	// публикация событий из outbox
+	dispatcher := outbox.NewDispatcher(eventRepo, logger.Logger,
+		outbox.NewLogSink(logger.Logger),
+		webhookService,
+	)
+	dispatchCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(dispatchCtx)
+	}()
+
+	// отправка вебхуков из очереди доставок
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		webhookService.Run(dispatchCtx)
+	}()
 
-	handlers := handler.NewHandler(teamService, userService, prService)
+	handlers := handler.NewHandler(teamService, userService, prService, webhookService, authService, accessService, auditService, idempotencyService, cfg)
 
 	router := gin.Default()
+	// адрес клиента попадает в журнал аудита, X-Forwarded-For принимается только от своих прокси
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	router.Use(gin.Logger())
 
@@ -81,8 +140,8 @@ func main() {
 
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -107,6 +166,9 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
-	db.Close()
+	stopDispatcher()
+	<-dispatcherDone
+	<-webhooksDone
+	store.Close()
 	log.Println("Server stopped")
 }
@@ -0,0 +1,51 @@
+// token выдаёт API-токен напрямую в хранилище сервиса. Нужен, чтобы получить
+// первый админский токен, когда ADMIN_TOKEN не задан:
+//
+//	go run ./cmd/token -name ci -scopes read,write
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"ReviewAssigner/internal/config"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
+	"ReviewAssigner/internal/storage"
+)
+
+func main() {
+	name := flag.String("name", "admin", "название токена")
+	scopes := flag.String("scopes", models.ScopeAdmin, "области через запятую: read, write, admin")
+	flag.Parse()
+
+	cfg := config.Load()
+	// токен из памяти процесса пропадёт вместе с ним
+	if cfg.Storage == config.StorageMemory {
+		log.Fatal("STORAGE=memory keeps tokens inside the server process, use ADMIN_TOKEN instead")
+	}
+
+	store, err := storage.Open(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	authService := service.NewAuthService(store.Tokens, store.Teams, store.Tx, nil, logger)
+
+	secret, token, err := authService.CreateToken(context.Background(), *name, strings.Split(*scopes, ","))
+	if err != nil {
+		store.Close()
+		log.Fatalf("Failed to create token: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "token %d %q with scopes %s, shown only once:\n",
+		token.ID, token.Name, strings.Join(token.Scopes, ","))
+	fmt.Println(secret)
+}
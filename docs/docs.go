@@ -19,32 +19,254 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/api/v1/audit": {
             "get": {
-                "description": "Проверка работоспособности сервиса",
-                "consumes": [
+                "description": "Возвращает изменяющие операции от новых к старым: кто (actor — subject токена, JWT или вебхука), что (operation, сущность, состояние до и после), когда и с какого адреса.\nПишутся создание, настройки и удаление команды, назначение и снятие лидов, смена активности пользователя, массовая деактивация, создание, переходы статуса, вердикты и мердж PR, замена ревьюера, выдача и отзыв API-токенов",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Журнал аудита",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "team.create",
+                            "team.update_settings",
+                            "team.delete",
+                            "team.add_lead",
+                            "team.remove_lead",
+                            "user.set_active",
+                            "team.deactivate_users",
+                            "pr.create",
+                            "pr.ready",
+                            "pr.review",
+                            "pr.close",
+                            "pr.reopen",
+                            "pr.merge",
+                            "pr.replace_reviewer",
+                            "token.create",
+                            "token.revoke"
+                        ],
+                        "type": "string",
+                        "description": "Операция",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "team",
+                            "user",
+                            "pull_request",
+                            "api_token"
+                        ],
+                        "type": "string",
+                        "description": "Тип сущности",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID сущности",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Не раньше, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Раньше, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница журнала",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или негодный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена или токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/me": {
+            "get": {
+                "description": "Возвращает автора запроса: API-токен или пользователя SSO с его группами и областями доступа",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "auth"
                 ],
-                "summary": "Health check",
+                "summary": "Кто я",
                 "responses": {
                     "200": {
-                        "description": "Статус сервиса",
+                        "description": "Автор запроса",
                         "schema": {
-                            "$ref": "#/definitions/handler.HealthResponse"
+                            "$ref": "#/definitions/models.Principal"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена, токен недействителен или просрочен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/pullRequest/create": {
+        "/api/v1/pull-requests": {
+            "get": {
+                "description": "Возвращает PR по фильтрам постранично. Курсор привязан к сортировке,\nдля другой сортировки нужно начинать с первой страницы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Список PR",
+                "parameters": [
+                    {
+                        "enum": [
+                            "DRAFT",
+                            "OPEN",
+                            "MERGED",
+                            "CLOSED"
+                        ],
+                        "type": "string",
+                        "description": "Статус",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Активный ревьюер",
+                        "name": "reviewer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Команда автора",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан не раньше, RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан раньше, RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "pull_request_id",
+                            "-pull_request_id"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка, по умолчанию -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или негодный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
             "post": {
-                "description": "Создает новый PR и автоматически назначает ревьюеров",
+                "description": "Создает новый PR и автоматически назначает ревьюеров. reviewer_count переопределяет число ревьюеров команды, draft создает черновик без ревьюеров и не совместим с reviewer_count",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,6 +286,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.CreatePRRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом получает первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -79,64 +307,72 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "PR уже существует",
+                        "description": "PR уже существует или запрос с этим Idempotency-Key ещё выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key уже использован с другим запросом",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/pullRequest/merge": {
-            "post": {
-                "description": "Помечает PR как мердженный",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/pull-requests/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "pull-requests"
                 ],
-                "summary": "Merge Pull Request",
+                "summary": "Получение Pull Request",
                 "parameters": [
                     {
+                        "type": "string",
                         "description": "ID Pull Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.MergePRRequest"
-                        }
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленный PR",
+                        "description": "PR",
                         "schema": {
                             "$ref": "#/definitions/handler.PRResponse"
                         }
                     },
-                    "400": {
-                        "description": "Ошибка валидации",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/pullRequest/reassign": {
-            "post": {
-                "description": "Заменяет ревьюера на PR на другого активного участника команды",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "OPEN переводит черновик в ревью или открывает закрытый PR, MERGED мерджит по политике команды (force с токеном области admin пропускает проверку), CLOSED закрывает без мерджа",
                 "consumes": [
                     "application/json"
                 ],
@@ -146,23 +382,30 @@ const docTemplate = `{
                 "tags": [
                     "pull-requests"
                 ],
-                "summary": "Замена ревьюера",
+                "summary": "Смена статуса Pull Request",
                 "parameters": [
                     {
-                        "description": "Данные для замены ревьюера",
+                        "type": "string",
+                        "description": "ID Pull Request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ReassignReviewerRequest"
+                            "$ref": "#/definitions/handler.PatchPRRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Результат замены",
+                        "description": "Обновленный PR",
                         "schema": {
-                            "$ref": "#/definitions/handler.ReassignReviewerResponse"
+                            "$ref": "#/definitions/handler.PRResponse"
                         }
                     },
                     "400": {
@@ -171,116 +414,146 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав или force без области admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "PR или ревьюер не найден",
+                        "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "Ошибка замены ревьюера",
+                        "description": "Недопустимый переход статуса или политика мерджа не выполнена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/stats/pr-metrics": {
+        "/api/v1/pull-requests/{id}/reviewers": {
             "get": {
-                "description": "Возвращает общую статистику по PR",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает назначенных ревьюеров и их вердикты",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "statistics"
+                    "pull-requests"
                 ],
-                "summary": "Метрики Pull Requests",
-                "responses": {
-                    "200": {
-                        "description": "Метрики PR",
-                        "schema": {
-                            "$ref": "#/definitions/handler.StatsResponse"
-                        }
+                "summary": "Ревьюеры Pull Request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Pull Request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                }
-            }
-        },
-        "/stats/user-assignments": {
-            "get": {
-                "description": "Возвращает количество назначений PR на каждого пользователя",
-                "consumes": [
-                    "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "statistics"
-                ],
-                "summary": "Статистика назначений по пользователям",
                 "responses": {
                     "200": {
-                        "description": "Статистика назначений",
+                        "description": "Ревьюеры",
                         "schema": {
-                            "$ref": "#/definitions/handler.StatsResponse"
+                            "$ref": "#/definitions/handler.PRReviewersResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/team/add": {
-            "post": {
-                "description": "Создает новую команду с участниками",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/pull-requests/{id}/reviewers/{reviewerID}": {
+            "delete": {
+                "description": "Снимает ревьюера с PR и назначает вместо него другого активного участника команды",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "teams"
+                    "pull-requests"
                 ],
-                "summary": "Создание команды",
+                "summary": "Замена ревьюера",
                 "parameters": [
                     {
-                        "description": "Данные команды",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.AddTeamRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Созданная команда",
+                        "type": "string",
+                        "description": "ID Pull Request",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID снимаемого ревьюера",
+                        "name": "reviewerID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом получает первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат замены",
                         "schema": {
-                            "$ref": "#/definitions/handler.TeamResponse"
+                            "$ref": "#/definitions/handler.ReassignReviewerResponse"
                         }
                     },
-                    "400": {
-                        "description": "Ошибка валидации",
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или ревьюер не найден",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "Команда уже существует",
+                        "description": "Ревьюер не назначен, PR не открыт или нет кандидатов",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key уже использован с другим запросом",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/team/get": {
-            "get": {
-                "description": "Возвращает информацию о команде и её участниках",
+        "/api/v1/pull-requests/{id}/reviews": {
+            "post": {
+                "description": "Фиксирует результат ревью: APPROVED, CHANGES_REQUESTED или COMMENTED",
                 "consumes": [
                     "application/json"
                 ],
@@ -288,23 +561,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teams"
+                    "pull-requests"
                 ],
-                "summary": "Получение информации о команде",
+                "summary": "Вердикт ревьюера",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Название команды",
-                        "name": "team_name",
-                        "in": "query",
+                        "description": "ID Pull Request",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Вердикт ревьюера",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о команде",
+                        "description": "PR с вердиктами ревьюеров",
                         "schema": {
-                            "$ref": "#/definitions/models.Team"
+                            "$ref": "#/definitions/handler.PRResponse"
                         }
                     },
                     "400": {
@@ -313,18 +595,58 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Команда не найдена",
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ревьюер не назначен или PR не открыт",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/team/{teamName}/deactivate-users": {
+        "/api/v1/teams": {
+            "get": {
+                "description": "Возвращает все команды с настройками и участниками",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Список команд",
+                "responses": {
+                    "200": {
+                        "description": "Команды",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TeamsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
             "post": {
-                "description": "Деактивирует пользователей команды и переназначает открытые PR",
+                "description": "Создает новую команду с участниками",
                 "consumes": [
                     "application/json"
                 ],
@@ -334,30 +656,23 @@ const docTemplate = `{
                 "tags": [
                     "teams"
                 ],
-                "summary": "Массовая деактивация пользователей",
+                "summary": "Создание команды",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Название команды",
-                        "name": "teamName",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Список ID пользователей для деактивации",
+                        "description": "Данные команды",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.DeactivateUsersRequest"
+                            "$ref": "#/definitions/handler.AddTeamRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Результаты деактивации",
+                    "201": {
+                        "description": "Созданная команда",
                         "schema": {
-                            "$ref": "#/definitions/handler.DeactivateUsersResponse"
+                            "$ref": "#/definitions/handler.TeamResponse"
                         }
                     },
                     "400": {
@@ -365,13 +680,112 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Команда уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/users/getReview": {
+        "/api/v1/teams/{teamName}": {
             "get": {
-                "description": "Возвращает список PR, назначенных на пользователя для ревью",
+                "description": "Возвращает команду с настройками и участниками",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Получение команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о команде",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Удаляет команду без участников",
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Удаление команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Команда удалена"
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В команде есть участники",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Меняет переданные настройки команды, остальные остаются прежними",
                 "consumes": [
                     "application/json"
                 ],
@@ -379,23 +793,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "teams"
                 ],
-                "summary": "Получение назначенных PR пользователя",
+                "summary": "Изменение настроек команды",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID пользователя",
-                        "name": "user_id",
-                        "in": "query",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Новые настройки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PatchTeamRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список PR пользователя",
+                        "description": "Обновленные настройки",
                         "schema": {
-                            "$ref": "#/definitions/handler.UserPRsResponse"
+                            "$ref": "#/definitions/handler.TeamSettingsResponse"
                         }
                     },
                     "400": {
@@ -404,18 +827,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "Команда не найдена",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/users/setIsActive": {
+        "/api/v1/teams/{teamName}/deactivate-users": {
             "post": {
-                "description": "Активирует или деактивирует пользователя",
+                "description": "Деактивирует пользователей команды и переназначает их открытые PR одной транзакцией. Ключи результата: user_id для деактивации и user_id:pr_id для каждого переназначения. Если в команде нет замены, PR остается за прежним ревьюером с success=false",
                 "consumes": [
                     "application/json"
                 ],
@@ -423,25 +857,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "teams"
                 ],
-                "summary": "Установка активности пользователя",
+                "summary": "Массовая деактивация пользователей",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Список ID пользователей для деактивации",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.SetUserActiveRequest"
+                            "$ref": "#/definitions/handler.DeactivateUsersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленный пользователь",
+                        "description": "Результаты деактивации",
                         "schema": {
-                            "$ref": "#/definitions/handler.UserResponse"
+                            "$ref": "#/definitions/handler.DeactivateUsersResponse"
                         }
                     },
                     "400": {
@@ -450,23 +891,1926 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "Пользователь не найден или не состоит в команде, изменения отменены",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
-        }
-    },
-    "definitions": {
-        "handler.AddTeamRequest": {
-            "type": "object",
-            "required": [
-                "members",
-                "team_name"
-            ],
+        },
+        "/api/v1/teams/{teamName}/leads": {
+            "get": {
+                "description": "Возвращает пользователей, которые управляют участниками и PR команды",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Лиды команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лиды команды",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TeamLeadsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/teams/{teamName}/leads/{userID}": {
+            "put": {
+                "description": "Делает пользователя лидом команды. Лид не обязан состоять в команде, повторное назначение ничего не меняет",
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Назначение лида команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Лид назначен"
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или пользователь не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Снятие лида команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Лид снят"
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не лид этой команды",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/teams/{teamName}/members": {
+            "get": {
+                "description": "Возвращает участников команды постранично по возрастанию user_id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Участники команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница участников",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TeamMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или негодный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/tokens": {
+            "get": {
+                "description": "Возвращает все токены, включая отозванные, без секретов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Список API-токенов",
+                "responses": {
+                    "200": {
+                        "description": "Токены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Выпускает токен с областями read, write и admin. Сам токен возвращается только в этом ответе, хранится его хэш",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выпуск API-токена",
+                "parameters": [
+                    {
+                        "description": "Имя и области токена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Выпущенный токен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/tokens/{id}": {
+            "delete": {
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Отзыв API-токена",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID токена",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Токен отозван"
+                    },
+                    "401": {
+                        "description": "Нет токена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Токен не найден или уже отозван",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{userID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Активирует или деактивирует пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Изменение пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PatchUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{userID}/reviews": {
+            "get": {
+                "description": "Возвращает открытые PR, назначенные на пользователя для ревью",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение назначенных PR пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список PR пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserPRsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "Возвращает изменяющие операции от новых к старым: кто (actor — subject токена, JWT или вебхука), что (operation, сущность, состояние до и после), когда и с какого адреса.\nПишутся создание, настройки и удаление команды, назначение и снятие лидов, смена активности пользователя, массовая деактивация, создание, переходы статуса, вердикты и мердж PR, замена ревьюера, выдача и отзыв API-токенов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Журнал аудита",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "team.create",
+                            "team.update_settings",
+                            "team.delete",
+                            "team.add_lead",
+                            "team.remove_lead",
+                            "user.set_active",
+                            "team.deactivate_users",
+                            "pr.create",
+                            "pr.ready",
+                            "pr.review",
+                            "pr.close",
+                            "pr.reopen",
+                            "pr.merge",
+                            "pr.replace_reviewer",
+                            "token.create",
+                            "token.revoke"
+                        ],
+                        "type": "string",
+                        "description": "Операция",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "team",
+                            "user",
+                            "pull_request",
+                            "api_token"
+                        ],
+                        "type": "string",
+                        "description": "Тип сущности",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID сущности",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Не раньше, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Раньше, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница журнала",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или негодный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена или токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Проверка работоспособности сервиса",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Статус сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/close": {
+            "post": {
+                "description": "Закрывает PR без мерджа и снимает назначенных ревьюеров",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Закрытие Pull Request",
+                "parameters": [
+                    {
+                        "description": "ID Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недопустимый переход статуса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/create": {
+            "post": {
+                "description": "Создает новый PR и автоматически назначает ревьюеров. reviewer_count переопределяет число ревьюеров команды, draft создает черновик без ревьюеров и не совместим с reviewer_count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Создание Pull Request",
+                "parameters": [
+                    {
+                        "description": "Данные Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreatePRRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом получает первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR уже существует или запрос с этим Idempotency-Key ещё выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key уже использован с другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/merge": {
+            "post": {
+                "description": "Помечает PR как мердженный, если выполнена политика мерджа команды. force с токеном области admin пропускает проверку",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Merge Pull Request",
+                "parameters": [
+                    {
+                        "description": "ID Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MergePRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав или force без области admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Политика мерджа не выполнена или PR не открыт",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/ready": {
+            "post": {
+                "description": "Переводит PR из DRAFT в OPEN и назначает ревьюеров",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Перевод черновика в ревью",
+                "parameters": [
+                    {
+                        "description": "ID Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недопустимый переход статуса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "description": "Заменяет ревьюера на PR на другого активного участника команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Замена ревьюера",
+                "parameters": [
+                    {
+                        "description": "Данные для замены ревьюера",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReassignReviewerRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом получает первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат замены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReassignReviewerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или ревьюер не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ошибка замены ревьюера",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key уже использован с другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/reopen": {
+            "post": {
+                "description": "Открывает закрытый PR и назначает ревьюеров заново",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Повторное открытие Pull Request",
+                "parameters": [
+                    {
+                        "description": "ID Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недопустимый переход статуса",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequest/review": {
+            "post": {
+                "description": "Фиксирует результат ревью: APPROVED, CHANGES_REQUESTED или COMMENTED",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Вердикт ревьюера",
+                "parameters": [
+                    {
+                        "description": "Вердикт ревьюера",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SubmitReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PR с вердиктами ревьюеров",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ревьюер не назначен или PR смерджен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/pullRequests": {
+            "get": {
+                "description": "Возвращает PR по фильтрам постранично. Курсор привязан к сортировке,\nдля другой сортировки нужно начинать с первой страницы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pull-requests"
+                ],
+                "summary": "Список PR",
+                "parameters": [
+                    {
+                        "enum": [
+                            "DRAFT",
+                            "OPEN",
+                            "MERGED",
+                            "CLOSED"
+                        ],
+                        "type": "string",
+                        "description": "Статус",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Активный ревьюер",
+                        "name": "reviewer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Команда автора",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан не раньше, RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан раньше, RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "pull_request_id",
+                            "-pull_request_id"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка, по умолчанию -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PRListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации или негодный курсор",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats/pr-metrics": {
+            "get": {
+                "description": "Возвращает общую статистику по PR",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Метрики Pull Requests",
+                "responses": {
+                    "200": {
+                        "description": "Метрики PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StatsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/stats/user-assignments": {
+            "get": {
+                "description": "Возвращает количество назначений PR на каждого пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "statistics"
+                ],
+                "summary": "Статистика назначений по пользователям",
+                "responses": {
+                    "200": {
+                        "description": "Статистика назначений",
+                        "schema": {
+                            "$ref": "#/definitions/handler.StatsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/team/add": {
+            "post": {
+                "description": "Создает новую команду с участниками",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Создание команды",
+                "parameters": [
+                    {
+                        "description": "Данные команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданная команда",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Команда уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/team/get": {
+            "get": {
+                "description": "Возвращает информацию о команде и её участниках",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Получение информации о команде",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о команде",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/team/settings": {
+            "post": {
+                "description": "Меняет стратегию выбора, число ревьюеров по умолчанию и политику мерджа команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Изменение настроек команды",
+                "parameters": [
+                    {
+                        "description": "Новые настройки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateTeamSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленные настройки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TeamSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/team/{teamName}/deactivate-users": {
+            "post": {
+                "description": "Деактивирует пользователей команды и переназначает их открытые PR одной транзакцией. Ключи результата: user_id для деактивации и user_id:pr_id для каждого переназначения. Если в команде нет замены, PR остается за прежним ревьюером с success=false",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teams"
+                ],
+                "summary": "Массовая деактивация пользователей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "teamName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Список ID пользователей для деактивации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeactivateUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результаты деактивации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeactivateUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден или не состоит в команде, изменения отменены",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "description": "Возвращает список PR, назначенных на пользователя для ревью",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение назначенных PR пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, максимум 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor из предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список PR пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserPRsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "description": "Активирует или деактивирует пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Установка активности пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SetUserActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный пользователь",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/deliveries": {
+            "get": {
+                "description": "Возвращает последние доставки подписки: статус, число попыток, код ответа и последнюю ошибку",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Журнал доставок вебхуков",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "subscription_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимум записей, по умолчанию 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доставки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/github": {
+            "post": {
+                "description": "Принимает события pull_request из GitHub. Подпись X-Hub-Signature-256 проверяется секретом GITHUB_WEBHOOK_SECRET. Поддерживаются opened, ready_for_review, closed (с мерджем и без), reopened и review_request_removed, остальные события игнорируются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Вебхук GitHub",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип события GitHub",
+                        "name": "X-GitHub-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 подпись тела запроса",
+                        "name": "X-Hub-Signature-256",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие обработано или проигнорировано",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное тело события",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверная подпись",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие противоречит состоянию PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/gitlab": {
+            "post": {
+                "description": "Принимает события Merge Request Hook из GitLab. Заголовок X-Gitlab-Token сверяется с GITLAB_WEBHOOK_TOKEN. Поддерживаются open, reopen, merge, close и update (снятие draft и снятие ревьюера), остальные события игнорируются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Вебхук GitLab",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип события GitLab",
+                        "name": "X-Gitlab-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Секретный токен вебхука",
+                        "name": "X-Gitlab-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие обработано или проигнорировано",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректное тело события",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный токен",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие противоречит состоянию PR",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/subscriptions/add": {
+            "post": {
+                "description": "Подписывает URL на события pr.created, pr.status_changed, pr.merged, reviewer.assigned и reviewer.replaced. Пустой events — все события. Тело доставки подписывается HMAC-SHA256 секретом подписки в заголовке X-Webhook-Signature. Если secret не передан, он генерируется и возвращается только в этом ответе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Создание подписки на вебхуки",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddWebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданная подписка",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/subscriptions/delete": {
+            "post": {
+                "description": "Удаляет подписку вместе с журналом доставок",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удаление подписки на вебхуки",
+                "parameters": [
+                    {
+                        "description": "ID подписки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeleteWebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка удалена"
+                    },
+                    "400": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/subscriptions/list": {
+            "get": {
+                "description": "Возвращает все подписки без секретов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Список подписок на вебхуки",
+                "responses": {
+                    "200": {
+                        "description": "Подписки",
+                        "schema": {
+                            "$ref": "#/definitions/handler.WebhookSubscriptionsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Нужна область admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "handler.AddTeamRequest": {
+            "type": "object",
+            "required": [
+                "members",
+                "team_name"
+            ],
             "properties": {
                 "members": {
                     "type": "array",
@@ -474,12 +2818,66 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.TeamMember"
                     }
                 },
+                "reviewer_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "selection_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "random",
+                        "round_robin",
+                        "least_loaded",
+                        "weighted"
+                    ],
+                    "example": "random"
+                },
                 "team_name": {
                     "type": "string",
                     "example": "backend"
                 }
             }
         },
+        "handler.AddWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "reviewer.assigned",
+                        "pr.merged"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "s3cret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://bot.example.com/hooks/review"
+                }
+            }
+        },
+        "handler.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.CreatePRRequest": {
             "type": "object",
             "required": [
@@ -492,6 +2890,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "user-456"
                 },
+                "draft": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "pull_request_id": {
                     "type": "string",
                     "example": "pr-123"
@@ -499,6 +2901,69 @@ const docTemplate = `{
                 "pull_request_name": {
                     "type": "string",
                     "example": "Fix login issue"
+                },
+                "reviewer_count": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "reviewer_id",
+                "verdict"
+            ],
+            "properties": {
+                "reviewer_id": {
+                    "type": "string",
+                    "example": "user-789"
+                },
+                "verdict": {
+                    "type": "string",
+                    "enum": [
+                        "APPROVED",
+                        "CHANGES_REQUESTED",
+                        "COMMENTED"
+                    ],
+                    "example": "APPROVED"
+                }
+            }
+        },
+        "handler.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                }
+            }
+        },
+        "handler.CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "api_token": {
+                    "$ref": "#/definitions/models.APIToken"
+                },
+                "token": {
+                    "description": "Token показывается только в этом ответе",
+                    "type": "string",
+                    "example": "ra_3q2-7wEvNbq6zW3YvQ"
                 }
             }
         },
@@ -525,13 +2990,27 @@ const docTemplate = `{
             "properties": {
                 "results": {
                     "type": "object",
-                    "additionalProperties": true
+                    "additionalProperties": {
+                        "$ref": "#/definitions/service.Reassignment"
+                    }
                 },
                 "team_name": {
                     "type": "string"
                 }
             }
         },
+        "handler.DeleteWebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "handler.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -541,6 +3020,10 @@ const docTemplate = `{
                         "code": {
                             "type": "string"
                         },
+                        "details": {
+                            "type": "object",
+                            "additionalProperties": true
+                        },
                         "message": {
                             "type": "string"
                         }
@@ -551,120 +3034,517 @@ const docTemplate = `{
         "handler.HealthResponse": {
             "type": "object",
             "properties": {
-                "service": {
-                    "type": "string"
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MergePRRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "force": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "pull_request_id": {
+                    "type": "string",
+                    "example": "pr-123"
+                }
+            }
+        },
+        "handler.PRListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequestShort"
+                    }
+                }
+            }
+        },
+        "handler.PRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "handler.PRReviewersResponse": {
+            "type": "object",
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reviewers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Review"
+                    }
+                }
+            }
+        },
+        "handler.PRStatusRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string",
+                    "example": "pr-123"
+                }
+            }
+        },
+        "handler.PatchPRRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "force": {
+                    "description": "Force пропускает политику мерджа, только для токена с областью admin",
+                    "type": "boolean",
+                    "example": false
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "OPEN",
+                        "MERGED",
+                        "CLOSED"
+                    ],
+                    "example": "MERGED"
+                }
+            }
+        },
+        "handler.PatchTeamRequest": {
+            "type": "object",
+            "properties": {
+                "block_on_changes_requested": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reviewer_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "selection_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "random",
+                        "round_robin",
+                        "least_loaded",
+                        "weighted"
+                    ],
+                    "example": "least_loaded"
+                }
+            }
+        },
+        "handler.PatchUserRequest": {
+            "type": "object",
+            "required": [
+                "is_active"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.ReassignReviewerRequest": {
+            "type": "object",
+            "required": [
+                "current_reviewer_id",
+                "pull_request_id"
+            ],
+            "properties": {
+                "current_reviewer_id": {
+                    "type": "string",
+                    "example": "user-789"
+                },
+                "pull_request_id": {
+                    "type": "string",
+                    "example": "pr-123"
+                }
+            }
+        },
+        "handler.ReassignReviewerResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "replaced_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SetUserActiveRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user-123"
+                }
+            }
+        },
+        "handler.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "pr_metrics": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "user_assignments": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.SubmitReviewRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id",
+                "reviewer_id",
+                "verdict"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string",
+                    "example": "pr-123"
+                },
+                "reviewer_id": {
+                    "type": "string",
+                    "example": "user-789"
+                },
+                "verdict": {
+                    "type": "string",
+                    "enum": [
+                        "APPROVED",
+                        "CHANGES_REQUESTED",
+                        "COMMENTED"
+                    ],
+                    "example": "APPROVED"
+                }
+            }
+        },
+        "handler.TeamLeadsResponse": {
+            "type": "object",
+            "properties": {
+                "leads": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TeamMembersResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeamMember"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TeamResponse": {
+            "type": "object",
+            "properties": {
+                "team": {
+                    "$ref": "#/definitions/models.Team"
+                }
+            }
+        },
+        "handler.TeamSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/models.TeamSettings"
                 },
-                "status": {
+                "team_name": {
                     "type": "string"
                 }
             }
         },
-        "handler.MergePRRequest": {
+        "handler.TeamsResponse": {
             "type": "object",
-            "required": [
-                "pull_request_id"
-            ],
             "properties": {
-                "pull_request_id": {
-                    "type": "string",
-                    "example": "pr-123"
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Team"
+                    }
                 }
             }
         },
-        "handler.PRResponse": {
+        "handler.TokensResponse": {
             "type": "object",
             "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIToken"
+                    }
                 }
             }
         },
-        "handler.ReassignReviewerRequest": {
+        "handler.UpdateTeamSettingsRequest": {
             "type": "object",
             "required": [
-                "old_reviewer_id",
-                "pull_request_id"
+                "team_name"
             ],
             "properties": {
-                "old_reviewer_id": {
+                "block_on_changes_requested": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reviewer_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "selection_strategy": {
                     "type": "string",
-                    "example": "user-789"
+                    "enum": [
+                        "random",
+                        "round_robin",
+                        "least_loaded",
+                        "weighted"
+                    ],
+                    "example": "least_loaded"
                 },
-                "pull_request_id": {
+                "team_name": {
                     "type": "string",
-                    "example": "pr-123"
+                    "example": "backend"
                 }
             }
         },
-        "handler.ReassignReviewerResponse": {
+        "handler.UserPRsResponse": {
             "type": "object",
             "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
+                "next_cursor": {
+                    "type": "string"
                 },
-                "replaced_by": {
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequestShort"
+                    }
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handler.SetUserActiveRequest": {
+        "handler.UserResponse": {
             "type": "object",
-            "required": [
-                "user_id"
-            ],
             "properties": {
-                "is_active": {
-                    "type": "boolean",
-                    "example": false
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "handler.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
                 },
-                "user_id": {
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string",
-                    "example": "user-123"
+                    "example": "processed"
                 }
             }
         },
-        "handler.StatsResponse": {
+        "handler.WebhookSubscriptionResponse": {
             "type": "object",
             "properties": {
-                "pr_metrics": {
-                    "type": "object",
-                    "additionalProperties": true
+                "secret": {
+                    "description": "Secret возвращается только при создании подписки",
+                    "type": "string"
                 },
-                "user_assignments": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "subscription": {
+                    "$ref": "#/definitions/models.WebhookSubscription"
                 }
             }
         },
-        "handler.TeamResponse": {
+        "handler.WebhookSubscriptionsResponse": {
             "type": "object",
             "properties": {
-                "team": {
-                    "$ref": "#/definitions/models.Team"
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookSubscription"
+                    }
                 }
             }
         },
-        "handler.UserPRsResponse": {
+        "models.APIToken": {
             "type": "object",
             "properties": {
-                "pull_requests": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.PullRequestShort"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
                 },
-                "user_id": {
+                "actor_method": {
+                    "type": "string"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "operation": {
                     "type": "string"
                 }
             }
         },
-        "handler.UserResponse": {
+        "models.Principal": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "expires_at": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lead_of": {
+                    "description": "LeadOf команды, которыми руководит пользователь",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject": {
+                    "description": "Subject устойчивый идентификатор: token:\u003cid\u003e или sub из JWT",
+                    "type": "string"
+                },
+                "token_id": {
+                    "description": "TokenID API-токен запроса",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "UserID пользователь сервиса, от имени которого пришёл запрос; пусто для API-токенов",
+                    "type": "string"
                 }
             }
         },
@@ -680,6 +3560,9 @@ const docTemplate = `{
                 "author_id": {
                     "type": "string"
                 },
+                "closedAt": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -692,6 +3575,18 @@ const docTemplate = `{
                 "pull_request_name": {
                     "type": "string"
                 },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Review"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "source_url": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 }
@@ -703,6 +3598,9 @@ const docTemplate = `{
                 "author_id": {
                     "type": "string"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
                 },
@@ -714,15 +3612,45 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Review": {
+            "type": "object",
+            "properties": {
+                "assignedAt": {
+                    "type": "string"
+                },
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Team": {
             "type": "object",
             "properties": {
+                "block_on_changes_requested": {
+                    "type": "boolean"
+                },
                 "members": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.TeamMember"
                     }
                 },
+                "required_approvals": {
+                    "description": "политика мерджа",
+                    "type": "integer"
+                },
+                "reviewer_count": {
+                    "type": "integer"
+                },
+                "selection_strategy": {
+                    "type": "string"
+                },
                 "team_name": {
                     "type": "string"
                 }
@@ -742,6 +3670,24 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TeamSettings": {
+            "type": "object",
+            "properties": {
+                "block_on_changes_requested": {
+                    "type": "boolean"
+                },
+                "required_approvals": {
+                    "description": "политика мерджа",
+                    "type": "integer"
+                },
+                "reviewer_count": {
+                    "type": "integer"
+                },
+                "selection_strategy": {
+                    "type": "string"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -764,6 +3710,87 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.Reassignment": {
+            "type": "object",
+            "properties": {
+                "new_reviewer": {
+                    "type": "string"
+                },
+                "old_reviewer": {
+                    "type": "string"
+                },
+                "pr_id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
         }
     },
     "securityDefinitions": {
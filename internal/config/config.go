@@ -1,18 +1,93 @@
 package config
 
-import "os"
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// хранилища данных
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
 
 type Config struct {
+	// Storage хранилище: postgres, sqlite или memory (данные в памяти процесса, для тестов и демо)
+	Storage     string
 	DatabaseURL string
+	// SQLitePath файл базы для STORAGE=sqlite
+	SQLitePath  string
 	ServerPort  string
 	Environment string
+	// AdminToken первый токен области admin, регистрируется при старте сервиса
+	AdminToken string
+	// GitHubWebhookSecret секрет для проверки подписи вебхуков GitHub
+	GitHubWebhookSecret string
+	// GitLabWebhookToken секретный токен вебхуков GitLab (заголовок X-Gitlab-Token)
+	GitLabWebhookToken string
+	// IdempotencyTTL сколько хранится ответ на запрос с заголовком Idempotency-Key
+	IdempotencyTTL time.Duration
+	// IdempotencyLease сколько запрос держит ключ идемпотентности, после этого ключ занимает повтор
+	IdempotencyLease time.Duration
+	// JWKS файл или http(s)-адрес ключей SSO; пусто — JWT не принимаются
+	JWKS string
+	// JWTIssuer и JWTAudience ожидаемые iss и aud токенов SSO
+	JWTIssuer   string
+	JWTAudience string
+	// JWTGroupsClaim утверждение JWT со списком групп
+	JWTGroupsClaim string
+	// JWTGroupScopes область доступа для групп SSO, JWT_GROUP_SCOPES="platform:admin,dev:write"
+	JWTGroupScopes map[string]string
+	// JWTDefaultScope область любого действительного JWT
+	JWTDefaultScope string
+	// TrustedProxies адреса и подсети прокси, которым доверяется X-Forwarded-For;
+	// пусто — адрес клиента берётся из соединения
+	TrustedProxies []string
+	// RateLimitRead и RateLimitWrite запросов в секунду на клиента для чтения (GET)
+	// и изменений, 0 — без лимита; Burst — сколько запросов клиент может сделать подряд
+	RateLimitRead       float64
+	RateLimitReadBurst  int
+	RateLimitWrite      float64
+	RateLimitWriteBurst int
+	// RateLimitAuthFailures ответов 401 в секунду на IP, 0 — без лимита; Burst —
+	// сколько неудачных попыток подряд допускается до 429
+	RateLimitAuthFailures      float64
+	RateLimitAuthFailuresBurst int
+	// MaxBodyBytes предельный размер тела запроса, 0 — без ограничения
+	MaxBodyBytes int64
 }
 
 func Load() *Config {
 	return &Config{
-		DatabaseURL: getDatabaseURL(),
-		ServerPort:  getEnv("SERVER_PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Storage:             getEnv("STORAGE", StoragePostgres),
+		DatabaseURL:         getDatabaseURL(),
+		SQLitePath:          getEnv("SQLITE_PATH", "review-assigner.db"),
+		ServerPort:          getEnv("SERVER_PORT", "8080"),
+		Environment:         getEnv("ENVIRONMENT", "development"),
+		AdminToken:          os.Getenv("ADMIN_TOKEN"),
+		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitLabWebhookToken:  os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+		IdempotencyTTL:      getDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLease:    getDuration("IDEMPOTENCY_LEASE", time.Minute),
+		JWKS:                os.Getenv("JWKS_URL"),
+		JWTIssuer:           os.Getenv("JWT_ISSUER"),
+		JWTAudience:         os.Getenv("JWT_AUDIENCE"),
+		JWTGroupsClaim:      getEnv("JWT_GROUPS_CLAIM", "groups"),
+		JWTGroupScopes:      getMap("JWT_GROUP_SCOPES"),
+		JWTDefaultScope:     getEnv("JWT_DEFAULT_SCOPE", "write"),
+		TrustedProxies:      getList("TRUSTED_PROXIES"),
+		RateLimitRead:       getFloat("RATE_LIMIT_READ", 20),
+		RateLimitReadBurst:  int(getInt("RATE_LIMIT_READ_BURST", 40)),
+		RateLimitWrite:      getFloat("RATE_LIMIT_WRITE", 5),
+		RateLimitWriteBurst: int(getInt("RATE_LIMIT_WRITE_BURST", 10)),
+		MaxBodyBytes:        getInt("MAX_BODY_BYTES", 1<<20),
+
+		RateLimitAuthFailures:      getFloat("RATE_LIMIT_AUTH_FAILURES", 0.2),
+		RateLimitAuthFailuresBurst: int(getInt("RATE_LIMIT_AUTH_FAILURES_BURST", 5)),
 	}
 }
 
@@ -30,3 +105,73 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDuration читает длительность в формате time.ParseDuration ("24h", "30m")
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid %s=%q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
+// getFloat читает неотрицательное число, например 0.5
+func getFloat(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		log.Printf("Invalid %s=%q, using %v", key, value, defaultValue)
+		return defaultValue
+	}
+	return f
+}
+
+// getInt читает неотрицательное целое
+func getInt(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		log.Printf("Invalid %s=%q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getMap читает пары "ключ:значение" через запятую
+func getMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, ":")
+		if !ok {
+			log.Printf("Invalid %s entry %q, expected key:value", key, pair)
+			continue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+// getList читает значения через запятую
+func getList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,2 @@
+-- стратегия выбора ревьюеров для команды
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS selection_strategy VARCHAR(32) NOT NULL DEFAULT 'random';
@@ -0,0 +1,2 @@
+-- число ревьюеров на PR по умолчанию для команды
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewer_count INT NOT NULL DEFAULT 2 CHECK (reviewer_count > 0);
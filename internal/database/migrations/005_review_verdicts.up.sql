@@ -0,0 +1,5 @@
+-- вердикт ревьюера по назначению
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ NULL;
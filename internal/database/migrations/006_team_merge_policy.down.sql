@@ -0,0 +1,3 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS block_on_changes_requested,
+    DROP COLUMN IF EXISTS required_approvals;
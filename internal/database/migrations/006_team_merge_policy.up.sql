@@ -0,0 +1,4 @@
+-- политика мерджа: минимум одобрений и блокировка при запрошенных изменениях
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0),
+    ADD COLUMN IF NOT EXISTS block_on_changes_requested BOOLEAN NOT NULL DEFAULT false;
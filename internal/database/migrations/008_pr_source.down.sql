@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS source_url,
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+-- источник PR: api, github, gitlab
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'api',
+    ADD COLUMN IF NOT EXISTS source_url VARCHAR(512) NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+-- outbox доменных событий: пишется в одной транзакции с изменением PR,
+-- published_at заполняет диспетчер после доставки во все sink'и
+CREATE TABLE IF NOT EXISTS events (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    published_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_unpublished ON events(id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_aggregate ON events(aggregate_id);
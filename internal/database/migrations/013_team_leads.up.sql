@@ -0,0 +1,9 @@
+-- лиды команд: управляют участниками своей команды и её PR. Лид может не состоять в команде
+CREATE TABLE IF NOT EXISTS team_leads (
+    team_name VARCHAR(100) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    user_id VARCHAR(50) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_leads_user ON team_leads(user_id);
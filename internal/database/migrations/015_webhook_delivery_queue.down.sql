@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP INDEX IF EXISTS ux_webhook_deliveries_event;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,11 @@
+-- доставки вебхуков становятся очередью: повторы выполняет опрос строк
+-- с наступившим next_attempt_at, а не процесс, создавший доставку.
+-- event_id делает запись доставки идемпотентной при повторе события из outbox
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS event_id BIGINT NULL;
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NULL;
+
+-- доставки, оставшиеся в PENDING до обновления, отправляются при первом опросе
+UPDATE webhook_deliveries SET next_attempt_at = created_at WHERE status = 'PENDING';
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_webhook_deliveries_event ON webhook_deliveries(subscription_id, event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
@@ -0,0 +1,8 @@
+-- участник ссылается на свою команду: удаление команды, в которую
+-- параллельно добавили участника, падает на внешнем ключе.
+-- команды пользователей, заведённых до появления ключа, создаются заново
+INSERT INTO teams (team_name)
+SELECT DISTINCT team_name FROM users
+ON CONFLICT (team_name) DO NOTHING;
+
+ALTER TABLE users ADD CONSTRAINT fk_users_team FOREIGN KEY (team_name) REFERENCES teams(team_name);
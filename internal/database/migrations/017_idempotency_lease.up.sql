@@ -0,0 +1,6 @@
+-- запрос держит ключ идемпотентности до locked_until и продлевать его не может:
+-- ключ упавшего запроса после этого занимает повтор с тем же телом, не дожидаясь expires_at
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL;
+
+-- незавершённые запросы, начатые до обновления, считаются упавшими
+UPDATE idempotency_keys SET locked_until = created_at WHERE status_code = 0;
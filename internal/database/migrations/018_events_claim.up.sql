@@ -0,0 +1,4 @@
+-- диспетчер забирает пачку событий, продлевая claimed_until: параллельный
+-- экземпляр сервиса пропускает занятые события до истечения срока.
+-- Событие упавшего экземпляра публикуется снова после claimed_until
+ALTER TABLE events ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ NULL;
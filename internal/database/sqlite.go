@@ -0,0 +1,81 @@
+package database
+
+import (
+	"database/sql/driver"
+	"embed"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+)
+
+//go:embed sqlite_migrations/*.sql
+var sqliteFS embed.FS
+
+// формат времени в SQLite: фиксированная ширина, чтобы строки сортировались как время
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000000-07:00"
+
+var registerNow sync.Once
+
+// NewSQLiteDB открывает файл SQLite (":memory:" — база в памяти) и применяет миграции.
+// Репозитории те же, что и для postgres: SQL в них совместим с обеими базами.
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	var err error
+	registerNow.Do(func() {
+		// в SQLite нет NOW(), а репозитории используют его в запросах
+		err = sqlite.RegisterScalarFunction("now", 0,
+			func(_ *sqlite.FunctionContext, _ []driver.Value) (driver.Value, error) {
+				return time.Now().UTC().Format(sqliteTimeFormat), nil
+			})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register NOW() for sqlite: %w", err)
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+	db, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite допускает одного писателя, а ":memory:" живёт в одном соединении
+	db.SetMaxOpenConns(1)
+
+	if err := runSQLiteMigrations(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	log.Printf("Successfully opened SQLite database %s", path)
+	return db, nil
+}
+
+func runSQLiteMigrations(db *sqlx.DB) error {
+	d, err := iofs.New(sqliteFS, "sqlite_migrations")
+	if err != nil {
+		return fmt.Errorf("failed to create migration source: %w", err)
+	}
+
+	driver, err := migratesqlite.WithInstance(db.DB, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", d, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	log.Println("Migrations completed successfully")
+	return nil
+}
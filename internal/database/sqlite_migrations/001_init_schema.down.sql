@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,102 @@
+-- схема SQLite: итоговое состояние миграций postgres 001-010.
+-- Время хранится текстом, поэтому колонки объявлены как TIMESTAMP — так драйвер
+-- отдаёт их как time.Time. NOW() регистрируется драйвером в database.NewSQLiteDB.
+
+-- users
+CREATE TABLE IF NOT EXISTS users (
+    user_id VARCHAR(50) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    team_name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- teams
+CREATE TABLE IF NOT EXISTS teams (
+    team_name VARCHAR(100) PRIMARY KEY,
+    selection_strategy VARCHAR(32) NOT NULL DEFAULT 'random',
+    reviewer_count INT NOT NULL DEFAULT 2 CHECK (reviewer_count > 0),
+    required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0),
+    block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- pull_requests
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id VARCHAR(50) PRIMARY KEY,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(20) DEFAULT 'OPEN' CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED')),
+    source VARCHAR(20) NOT NULL DEFAULT 'api',
+    source_url VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    merged_at TIMESTAMP NULL,
+    closed_at TIMESTAMP NULL
+);
+
+-- pr_reviewers
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id VARCHAR(50) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(50) NOT NULL REFERENCES users(user_id),
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    replaced_at TIMESTAMP NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    reviewed_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active);
+CREATE INDEX IF NOT EXISTS idx_pr_author_id ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_pr_id ON pr_reviewers(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_reviewer_id ON pr_reviewers(reviewer_id);
+-- уникальный индекс для пар (pr, reviewer) когда запись активна.
+-- Условие должно совпадать с ON CONFLICT ... WHERE в репозитории дословно, иначе SQLite не найдёт индекс
+CREATE UNIQUE INDEX IF NOT EXISTS unique_active_reviewer ON pr_reviewers(pull_request_id, reviewer_id) WHERE is_active = true;
+
+-- подписки на исходящие вебхуки; events — список событий через запятую, пустой список означает все события
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url VARCHAR(512) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- журнал доставок вебхуков
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscription_id INT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    response_code INT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id);
+
+-- outbox доменных событий
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_unpublished ON events(id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_aggregate ON events(aggregate_id);
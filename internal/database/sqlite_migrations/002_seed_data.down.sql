@@ -0,0 +1,4 @@
+DELETE FROM pr_reviewers;
+DELETE FROM pull_requests;
+DELETE FROM users;
+DELETE FROM teams;
@@ -0,0 +1,3 @@
+UPDATE pull_requests
+SET created_at = substr(created_at, 1, 19)
+WHERE created_at LIKE '%.000000000+00:00';
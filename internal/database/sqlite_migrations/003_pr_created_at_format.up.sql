@@ -0,0 +1,6 @@
+-- сид PR получил created_at из CURRENT_TIMESTAMP без долей секунды и зоны,
+-- а NOW() пишет фиксированную ширину; курсоры списка PR сравнивают строки,
+-- поэтому приводим старые значения к формату NOW()
+UPDATE pull_requests
+SET created_at = created_at || '.000000000+00:00'
+WHERE length(created_at) = 19;
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS trg_teams_delete_members;
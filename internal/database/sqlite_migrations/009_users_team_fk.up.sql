@@ -0,0 +1,8 @@
+-- SQLite не добавляет внешний ключ к существующей таблице, поэтому
+-- команду с участниками не даёт удалить триггер
+CREATE TRIGGER IF NOT EXISTS trg_teams_delete_members
+BEFORE DELETE ON teams
+WHEN EXISTS (SELECT 1 FROM users WHERE team_name = OLD.team_name)
+BEGIN
+    SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: team has members');
+END;
@@ -0,0 +1,266 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// репозитории postgres должны работать на SQLite без изменений
+func TestSQLite_Repositories(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	prRepo := repository.NewPRRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+
+	// данные из 002_seed_data
+	load, err := prRepo.GetOpenReviewLoad(ctx, []string{"u2", "u3", "u5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"u2": 2, "u3": 2}, load)
+
+	require.NoError(t, prRepo.CreatePR(ctx, &models.PullRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "SQLite",
+		AuthorID:        "u1",
+		Status:          models.PRStatusDraft,
+	}))
+	require.NoError(t, prRepo.TransitionPR(ctx, "pr-1", models.PRStatusDraft, models.PRStatusOpen))
+	require.NoError(t, prRepo.AddPRReviewers(ctx, "pr-1", []string{"u2", "u3"}))
+	require.NoError(t, prRepo.SetReviewState(ctx, "pr-1", "u2", models.ReviewStateApproved))
+	require.NoError(t, prRepo.ReplacePRReviewer(ctx, "pr-1", "u3", "u6"))
+
+	pr, err := prRepo.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusOpen, pr.Status)
+	assert.Nil(t, pr.ClosedAt)
+	require.NotNil(t, pr.CreatedAt)
+	assert.ElementsMatch(t, []string{"u2", "u6"}, pr.AssignedReviewers)
+
+	require.NoError(t, prRepo.TransitionPR(ctx, "pr-1", models.PRStatusOpen, models.PRStatusClosed))
+	pr, err = prRepo.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.NotNil(t, pr.ClosedAt)
+
+	events, err := eventRepo.GetPendingEvents(ctx, 10, 10)
+	require.NoError(t, err)
+	types := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.EventType
+	}
+	assert.Equal(t, []string{
+		models.EventPRCreated,
+		models.EventPRStatusChanged,
+		models.EventReviewerAssigned,
+		models.EventReviewerReplaced,
+		models.EventPRStatusChanged,
+	}, types)
+
+	require.NoError(t, eventRepo.MarkEventPublished(ctx, events[0].ID))
+	events, err = eventRepo.GetPendingEvents(ctx, 10, 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+}
+
+func TestSQLite_WebhookCascade(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewWebhookRepository(db)
+
+	sub := &models.WebhookSubscription{URL: "https://example.com/hook", Secret: "s", IsActive: true}
+	require.NoError(t, repo.CreateSubscription(ctx, sub))
+	assert.NotZero(t, sub.ID)
+	require.NotNil(t, sub.CreatedAt)
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventType:      models.EventPRMerged,
+		Payload:        `{}`,
+		Status:         models.DeliveryStatusPending,
+	}
+	require.NoError(t, repo.CreateDelivery(ctx, delivery))
+
+	require.NoError(t, repo.DeleteSubscription(ctx, sub.ID))
+	deliveries, err := repo.GetDeliveries(ctx, sub.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries, "доставки удаляются вместе с подпиской")
+}
+
+func TestSQLite_DeleteTeamWithMembers(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTeamRepository(db)
+
+	// в backend из 002_seed_data есть участники
+	err = repo.DeleteTeam(ctx, "backend")
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	exists, err := repo.TeamExists(ctx, "backend")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, repo.CreateTeam(ctx, "empty"))
+	require.NoError(t, repo.DeleteTeam(ctx, "empty"))
+}
+
+func TestSQLite_WebhookDeliveryQueue(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewWebhookRepository(db)
+
+	sub := &models.WebhookSubscription{URL: "https://example.com/hook", Secret: "s", IsActive: true}
+	require.NoError(t, repo.CreateSubscription(ctx, sub))
+
+	eventID := int64(11)
+	due := time.Now().Add(-time.Second)
+	newDelivery := func() *models.WebhookDelivery {
+		return &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        &eventID,
+			EventType:      models.EventPRMerged,
+			Payload:        `{}`,
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  &due,
+		}
+	}
+	require.NoError(t, repo.CreateDelivery(ctx, newDelivery()))
+	assert.ErrorIs(t, repo.CreateDelivery(ctx, newDelivery()), repository.ErrConflict,
+		"повтор события не создаёт вторую доставку")
+
+	lease := time.Now().Add(time.Minute)
+	claimed, err := repo.ClaimDueDeliveries(ctx, 10, lease)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NotNil(t, claimed[0].NextAttemptAt)
+	assert.WithinDuration(t, lease, *claimed[0].NextAttemptAt, time.Millisecond)
+
+	claimed, err = repo.ClaimDueDeliveries(ctx, 10, lease)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "забранная доставка ждёт окончания аренды")
+
+	delivery := newDelivery()
+	delivery.ID = 1
+	delivery.Attempts = 1
+	delivery.NextAttemptAt = &due
+	require.NoError(t, repo.UpdateDelivery(ctx, delivery))
+	claimed, err = repo.ClaimDueDeliveries(ctx, 10, lease)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1, "неудачная попытка возвращает доставку в очередь")
+}
+
+func TestSQLite_AuditLogAppendOnly(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewAuditRepository(db)
+
+	entry := &models.AuditEntry{
+		Actor:      "token:1",
+		Operation:  models.AuditTeamCreated,
+		EntityType: models.AuditEntityTeam,
+		EntityID:   "backend",
+		After:      []byte(`{"team_name":"backend"}`),
+	}
+	require.NoError(t, repo.AppendAudit(ctx, entry))
+
+	from := entry.CreatedAt.Add(-time.Minute)
+	entries, _, err := repo.ListAudit(ctx, repository.AuditFilter{From: &from}, repository.Page{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, `{"team_name":"backend"}`, string(entries[0].After))
+
+	_, err = db.ExecContext(ctx, `UPDATE audit_log SET actor = 'someone-else'`)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = db.ExecContext(ctx, `DELETE FROM audit_log`)
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestSQLite_IdempotencyTakeOver(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	repo := repository.NewIdempotencyRepository(db)
+
+	now := time.Now()
+	held := now.Add(time.Minute)
+	rec := &models.IdempotencyRecord{
+		Key: "key-1", Scope: "scope", RequestHash: "hash",
+		ExpiresAt: now.Add(time.Hour), LockedUntil: &held,
+	}
+	require.NoError(t, repo.ReserveKey(ctx, rec))
+	assert.ErrorIs(t, repo.TakeOverKey(ctx, rec), repository.ErrConflict, "ключ ещё занят")
+
+	stalled := now.Add(-time.Second)
+	rec.Key = "key-2"
+	rec.LockedUntil = &stalled
+	require.NoError(t, repo.ReserveKey(ctx, rec))
+
+	rec.LockedUntil = &held
+	require.NoError(t, repo.TakeOverKey(ctx, rec))
+	saved, err := repo.GetKey(ctx, "key-2", "scope")
+	require.NoError(t, err)
+	require.NotNil(t, saved.LockedUntil)
+	assert.True(t, saved.LockedUntil.After(now))
+	assert.ErrorIs(t, repo.TakeOverKey(ctx, rec), repository.ErrConflict, "повтор держит ключ")
+}
+
+func TestSQLite_ClaimPendingEvents(t *testing.T) {
+	db, err := NewSQLiteDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	prRepo := repository.NewPRRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		require.NoError(t, prRepo.CreatePR(ctx, &models.PullRequest{
+			PullRequestID: id, PullRequestName: id, AuthorID: "u1", Status: models.PRStatusOpen,
+		}))
+	}
+
+	claimed, err := eventRepo.ClaimPendingEvents(ctx, 1, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	// занятое событие другой диспетчер пропускает
+	rest, err := eventRepo.ClaimPendingEvents(ctx, 10, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Greater(t, rest[0].ID, claimed[0].ID)
+
+	none, err := eventRepo.ClaimPendingEvents(ctx, 10, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	// после ошибки событие снова свободно, как и после истечения срока
+	require.NoError(t, eventRepo.MarkEventFailed(ctx, claimed[0].ID, "sink down"))
+	again, err := eventRepo.ClaimPendingEvents(ctx, 10, 10, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, claimed[0].ID, again[0].ID)
+
+	expired, err := eventRepo.ClaimPendingEvents(ctx, 10, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, expired, 1, "срок истёк")
+}
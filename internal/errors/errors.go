@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+)
 
 var (
 	ErrPRNotFound     = NewError("NOT_FOUND", "PR not found")
@@ -12,11 +15,39 @@ var (
 	ErrNotAssigned    = NewError("NOT_ASSIGNED", "Reviewer not assigned to this PR")
 	ErrNoCandidate    = NewError("NO_CANDIDATE", "No active replacement candidate in team")
 	ErrAuthorNotFound = NewError("NOT_FOUND", "Author not found")
+
+	ErrInvalidReviewerCount = NewError("INVALID_REQUEST", "Reviewer count is out of allowed range")
+	ErrInvalidVerdict       = NewError("INVALID_REQUEST", "Invalid review verdict")
+	ErrDraftReviewerCount   = NewError("INVALID_REQUEST", "reviewer_count cannot be set for a draft PR")
+	ErrReviewOnMerged       = NewError("PR_MERGED", "Cannot review merged PR")
+	ErrInvalidMergePolicy   = NewError("INVALID_REQUEST", "Invalid merge policy")
+	ErrMergeBlocked         = NewError("MERGE_BLOCKED", "Merge requirements not satisfied")
+	ErrForbidden            = NewError("FORBIDDEN", "Operation not permitted")
+	ErrInvalidTransition    = NewError("INVALID_TRANSITION", "Illegal PR status transition")
+	ErrPRNotOpen            = NewError("PR_NOT_OPEN", "PR is not open")
+	ErrInvalidWebhook       = NewError("INVALID_REQUEST", "Invalid webhook subscription")
+	ErrWebhookNotFound      = NewError("NOT_FOUND", "Webhook subscription not found")
+	ErrInvalidSignature     = NewError("UNAUTHORIZED", "Webhook signature or token is invalid")
+	ErrServiceUnavailable   = NewError("SERVICE_UNAVAILABLE", "Storage is temporarily unavailable")
+	ErrTeamNotEmpty         = NewError("TEAM_NOT_EMPTY", "Team still has members")
+	ErrInvalidCursor        = NewError("INVALID_REQUEST", "Invalid page cursor")
+	ErrInvalidSort          = NewError("INVALID_REQUEST", "Unknown sort key")
+	ErrIdempotencyKeyReused = NewError("IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request")
+	ErrIdempotencyInFlight  = NewError("IDEMPOTENCY_KEY_IN_USE", "Request with this Idempotency-Key is still in progress")
+	ErrUnauthorized         = NewError("UNAUTHORIZED", "Missing or invalid API token")
+	ErrTokenExpired         = NewError("UNAUTHORIZED", "Token has expired")
+	ErrTokenNotFound        = NewError("NOT_FOUND", "API token not found")
+	ErrLeadNotFound         = NewError("NOT_FOUND", "User is not a lead of this team")
+	ErrInvalidScope         = NewError("INVALID_REQUEST", "Unknown API token scope")
+	ErrRateLimited          = NewError("RATE_LIMITED", "Too many requests")
+	ErrBodyTooLarge         = NewError("PAYLOAD_TOO_LARGE", "Request body is too large")
 )
 
 type Error struct {
 	Code    string
 	Message string
+	// Details уточнения для клиента: поле запроса, id PR и т.п.
+	Details map[string]interface{}
 	Cause   error
 }
 
@@ -27,6 +58,34 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap отдаёт причину стандартным errors.Is/As
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is сравнивает доменные ошибки по коду, поэтому errors.Is(err, ErrPRNotFound)
+// срабатывает и для обёрнутых копий с другой причиной или деталями
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails возвращает копию ошибки с добавленными деталями
+func (e *Error) WithDetails(key string, value interface{}) *Error {
+	details := make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
+	}
+	details[key] = value
+
+	return &Error{
+		Code:    e.Code,
+		Message: e.Message,
+		Details: details,
+		Cause:   e.Cause,
+	}
+}
+
 func NewError(code, message string) *Error {
 	return &Error{
 		Code:    code,
@@ -38,13 +97,21 @@ func WrapError(err *Error, cause error) *Error {
 	return &Error{
 		Code:    err.Code,
 		Message: err.Message,
+		Details: err.Details,
 		Cause:   cause,
 	}
 }
 
+// Is проверяет, есть ли в цепочке err доменная ошибка с кодом target
 func Is(err error, target *Error) bool {
-	if domainErr, ok := err.(*Error); ok {
-		return domainErr.Code == target.Code
+	return stderrors.Is(err, target)
+}
+
+// As ищет в цепочке err доменную ошибку
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if stderrors.As(err, &domainErr) {
+		return domainErr, true
 	}
-	return false
+	return nil, false
 }
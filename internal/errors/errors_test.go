@@ -0,0 +1,38 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_Chain(t *testing.T) {
+	cause := stderrors.New("connection reset")
+	err := fmt.Errorf("failed to assign reviewers: %w", WrapError(ErrNoCandidate, cause))
+
+	assert.True(t, Is(err, ErrNoCandidate))
+	assert.True(t, stderrors.Is(err, ErrNoCandidate))
+	assert.False(t, Is(err, ErrPRNotFound))
+	assert.ErrorIs(t, err, cause, "причина доступна через Unwrap")
+
+	domainErr, ok := As(err)
+	require.True(t, ok)
+	assert.Equal(t, "NO_CANDIDATE", domainErr.Code)
+
+	_, ok = As(cause)
+	assert.False(t, ok)
+}
+
+func TestError_WithDetails(t *testing.T) {
+	err := ErrNotAssigned.WithDetails("pull_request_id", "pr-1").WithDetails("reviewer_id", "u2")
+
+	assert.Equal(t, map[string]interface{}{"pull_request_id": "pr-1", "reviewer_id": "u2"}, err.Details)
+	assert.Nil(t, ErrNotAssigned.Details, "общая ошибка не меняется")
+	assert.True(t, Is(err, ErrNotAssigned))
+
+	wrapped := WrapError(err, stderrors.New("no rows"))
+	assert.Equal(t, err.Details, wrapped.Details)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAudit godoc
+// @Summary Журнал аудита
+// @Description Возвращает изменяющие операции от новых к старым: кто (actor — subject токена, JWT или вебхука), что (operation, сущность, состояние до и после), когда и с какого адреса.
+// @Description Пишутся создание, настройки и удаление команды, назначение и снятие лидов, смена активности пользователя, массовая деактивация, создание, переходы статуса, вердикты и мердж PR, замена ревьюера, выдача и отзыв API-токенов
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param actor query string false "Автор изменения" example:token:1
+// @Param operation query string false "Операция" Enums(team.create, team.update_settings, team.delete, team.add_lead, team.remove_lead, user.set_active, team.deactivate_users, pr.create, pr.ready, pr.review, pr.close, pr.reopen, pr.merge, pr.replace_reviewer, token.create, token.revoke)
+// @Param entity_type query string false "Тип сущности" Enums(team, user, pull_request, api_token)
+// @Param entity_id query string false "ID сущности" example:pr-1006
+// @Param from query string false "Не раньше, RFC 3339" example:2025-01-01T00:00:00Z
+// @Param to query string false "Раньше, RFC 3339" example:2025-02-01T00:00:00Z
+// @Param limit query int false "Размер страницы, по умолчанию 50, максимум 200" example:50
+// @Param cursor query string false "next_cursor из предыдущего ответа"
+// @Success 200 {object} AuditLogResponse "Страница журнала"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации или негодный курсор"
+// @Failure 401 {object} ErrorResponse "Нет токена или токен недействителен"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Router /audit [get]
+// @Router /api/v1/audit [get]
+func (h *Handler) listAudit(c *gin.Context) {
+	var query AuditQuery
+	if !validateQuery(c, &query) {
+		return
+	}
+
+	entries, next, err := h.auditService.ListAudit(c.Request.Context(), query.filter(), query.page())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditLogResponse{
+		Entries:    entries,
+		NextCursor: next,
+	})
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey ключ контекста gin с автором запроса
+const principalKey = "principal"
+
+// legacyAdminTokenHeader прежний заголовок административного токена,
+// принимается наравне с Authorization: Bearer
+const legacyAdminTokenHeader = "X-Admin-Token"
+
+type CreateTokenRequest struct {
+	Name   string   `json:"name" binding:"required" example:"ci"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=read write admin" example:"read,write"`
+}
+
+type CreateTokenResponse struct {
+	// Token показывается только в этом ответе
+	Token    string           `json:"token" example:"ra_3q2-7wEvNbq6zW3YvQ"`
+	APIToken *models.APIToken `json:"api_token"`
+}
+
+type TokensResponse struct {
+	Tokens []models.APIToken `json:"tokens"`
+}
+
+// bearerToken достаёт API-токен или JWT из Authorization: Bearer или из X-Admin-Token
+func bearerToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return c.GetHeader(legacyAdminTokenHeader)
+}
+
+// authorize пропускает запрос только с действующим API-токеном или JWT, у которого
+// есть scope; пустой scope — любой аутентифицированный запрос.
+// Без токена, с неизвестным или просроченным — 401, с недостаточными правами — 403.
+func (h *Handler) authorize(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := bearerToken(c)
+		if secret == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="review-assigner"`)
+			handleError(c, errors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		principal, err := h.authService.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			if errors.Is(err, errors.ErrUnauthorized) {
+				c.Header("WWW-Authenticate", `Bearer realm="review-assigner", error="invalid_token"`)
+			}
+			handleError(c, err)
+			c.Abort()
+			return
+		}
+		if scope != "" && !principal.HasScope(scope) {
+			handleError(c, errors.ErrForbidden.WithDetails("required_scope", scope))
+			c.Abort()
+			return
+		}
+
+		c.Set(principalKey, principal)
+		// автор и адрес изменений для журнала аудита
+		c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), models.Actor{
+			Subject: principal.Subject,
+			Name:    principal.Name,
+			Method:  principal.Method,
+			IP:      c.ClientIP(),
+		}))
+		c.Next()
+	}
+}
+
+// currentPrincipal автор запроса; nil на открытых маршрутах
+func currentPrincipal(c *gin.Context) *models.Principal {
+	value, _ := c.Get(principalKey)
+	principal, _ := value.(*models.Principal)
+	return principal
+}
+
+// allowed отвечает ошибкой, если политика доступа отказала: 403 или 404,
+// когда объекта проверки нет
+func allowed(c *gin.Context, err error) bool {
+	if err != nil {
+		handleError(c, err)
+		return false
+	}
+	return true
+}
+
+// GetMe godoc
+// @Summary Кто я
+// @Description Возвращает автора запроса: API-токен или пользователя SSO с его группами и областями доступа
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Principal "Автор запроса"
+// @Failure 401 {object} ErrorResponse "Нет токена, токен недействителен или просрочен"
+// @Router /api/v1/me [get]
+func (h *Handler) getMe(c *gin.Context) {
+	c.JSON(http.StatusOK, currentPrincipal(c))
+}
+
+// CreateToken godoc
+// @Summary Выпуск API-токена
+// @Description Выпускает токен с областями read, write и admin. Сам токен возвращается только в этом ответе, хранится его хэш
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTokenRequest true "Имя и области токена" example:{"name":"ci","scopes":["read","write"]}
+// @Success 201 {object} CreateTokenResponse "Выпущенный токен"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 401 {object} ErrorResponse "Нет токена"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Router /api/v1/tokens [post]
+func (h *Handler) createToken(c *gin.Context) {
+	var request CreateTokenRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	secret, token, err := h.authService.CreateToken(c.Request.Context(), request.Name, request.Scopes)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateTokenResponse{
+		Token:    secret,
+		APIToken: token,
+	})
+}
+
+// ListTokens godoc
+// @Summary Список API-токенов
+// @Description Возвращает все токены, включая отозванные, без секретов
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TokensResponse "Токены"
+// @Failure 401 {object} ErrorResponse "Нет токена"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Router /api/v1/tokens [get]
+func (h *Handler) listTokens(c *gin.Context) {
+	tokens, err := h.authService.ListTokens(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokensResponse{Tokens: tokens})
+}
+
+// RevokeToken godoc
+// @Summary Отзыв API-токена
+// @Tags auth
+// @Security BearerAuth
+// @Param id path int true "ID токена" example:1
+// @Success 204 "Токен отозван"
+// @Failure 401 {object} ErrorResponse "Нет токена"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Failure 404 {object} ErrorResponse "Токен не найден или уже отозван"
+// @Router /api/v1/tokens/{id} [delete]
+func (h *Handler) revokeToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		handleError(c, errors.NewError("INVALID_REQUEST", "token id must be an integer"))
+		return
+	}
+
+	if err := h.authService.RevokeToken(c.Request.Context(), id); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -69,15 +69,19 @@ type DeactivateUsersRequest struct {
 
 // DeactivateUsers godoc
 // @Summary Массовая деактивация пользователей
-// @Description Деактивирует пользователей команды и переназначает открытые PR
+// @Description Деактивирует пользователей команды и переназначает их открытые PR одной транзакцией. Ключи результата: user_id для деактивации и user_id:pr_id для каждого переназначения. Если в команде нет замены, PR остается за прежним ревьюером с success=false
 // @Tags teams
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param teamName path string true "Название команды" example:backend
 // @Param request body DeactivateUsersRequest true "Список ID пользователей для деактивации" example:{"user_ids":["user-123","user-456"]}
 // @Success 200 {object} DeactivateUsersResponse "Результаты деактивации"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден или не состоит в команде, изменения отменены"
 // @Router /team/{teamName}/deactivate-users [post]
+// @Router /api/v1/teams/{teamName}/deactivate-users [post]
 func (h *Handler) deactivateUsers(c *gin.Context) {
 	teamName := c.Param("teamName")
 	if !validateRequiredParam(c, teamName, "team name") {
@@ -89,21 +93,14 @@ func (h *Handler) deactivateUsers(c *gin.Context) {
 		return
 	}
 
-	results := make(map[string]interface{})
-	for _, userID := range req.UserIDs {
-		user, err := h.userService.SetUserActive(userID, false)
-		if err != nil {
-			results[userID] = gin.H{
-				"status":  "error",
-				"message": err.Error(),
-			}
-		} else {
-			results[userID] = gin.H{
-				"status":  "success",
-				"message": "deactivated successfully",
-				"user":    user,
-			}
-		}
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), teamName, "deactivate_users")) {
+		return
+	}
+
+	results, err := h.userService.BulkDeactivateUsers(c.Request.Context(), teamName, req.UserIDs)
+	if err != nil {
+		handleError(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, DeactivateUsersResponse{
@@ -1,47 +1,125 @@
 package handler
 
 import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 
 	"ReviewAssigner/internal/errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // ErrorResponse стандартный ответ с ошибкой
 type ErrorResponse struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string                 `json:"code"`
+		Message string                 `json:"message"`
+		Details map[string]interface{} `json:"details,omitempty"`
 	} `json:"error"`
 }
 
-// handleError обрабатывает ошибки и возвращает стандартизированный ответ
+// ProblemDetails ответ с ошибкой в формате RFC 7807, отдаётся клиентам
+// с Accept: application/problem+json
+type ProblemDetails struct {
+	Type          string                 `json:"type" example:"/problems/not-found"`
+	Title         string                 `json:"title" example:"Not Found"`
+	Status        int                    `json:"status" example:"404"`
+	Detail        string                 `json:"detail,omitempty" example:"PR not found"`
+	Instance      string                 `json:"instance,omitempty" example:"/pullRequest/merge"`
+	Code          string                 `json:"code" example:"NOT_FOUND"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+	InvalidParams []InvalidParam         `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam ошибка валидации одного поля запроса
+type InvalidParam struct {
+	Name   string `json:"name" example:"pull_request_id"`
+	Reason string `json:"reason" example:"is required"`
+}
+
+const (
+	problemJSONType = "application/problem+json"
+	// problemTypeBase относительный URI типа проблемы, к нему добавляется код ошибки
+	problemTypeBase = "/problems/"
+)
+
+// handleError обрабатывает ошибки и возвращает стандартизированный ответ.
+// Доменная ошибка ищется по всей цепочке, в том числе под fmt.Errorf("%w").
 func handleError(c *gin.Context, err error) {
-	if domainErr, ok := err.(*errors.Error); ok {
-		status := getHTTPStatus(domainErr.Code)
-		response := ErrorResponse{}
-		response.Error.Code = domainErr.Code
-		response.Error.Message = domainErr.Message
-		c.JSON(status, response)
+	if domainErr, ok := errors.As(err); ok {
+		writeError(c, getHTTPStatus(domainErr.Code), domainErr.Code, domainErr.Message, domainErr.Details, nil)
 		return
 	}
 
 	// общая ошибка
+	writeError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error", nil, nil)
+}
+
+// writeError отвечает ошибкой в формате, который клиент запросил в Accept:
+// problem+json или прежний ErrorResponse по умолчанию
+func writeError(c *gin.Context, status int, code, message string, details map[string]interface{}, invalidParams []InvalidParam) {
+	if c.NegotiateFormat(binding.MIMEJSON, problemJSONType) == problemJSONType {
+		detail := message
+		if len(invalidParams) > 0 {
+			// поля перечислены в invalid_params, сырой текст валидатора не нужен
+			detail = "Request validation failed"
+		}
+		c.Header("Content-Type", problemJSONType)
+		c.JSON(status, ProblemDetails{
+			Type:          problemTypeBase + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+			Title:         http.StatusText(status),
+			Status:        status,
+			Detail:        detail,
+			Instance:      c.Request.URL.Path,
+			Code:          code,
+			Details:       details,
+			InvalidParams: invalidParams,
+		})
+		return
+	}
+
+	if len(invalidParams) > 0 {
+		if details == nil {
+			details = make(map[string]interface{}, 1)
+		}
+		details["invalid_params"] = invalidParams
+	}
+
 	response := ErrorResponse{}
-	response.Error.Code = "INTERNAL_ERROR"
-	response.Error.Message = "Internal server error"
-	c.JSON(http.StatusInternalServerError, response)
+	response.Error.Code = code
+	response.Error.Message = message
+	response.Error.Details = details
+	c.JSON(status, response)
 }
 
 func getHTTPStatus(errorCode string) int {
 	switch errorCode {
 	case "NOT_FOUND":
 		return http.StatusNotFound
-	case "PR_EXISTS", "TEAM_EXISTS", "PR_MERGED", "NOT_ASSIGNED", "NO_CANDIDATE":
+	case "PR_EXISTS", "TEAM_EXISTS", "PR_MERGED", "NOT_ASSIGNED", "NO_CANDIDATE", "MERGE_BLOCKED",
+		"INVALID_TRANSITION", "PR_NOT_OPEN", "TEAM_NOT_EMPTY", "IDEMPOTENCY_KEY_IN_USE":
 		return http.StatusConflict
+	case "IDEMPOTENCY_KEY_REUSED":
+		return http.StatusUnprocessableEntity
+	case "UNAUTHORIZED":
+		return http.StatusUnauthorized
+	case "FORBIDDEN":
+		return http.StatusForbidden
 	case "INVALID_REQUEST":
 		return http.StatusBadRequest
+	case "SERVICE_UNAVAILABLE":
+		return http.StatusServiceUnavailable
+	case "RATE_LIMITED":
+		return http.StatusTooManyRequests
+	case "PAYLOAD_TOO_LARGE":
+		return http.StatusRequestEntityTooLarge
 	default:
 		return http.StatusInternalServerError
 	}
@@ -49,10 +127,19 @@ func getHTTPStatus(errorCode string) int {
 
 func validateRequest(c *gin.Context, request interface{}) bool {
 	if err := c.ShouldBindJSON(request); err != nil {
-		response := ErrorResponse{}
-		response.Error.Code = "INVALID_REQUEST"
-		response.Error.Message = err.Error()
-		c.JSON(http.StatusBadRequest, response)
+		if bodyTooLarge(c, err) {
+			return false
+		}
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), nil, invalidParams(err))
+		return false
+	}
+	return true
+}
+
+// validateQuery как validateRequest, но для параметров строки запроса
+func validateQuery(c *gin.Context, query interface{}) bool {
+	if err := c.ShouldBindQuery(query); err != nil {
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error(), nil, invalidParams(err))
 		return false
 	}
 	return true
@@ -60,11 +147,60 @@ func validateRequest(c *gin.Context, request interface{}) bool {
 
 func validateRequiredParam(c *gin.Context, param, paramName string) bool {
 	if param == "" {
-		response := ErrorResponse{}
-		response.Error.Code = "INVALID_REQUEST"
-		response.Error.Message = paramName + " parameter is required"
-		c.JSON(http.StatusBadRequest, response)
+		writeError(c, http.StatusBadRequest, "INVALID_REQUEST", paramName+" parameter is required", nil,
+			[]InvalidParam{{Name: paramName, Reason: "is required"}})
 		return false
 	}
 	return true
 }
+
+var registerJSONNames sync.Once
+
+// useJSONFieldNames заставляет валидатор gin называть поля по json-тегам,
+// чтобы invalid_params совпадали с полями запроса
+func useJSONFieldNames() {
+	registerJSONNames.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	})
+}
+
+// invalidParams разбирает ошибку привязки тела запроса по полям
+func invalidParams(err error) []InvalidParam {
+	var validationErrs validator.ValidationErrors
+	if stderrors.As(err, &validationErrs) {
+		params := make([]InvalidParam, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			// Namespace начинается с имени структуры запроса
+			_, name, _ := strings.Cut(fe.Namespace(), ".")
+			params = append(params, InvalidParam{Name: name, Reason: validationReason(fe)})
+		}
+		return params
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) && typeErr.Field != "" {
+		return []InvalidParam{{Name: typeErr.Field, Reason: "must be " + typeErr.Type.String()}}
+	}
+	return nil
+}
+
+func validationReason(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	default:
+		return fmt.Sprintf("failed on %q validation", fe.Tag())
+	}
+}
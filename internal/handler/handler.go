@@ -1,43 +1,155 @@
 package handler
 
 import (
+	"ReviewAssigner/internal/config"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/ratelimit"
 	"ReviewAssigner/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	teamService *service.TeamService
-	userService *service.UserService
-	prService   *service.PRService
+	teamService    *service.TeamService
+	userService    *service.UserService
+	prService      *service.PRService
+	webhookService *service.WebhookService
+	authService    *service.AuthService
+	accessService  *service.AccessService
+	auditService   *service.AuditService
+	// githubSecret секрет подписи вебхуков GitHub
+	githubSecret string
+	// gitlabToken секретный токен вебхуков GitLab
+	gitlabToken string
+	// idempotencyService хранит ответы для Idempotency-Key, nil — заголовок игнорируется
+	idempotencyService *service.IdempotencyService
+
+	// readLimiter и writeLimiter частота чтения и изменений на клиента
+	readLimiter  *ratelimit.Limiter
+	writeLimiter *ratelimit.Limiter
+	// authLimiter запросы без действующего токена с одного IP
+	authLimiter *ratelimit.Limiter
+	// maxBodyBytes предельный размер тела запроса, 0 — без ограничения
+	maxBodyBytes int64
 }
 
 func NewHandler(
 	teamService *service.TeamService,
 	userService *service.UserService,
 	prService *service.PRService,
+	webhookService *service.WebhookService,
+	authService *service.AuthService,
+	accessService *service.AccessService,
+	auditService *service.AuditService,
+	idempotencyService *service.IdempotencyService,
+	cfg *config.Config,
 ) *Handler {
 	return &Handler{
-		teamService: teamService,
-		userService: userService,
-		prService:   prService,
+		teamService:    teamService,
+		userService:    userService,
+		prService:      prService,
+		webhookService: webhookService,
+		authService:    authService,
+		accessService:  accessService,
+		auditService:   auditService,
+		githubSecret:   cfg.GitHubWebhookSecret,
+		gitlabToken:    cfg.GitLabWebhookToken,
+
+		idempotencyService: idempotencyService,
+
+		readLimiter:  ratelimit.New(cfg.RateLimitRead, cfg.RateLimitReadBurst),
+		writeLimiter: ratelimit.New(cfg.RateLimitWrite, cfg.RateLimitWriteBurst),
+		authLimiter:  ratelimit.New(cfg.RateLimitAuthFailures, cfg.RateLimitAuthFailuresBurst),
+		maxBodyBytes: cfg.MaxBodyBytes,
 	}
 }
 
+// isAdmin проверяет, что у автора запроса есть область admin
+func (h *Handler) isAdmin(c *gin.Context) bool {
+	principal := currentPrincipal(c)
+	return principal != nil && principal.HasScope(models.ScopeAdmin)
+}
+
 func (h *Handler) SetupRoutes(router *gin.Engine) {
+	useJSONFieldNames()
+
+	router.Use(h.limitBody)
+
+	// без токена: проверка работоспособности и входящие вебхуки со своей подписью,
+	// вебхуки ограничиваются по IP
 	router.GET("/health", h.healthCheck)
+	router.POST("/webhooks/github", h.limitRate, h.githubWebhook)
+	router.POST("/webhooks/gitlab", h.limitRate, h.gitlabWebhook)
+
+	read := router.Group("", h.limitAuthFailures, h.authorize(models.ScopeRead), h.limitRate)
+	write := router.Group("", h.limitAuthFailures, h.authorize(models.ScopeWrite), h.limitRate)
+	admin := router.Group("", h.limitAuthFailures, h.authorize(models.ScopeAdmin), h.limitRate)
+
+	write.POST("/team/add", h.addTeam)
+	read.GET("/team/get", h.getTeam)
+	write.POST("/team/settings", h.updateTeamSettings)
+	write.POST("/team/:teamName/deactivate-users", h.deactivateUsers)
+
+	write.POST("/users/setIsActive", h.setUserActive)
+	read.GET("/users/getReview", h.getUserReviews)
+
+	write.POST("/pullRequest/create", h.idempotent(h.createPR))
+	write.POST("/pullRequest/merge", h.mergePR)
+	write.POST("/pullRequest/reassign", h.idempotent(h.reassignReviewer))
+	write.POST("/pullRequest/review", h.submitReview)
+	write.POST("/pullRequest/ready", h.markPRReady)
+	write.POST("/pullRequest/close", h.closePR)
+	write.POST("/pullRequest/reopen", h.reopenPR)
+	read.GET("/pullRequests", h.listPRs)
+
+	admin.POST("/webhooks/subscriptions/add", h.addWebhookSubscription)
+	admin.GET("/webhooks/subscriptions/list", h.listWebhookSubscriptions)
+	admin.POST("/webhooks/subscriptions/delete", h.deleteWebhookSubscription)
+	admin.GET("/webhooks/deliveries", h.getWebhookDeliveries)
+
+	read.GET("/stats/user-assignments", h.getUserAssignmentsStats)
+	read.GET("/stats/pr-metrics", h.getPRMetrics)
+
+	admin.GET("/audit", h.listAudit)
+
+	h.setupV1Routes(router.Group("/api/v1"))
+}
+
+// setupV1Routes ресурсные маршруты /api/v1. Маршруты выше остаются
+// для совместимости и работают через те же сервисы.
+func (h *Handler) setupV1Routes(v1 *gin.RouterGroup) {
+	read := v1.Group("", h.limitAuthFailures, h.authorize(models.ScopeRead), h.limitRate)
+	write := v1.Group("", h.limitAuthFailures, h.authorize(models.ScopeWrite), h.limitRate)
+	admin := v1.Group("", h.limitAuthFailures, h.authorize(models.ScopeAdmin), h.limitRate)
+
+	read.GET("/teams", h.listTeams)
+	write.POST("/teams", h.addTeam)
+	read.GET("/teams/:teamName", h.getTeamByName)
+	write.PATCH("/teams/:teamName", h.patchTeam)
+	admin.DELETE("/teams/:teamName", h.deleteTeam)
+	read.GET("/teams/:teamName/members", h.listTeamMembers)
+	write.POST("/teams/:teamName/deactivate-users", h.deactivateUsers)
+	read.GET("/teams/:teamName/leads", h.listTeamLeads)
+	admin.PUT("/teams/:teamName/leads/:userID", h.addTeamLead)
+	admin.DELETE("/teams/:teamName/leads/:userID", h.removeTeamLead)
+
+	read.GET("/users/:userID", h.getUser)
+	write.PATCH("/users/:userID", h.patchUser)
+	read.GET("/users/:userID/reviews", h.getUserReviewsByID)
 
-	router.POST("/team/add", h.addTeam)
-	router.GET("/team/get", h.getTeam)
-	router.POST("/team/:teamName/deactivate-users", h.deactivateUsers)
+	read.GET("/pull-requests", h.listPRs)
+	write.POST("/pull-requests", h.idempotent(h.createPR))
+	read.GET("/pull-requests/:id", h.getPR)
+	write.PATCH("/pull-requests/:id", h.patchPR)
+	read.GET("/pull-requests/:id/reviewers", h.listPRReviewers)
+	write.DELETE("/pull-requests/:id/reviewers/:reviewerID", h.idempotent(h.replacePRReviewer))
+	write.POST("/pull-requests/:id/reviews", h.createPRReview)
 
-	router.POST("/users/setIsActive", h.setUserActive)
-	router.GET("/users/getReview", h.getUserReviews)
+	v1.GET("/me", h.limitAuthFailures, h.authorize(""), h.limitRate, h.getMe)
 
-	router.POST("/pullRequest/create", h.createPR)
-	router.POST("/pullRequest/merge", h.mergePR)
-	router.POST("/pullRequest/reassign", h.reassignReviewer)
+	admin.GET("/tokens", h.listTokens)
+	admin.POST("/tokens", h.createToken)
+	admin.DELETE("/tokens/:id", h.revokeToken)
 
-	router.GET("/stats/user-assignments", h.getUserAssignmentsStats)
-	router.GET("/stats/pr-metrics", h.getPRMetrics)
+	admin.GET("/audit", h.listAudit)
 }
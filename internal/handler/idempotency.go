@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// заголовки идемпотентных запросов
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader помечает ответ, отданный из сохранённого
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// responseRecorder копирует тело ответа, чтобы его можно было сохранить
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotent оборачивает обработчик поддержкой заголовка Idempotency-Key:
+// первый ответ сохраняется, повтор того же клиента с тем же ключом и тем же
+// запросом получает его без повторного выполнения. Ответы 5xx не сохраняются — такой запрос
+// можно повторить. Без заголовка обработчик вызывается как есть.
+func (h *Handler) idempotent(next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" || h.idempotencyService == nil {
+			next(c)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeError(c, http.StatusBadRequest, "INVALID_REQUEST", "Idempotency-Key is too long", nil,
+				[]InvalidParam{{Name: idempotencyKeyHeader, Reason: "must be at most 255 characters"}})
+			return
+		}
+
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// ключ действует в пределах клиента и маршрута, а запрос сравнивается по пути и телу
+		scope := c.Request.Method + " " + c.FullPath()
+		if principal := currentPrincipal(c); principal != nil {
+			scope = principal.Subject + " " + scope
+		}
+		sum := sha256.New()
+		sum.Write([]byte(c.Request.URL.Path))
+		sum.Write([]byte{0})
+		sum.Write(body)
+		requestHash := hex.EncodeToString(sum.Sum(nil))
+
+		// ответ сохраняется, даже если клиент уже отключился
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		saved, err := h.idempotencyService.Begin(ctx, key, scope, requestHash)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		if saved != nil {
+			c.Header(idempotentReplayedHeader, "true")
+			c.Data(saved.StatusCode, saved.ContentType, []byte(saved.ResponseBody))
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		completed := false
+		defer func() {
+			// паника или 5xx: ключ освобождается, чтобы повтор выполнил запрос
+			if !completed {
+				_ = h.idempotencyService.Release(ctx, key, scope)
+			}
+		}()
+
+		next(c)
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := h.idempotencyService.Complete(ctx, key, scope, status,
+			recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			return
+		}
+		completed = true
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	stderrors "errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limitRate ограничивает частоту запросов клиента, чтение (GET, HEAD) и изменения
+// расходуют разные корзины. Клиент — автор запроса (API-токен или sub из JWT),
+// на открытых маршрутах — IP. Ставится после authorize.
+func (h *Handler) limitRate(c *gin.Context) {
+	limiter := h.writeLimiter
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		limiter = h.readLimiter
+	}
+
+	key := "ip:" + c.ClientIP()
+	if principal := currentPrincipal(c); principal != nil {
+		key = principal.Subject
+	}
+
+	if ok, wait := limiter.Allow(key); !ok {
+		rateLimited(c, wait)
+		return
+	}
+	c.Next()
+}
+
+// limitAuthFailures ограничивает по IP запросы без действующего токена: каждый
+// ответ 401 расходует корзину адреса, а с пустой корзиной запрос отклоняется до
+// проверки токена. Ставится перед authorize.
+func (h *Handler) limitAuthFailures(c *gin.Context) {
+	key := "ip:" + c.ClientIP()
+	if wait := h.authLimiter.Wait(key); wait > 0 {
+		rateLimited(c, wait)
+		return
+	}
+
+	c.Next()
+
+	if c.Writer.Status() == http.StatusUnauthorized {
+		h.authLimiter.Allow(key)
+	}
+}
+
+// rateLimited отвечает 429 с Retry-After в секундах
+func rateLimited(c *gin.Context, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	handleError(c, errors.ErrRateLimited.WithDetails("retry_after", retryAfter))
+	c.Abort()
+}
+
+// limitBody ограничивает размер тела запроса: если Content-Length больше
+// предела — сразу 413, иначе тело читается не дальше предела
+func (h *Handler) limitBody(c *gin.Context) {
+	if h.maxBodyBytes <= 0 || c.Request.Body == nil {
+		c.Next()
+		return
+	}
+
+	if c.Request.ContentLength > h.maxBodyBytes {
+		handleError(c, errors.ErrBodyTooLarge.WithDetails("max_bytes", h.maxBodyBytes))
+		c.Abort()
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBodyBytes)
+	c.Next()
+}
+
+// bodyTooLarge отвечает 413, если тело запроса оборвано по пределу limitBody
+func bodyTooLarge(c *gin.Context, err error) bool {
+	var maxErr *http.MaxBytesError
+	if !stderrors.As(err, &maxErr) {
+		return false
+	}
+	handleError(c, errors.ErrBodyTooLarge.WithDetails("max_bytes", maxErr.Limit))
+	return true
+}
+
+// readBody читает тело запроса целиком; при ошибке уже отвечает клиенту
+func readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		if !bodyTooLarge(c, err) {
+			handleError(c, errors.NewError("INVALID_REQUEST", "Failed to read request body"))
+		}
+		return nil, false
+	}
+	return body, true
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"time"
+
+	"ReviewAssigner/internal/repository"
+)
+
+// PageQuery параметры страницы списка. next_cursor из ответа передаётся
+// в cursor следующего запроса, пустой next_cursor — последняя страница.
+type PageQuery struct {
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=200" example:"50"`
+	Cursor string `form:"cursor" json:"cursor"`
+}
+
+func (q PageQuery) page() repository.Page {
+	return repository.Page{Limit: q.Limit, Cursor: q.Cursor}
+}
+
+// ListPRsQuery фильтры, сортировка и страница списка PR
+type ListPRsQuery struct {
+	Status     string `form:"status" json:"status" binding:"omitempty,oneof=DRAFT OPEN MERGED CLOSED"`
+	AuthorID   string `form:"author_id" json:"author_id"`
+	ReviewerID string `form:"reviewer_id" json:"reviewer_id"`
+	TeamName   string `form:"team_name" json:"team_name"`
+	// CreatedFrom и CreatedTo в RFC 3339, граница created_to не включается
+	CreatedFrom *time.Time `form:"created_from" json:"created_from"`
+	CreatedTo   *time.Time `form:"created_to" json:"created_to"`
+	Sort        string     `form:"sort" json:"sort" binding:"omitempty,oneof=created_at -created_at pull_request_id -pull_request_id"`
+	Limit       int        `form:"limit" json:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor      string     `form:"cursor" json:"cursor"`
+}
+
+func (q ListPRsQuery) filter() repository.PRFilter {
+	return repository.PRFilter{
+		Status:      q.Status,
+		AuthorID:    q.AuthorID,
+		ReviewerID:  q.ReviewerID,
+		TeamName:    q.TeamName,
+		CreatedFrom: q.CreatedFrom,
+		CreatedTo:   q.CreatedTo,
+		Sort:        q.Sort,
+	}
+}
+
+func (q ListPRsQuery) page() repository.Page {
+	return repository.Page{Limit: q.Limit, Cursor: q.Cursor}
+}
+
+// AuditQuery фильтры и страница журнала аудита
+type AuditQuery struct {
+	// Actor subject автора: token:<id>, sub из JWT или webhook:<источник>
+	Actor      string `form:"actor" json:"actor"`
+	Operation  string `form:"operation" json:"operation"`
+	EntityType string `form:"entity_type" json:"entity_type" binding:"omitempty,oneof=team user pull_request"`
+	EntityID   string `form:"entity_id" json:"entity_id"`
+	// From и To в RFC 3339, граница to не включается
+	From   *time.Time `form:"from" json:"from"`
+	To     *time.Time `form:"to" json:"to"`
+	Limit  int        `form:"limit" json:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor string     `form:"cursor" json:"cursor"`
+}
+
+func (q AuditQuery) filter() repository.AuditFilter {
+	return repository.AuditFilter{
+		Actor:      q.Actor,
+		Operation:  q.Operation,
+		EntityType: q.EntityType,
+		EntityID:   q.EntityID,
+		From:       q.From,
+		To:         q.To,
+	}
+}
+
+func (q AuditQuery) page() repository.Page {
+	return repository.Page{Limit: q.Limit, Cursor: q.Cursor}
+}
@@ -125,6 +125,7 @@ package handler
 import (
 	"net/http"
 
+	"ReviewAssigner/internal/errors"
 	"ReviewAssigner/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -134,10 +135,36 @@ type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id" binding:"required" example:"pr-123"`
 	PullRequestName string `json:"pull_request_name" binding:"required" example:"Fix login issue"`
 	AuthorID        string `json:"author_id" binding:"required" example:"user-456"`
+	ReviewerCount   *int   `json:"reviewer_count,omitempty" example:"2"`
+	Draft           bool   `json:"draft" example:"false"`
+}
+
+type PRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required" example:"pr-123"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required" example:"pr-123"`
+	Force         bool   `json:"force" example:"false"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required" example:"pr-123"`
+	ReviewerID    string `json:"reviewer_id" binding:"required" example:"user-789"`
+	Verdict       string `json:"verdict" binding:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED" example:"APPROVED"`
+}
+
+// PatchPRRequest смена статуса PR через /api/v1
+type PatchPRRequest struct {
+	Status string `json:"status" binding:"required,oneof=OPEN MERGED CLOSED" example:"MERGED"`
+	// Force пропускает политику мерджа, только для токена с областью admin
+	Force bool `json:"force" example:"false"`
+}
+
+// CreateReviewRequest вердикт ревьюера через /api/v1
+type CreateReviewRequest struct {
+	ReviewerID string `json:"reviewer_id" binding:"required" example:"user-789"`
+	Verdict    string `json:"verdict" binding:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED" example:"APPROVED"`
 }
 
 type ReassignReviewerRequest struct {
@@ -147,28 +174,40 @@ type ReassignReviewerRequest struct {
 
 // CreatePR godoc
 // @Summary Создание Pull Request
-// @Description Создает новый PR и автоматически назначает ревьюеров
+// @Description Создает новый PR и автоматически назначает ревьюеров. reviewer_count переопределяет число ревьюеров команды, draft создает черновик без ревьюеров и не совместим с reviewer_count
 // @Tags pull-requests
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body CreatePRRequest true "Данные Pull Request" example:{"pull_request_id":"pr-123","pull_request_name":"Fix login issue","author_id":"user-456"}
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом получает первый ответ" example:ci-run-42
 // @Success 201 {object} PRResponse "Созданный PR"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
-// @Failure 409 {object} ErrorResponse "PR уже существует"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 409 {object} ErrorResponse "PR уже существует или запрос с этим Idempotency-Key ещё выполняется"
+// @Failure 422 {object} ErrorResponse "Idempotency-Key уже использован с другим запросом"
 // @Router /pullRequest/create [post]
+// @Router /api/v1/pull-requests [post]
 func (h *Handler) createPR(c *gin.Context) {
 	var request CreatePRRequest
 	if !validateRequest(c, &request) {
 		return
 	}
 
+	if !allowed(c, h.accessService.CanCreatePR(c.Request.Context(), currentPrincipal(c), request.AuthorID)) {
+		return
+	}
+
 	pr := &models.PullRequest{
 		PullRequestID:   request.PullRequestID,
 		PullRequestName: request.PullRequestName,
 		AuthorID:        request.AuthorID,
 	}
+	if request.Draft {
+		pr.Status = models.PRStatusDraft
+	}
 
-	createdPR, err := h.prService.CreatePR(pr)
+	createdPR, err := h.prService.CreatePR(c.Request.Context(), pr, request.ReviewerCount)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -179,14 +218,17 @@ func (h *Handler) createPR(c *gin.Context) {
 
 // MergePR godoc
 // @Summary Merge Pull Request
-// @Description Помечает PR как мердженный
+// @Description Помечает PR как мердженный, если выполнена политика мерджа команды. force с токеном области admin пропускает проверку
 // @Tags pull-requests
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body MergePRRequest true "ID Pull Request" example:{"pull_request_id":"pr-123"}
 // @Success 200 {object} PRResponse "Обновленный PR"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав или force без области admin"
 // @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Политика мерджа не выполнена или PR не открыт"
 // @Router /pullRequest/merge [post]
 func (h *Handler) mergePR(c *gin.Context) {
 	var request MergePRRequest
@@ -194,7 +236,16 @@ func (h *Handler) mergePR(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.prService.MergePR(request.PullRequestID)
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), request.PullRequestID, "merge_pr")) {
+		return
+	}
+
+	if request.Force && !h.isAdmin(c) {
+		handleError(c, errors.ErrForbidden)
+		return
+	}
+
+	pr, err := h.prService.MergePR(c.Request.Context(), request.PullRequestID, request.Force)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -209,11 +260,15 @@ func (h *Handler) mergePR(c *gin.Context) {
 // @Tags pull-requests
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body ReassignReviewerRequest true "Данные для замены ревьюера" example:{"pull_request_id":"pr-123","current_reviewer_id":"user-789"}
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом получает первый ответ" example:ci-run-42
 // @Success 200 {object} ReassignReviewerResponse "Результат замены"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
 // @Failure 404 {object} ErrorResponse "PR или ревьюер не найден"
 // @Failure 409 {object} ErrorResponse "Ошибка замены ревьюера"
+// @Failure 422 {object} ErrorResponse "Idempotency-Key уже использован с другим запросом"
 // @Router /pullRequest/reassign [post]
 func (h *Handler) reassignReviewer(c *gin.Context) {
 	var request ReassignReviewerRequest
@@ -221,13 +276,270 @@ func (h *Handler) reassignReviewer(c *gin.Context) {
 		return
 	}
 
-	newReviewerID, err := h.prService.ReplaceReviewer(request.PullRequestID, request.CurrentReviewerID)
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), request.PullRequestID, "reassign_reviewer")) {
+		return
+	}
+
+	newReviewerID, err := h.prService.ReplaceReviewer(c.Request.Context(), request.PullRequestID, request.CurrentReviewerID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	pr, err := h.prService.GetPRByID(c.Request.Context(), request.PullRequestID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ReassignReviewerResponse{
+		PR:         pr,
+		ReplacedBy: newReviewerID,
+	})
+}
+
+// SubmitReview godoc
+// @Summary Вердикт ревьюера
+// @Description Фиксирует результат ревью: APPROVED, CHANGES_REQUESTED или COMMENTED
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SubmitReviewRequest true "Вердикт ревьюера" example:{"pull_request_id":"pr-123","reviewer_id":"user-789","verdict":"APPROVED"}
+// @Success 200 {object} PRResponse "PR с вердиктами ревьюеров"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Ревьюер не назначен или PR смерджен"
+// @Router /pullRequest/review [post]
+func (h *Handler) submitReview(c *gin.Context) {
+	var request SubmitReviewRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanSubmitReview(currentPrincipal(c), request.ReviewerID)) {
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(c.Request.Context(), request.PullRequestID, request.ReviewerID, request.Verdict)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// MarkPRReady godoc
+// @Summary Перевод черновика в ревью
+// @Description Переводит PR из DRAFT в OPEN и назначает ревьюеров
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PRStatusRequest true "ID Pull Request" example:{"pull_request_id":"pr-123"}
+// @Success 200 {object} PRResponse "Обновленный PR"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Недопустимый переход статуса"
+// @Router /pullRequest/ready [post]
+func (h *Handler) markPRReady(c *gin.Context) {
+	var request PRStatusRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), request.PullRequestID, "change_pr_status")) {
+		return
+	}
+
+	pr, err := h.prService.MarkReady(c.Request.Context(), request.PullRequestID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// ClosePR godoc
+// @Summary Закрытие Pull Request
+// @Description Закрывает PR без мерджа и снимает назначенных ревьюеров
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PRStatusRequest true "ID Pull Request" example:{"pull_request_id":"pr-123"}
+// @Success 200 {object} PRResponse "Обновленный PR"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Недопустимый переход статуса"
+// @Router /pullRequest/close [post]
+func (h *Handler) closePR(c *gin.Context) {
+	var request PRStatusRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), request.PullRequestID, "change_pr_status")) {
+		return
+	}
+
+	pr, err := h.prService.ClosePR(c.Request.Context(), request.PullRequestID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// ReopenPR godoc
+// @Summary Повторное открытие Pull Request
+// @Description Открывает закрытый PR и назначает ревьюеров заново
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PRStatusRequest true "ID Pull Request" example:{"pull_request_id":"pr-123"}
+// @Success 200 {object} PRResponse "Обновленный PR"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Недопустимый переход статуса"
+// @Router /pullRequest/reopen [post]
+func (h *Handler) reopenPR(c *gin.Context) {
+	var request PRStatusRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), request.PullRequestID, "change_pr_status")) {
+		return
+	}
+
+	pr, err := h.prService.ReopenPR(c.Request.Context(), request.PullRequestID)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	pr, err := h.prService.GetPRByID(request.PullRequestID)
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// GetPR godoc
+// @Summary Получение Pull Request
+// @Tags pull-requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID Pull Request" example:pr-123
+// @Success 200 {object} PRResponse "PR"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Router /api/v1/pull-requests/{id} [get]
+func (h *Handler) getPR(c *gin.Context) {
+	pr, err := h.prService.GetPRByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// PatchPR godoc
+// @Summary Смена статуса Pull Request
+// @Description OPEN переводит черновик в ревью или открывает закрытый PR, MERGED мерджит по политике команды (force с токеном области admin пропускает проверку), CLOSED закрывает без мерджа
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID Pull Request" example:pr-123
+// @Param request body PatchPRRequest true "Новый статус" example:{"status":"MERGED"}
+// @Success 200 {object} PRResponse "Обновленный PR"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав или force без области admin"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Недопустимый переход статуса или политика мерджа не выполнена"
+// @Router /api/v1/pull-requests/{id} [patch]
+func (h *Handler) patchPR(c *gin.Context) {
+	var request PatchPRRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), c.Param("id"), "change_pr_status")) {
+		return
+	}
+
+	if request.Force && !h.isAdmin(c) {
+		handleError(c, errors.ErrForbidden)
+		return
+	}
+
+	pr, err := h.prService.SetStatus(c.Request.Context(), c.Param("id"), request.Status, request.Force)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// ListPRReviewers godoc
+// @Summary Ревьюеры Pull Request
+// @Description Возвращает назначенных ревьюеров и их вердикты
+// @Tags pull-requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID Pull Request" example:pr-123
+// @Success 200 {object} PRReviewersResponse "Ревьюеры"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Router /api/v1/pull-requests/{id}/reviewers [get]
+func (h *Handler) listPRReviewers(c *gin.Context) {
+	pr, err := h.prService.GetPRByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRReviewersResponse{
+		PullRequestID: pr.PullRequestID,
+		Reviewers:     pr.Reviews,
+	})
+}
+
+// ReplacePRReviewer godoc
+// @Summary Замена ревьюера
+// @Description Снимает ревьюера с PR и назначает вместо него другого активного участника команды
+// @Tags pull-requests
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID Pull Request" example:pr-123
+// @Param reviewerID path string true "ID снимаемого ревьюера" example:user-789
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом получает первый ответ" example:ci-run-42
+// @Success 200 {object} ReassignReviewerResponse "Результат замены"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR или ревьюер не найден"
+// @Failure 409 {object} ErrorResponse "Ревьюер не назначен, PR не открыт или нет кандидатов"
+// @Failure 422 {object} ErrorResponse "Idempotency-Key уже использован с другим запросом"
+// @Router /api/v1/pull-requests/{id}/reviewers/{reviewerID} [delete]
+func (h *Handler) replacePRReviewer(c *gin.Context) {
+	prID := c.Param("id")
+
+	if !allowed(c, h.accessService.CanActOnPR(c.Request.Context(), currentPrincipal(c), prID, "reassign_reviewer")) {
+		return
+	}
+
+	newReviewerID, err := h.prService.ReplaceReviewer(c.Request.Context(), prID, c.Param("reviewerID"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	pr, err := h.prService.GetPRByID(c.Request.Context(), prID)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -238,3 +550,75 @@ func (h *Handler) reassignReviewer(c *gin.Context) {
 		ReplacedBy: newReviewerID,
 	})
 }
+
+// CreatePRReview godoc
+// @Summary Вердикт ревьюера
+// @Description Фиксирует результат ревью: APPROVED, CHANGES_REQUESTED или COMMENTED
+// @Tags pull-requests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID Pull Request" example:pr-123
+// @Param request body CreateReviewRequest true "Вердикт ревьюера" example:{"reviewer_id":"user-789","verdict":"APPROVED"}
+// @Success 200 {object} PRResponse "PR с вердиктами ревьюеров"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "PR не найден"
+// @Failure 409 {object} ErrorResponse "Ревьюер не назначен или PR не открыт"
+// @Router /api/v1/pull-requests/{id}/reviews [post]
+func (h *Handler) createPRReview(c *gin.Context) {
+	var request CreateReviewRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanSubmitReview(currentPrincipal(c), request.ReviewerID)) {
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(c.Request.Context(), c.Param("id"), request.ReviewerID, request.Verdict)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRResponse{PR: pr})
+}
+
+// ListPRs godoc
+// @Summary Список PR
+// @Description Возвращает PR по фильтрам постранично. Курсор привязан к сортировке,
+// @Description для другой сортировки нужно начинать с первой страницы
+// @Tags pull-requests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Статус" Enums(DRAFT, OPEN, MERGED, CLOSED)
+// @Param author_id query string false "Автор" example:u1
+// @Param reviewer_id query string false "Активный ревьюер" example:u2
+// @Param team_name query string false "Команда автора" example:backend
+// @Param created_from query string false "Создан не раньше, RFC 3339" example:2025-01-01T00:00:00Z
+// @Param created_to query string false "Создан раньше, RFC 3339" example:2025-02-01T00:00:00Z
+// @Param sort query string false "Сортировка, по умолчанию -created_at" Enums(created_at, -created_at, pull_request_id, -pull_request_id)
+// @Param limit query int false "Размер страницы, по умолчанию 50, максимум 200" example:50
+// @Param cursor query string false "next_cursor из предыдущего ответа"
+// @Success 200 {object} PRListResponse "Страница PR"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации или негодный курсор"
+// @Router /pullRequests [get]
+// @Router /api/v1/pull-requests [get]
+func (h *Handler) listPRs(c *gin.Context) {
+	var query ListPRsQuery
+	if !validateQuery(c, &query) {
+		return
+	}
+
+	prs, next, err := h.prService.ListPRs(c.Request.Context(), query.filter(), query.page())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PRListResponse{
+		PullRequests: prs,
+		NextCursor:   next,
+	})
+}
@@ -1,6 +1,9 @@
 package handler
 
-import "ReviewAssigner/internal/models"
+import (
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
+)
 
 type PRResponse struct {
 	PR *models.PullRequest `json:"pr"`
@@ -10,6 +13,15 @@ type TeamResponse struct {
 	Team *models.Team `json:"team"`
 }
 
+type TeamsResponse struct {
+	Teams []models.Team `json:"teams"`
+}
+
+type TeamSettingsResponse struct {
+	TeamName string               `json:"team_name"`
+	Settings *models.TeamSettings `json:"settings"`
+}
+
 type UserResponse struct {
 	User *models.User `json:"user"`
 }
@@ -17,11 +29,34 @@ type UserResponse struct {
 type UserPRsResponse struct {
 	UserID       string                    `json:"user_id"`
 	PullRequests []models.PullRequestShort `json:"pull_requests"`
+	NextCursor   string                    `json:"next_cursor,omitempty"`
+}
+
+type PRListResponse struct {
+	PullRequests []models.PullRequestShort `json:"pull_requests"`
+	NextCursor   string                    `json:"next_cursor,omitempty"`
+}
+
+type AuditLogResponse struct {
+	Entries    []models.AuditEntry `json:"entries"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type TeamMembersResponse struct {
+	TeamName   string              `json:"team_name"`
+	Members    []models.TeamMember `json:"members"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// TeamLeadsResponse user_id лидов команды
+type TeamLeadsResponse struct {
+	TeamName string   `json:"team_name"`
+	Leads    []string `json:"leads"`
 }
 
 type DeactivateUsersResponse struct {
-	TeamName string                 `json:"team_name"`
-	Results  map[string]interface{} `json:"results"`
+	TeamName string                          `json:"team_name"`
+	Results  map[string]service.Reassignment `json:"results"`
 }
 
 type StatsResponse struct {
@@ -34,7 +69,33 @@ type ReassignReviewerResponse struct {
 	ReplacedBy string              `json:"replaced_by"`
 }
 
+type PRReviewersResponse struct {
+	PullRequestID string          `json:"pull_request_id"`
+	Reviewers     []models.Review `json:"reviewers"`
+}
+
 type HealthResponse struct {
 	Status  string `json:"status"`
 	Service string `json:"service"`
 }
+
+type WebhookResponse struct {
+	Status string              `json:"status" example:"processed"`
+	Reason string              `json:"reason,omitempty"`
+	PR     *models.PullRequest `json:"pr,omitempty"`
+}
+
+type WebhookSubscriptionResponse struct {
+	Subscription *models.WebhookSubscription `json:"subscription"`
+	// Secret возвращается только при создании подписки
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []models.WebhookSubscription `json:"subscriptions"`
+}
+
+type WebhookDeliveriesResponse struct {
+	SubscriptionID int64                    `json:"subscription_id"`
+	Deliveries     []models.WebhookDelivery `json:"deliveries"`
+}
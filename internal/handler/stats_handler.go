@@ -12,10 +12,11 @@ import (
 // @Tags statistics
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Success 200 {object} StatsResponse "Статистика назначений"
 // @Router /stats/user-assignments [get]
 func (h *Handler) getUserAssignmentsStats(c *gin.Context) {
-	stats, err := h.prService.GetUserAssignmentStats()
+	stats, err := h.prService.GetUserAssignmentStats(c.Request.Context())
 	if err != nil {
 		handleError(c, err)
 		return
@@ -30,10 +31,11 @@ func (h *Handler) getUserAssignmentsStats(c *gin.Context) {
 // @Tags statistics
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Success 200 {object} StatsResponse "Метрики PR"
 // @Router /stats/pr-metrics [get]
 func (h *Handler) getPRMetrics(c *gin.Context) {
-	metrics, err := h.prService.GetPRMetrics()
+	metrics, err := h.prService.GetPRMetrics(c.Request.Context())
 	if err != nil {
 		handleError(c, err)
 		return
@@ -76,13 +76,33 @@ import (
 	"net/http"
 
 	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AddTeamRequest struct {
-	TeamName string              `json:"team_name" binding:"required" example:"backend"`
-	Members  []models.TeamMember `json:"members" binding:"required"`
+	TeamName          string              `json:"team_name" binding:"required" example:"backend"`
+	Members           []models.TeamMember `json:"members" binding:"required"`
+	SelectionStrategy string              `json:"selection_strategy" binding:"omitempty,oneof=random round_robin least_loaded weighted" example:"random"`
+	ReviewerCount     int                 `json:"reviewer_count" example:"2"`
+}
+
+// PatchTeamRequest частичное обновление настроек команды, nil поля не меняются
+type PatchTeamRequest struct {
+	SelectionStrategy       *string `json:"selection_strategy" binding:"omitempty,oneof=random round_robin least_loaded weighted" example:"least_loaded"`
+	ReviewerCount           *int    `json:"reviewer_count" example:"3"`
+	RequiredApprovals       *int    `json:"required_approvals" example:"1"`
+	BlockOnChangesRequested *bool   `json:"block_on_changes_requested" example:"true"`
+}
+
+type UpdateTeamSettingsRequest struct {
+	TeamName          string  `json:"team_name" binding:"required" example:"backend"`
+	SelectionStrategy *string `json:"selection_strategy" binding:"omitempty,oneof=random round_robin least_loaded weighted" example:"least_loaded"`
+	ReviewerCount     *int    `json:"reviewer_count" example:"3"`
+
+	RequiredApprovals       *int  `json:"required_approvals" example:"1"`
+	BlockOnChangesRequested *bool `json:"block_on_changes_requested" example:"true"`
 }
 
 // AddTeam godoc
@@ -91,11 +111,14 @@ type AddTeamRequest struct {
 // @Tags teams
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body AddTeamRequest true "Данные команды" example:{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true},{"user_id":"u2","username":"Bob","is_active":true}]}
 // @Success 201 {object} TeamResponse "Созданная команда"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
 // @Failure 409 {object} ErrorResponse "Команда уже существует"
 // @Router /team/add [post]
+// @Router /api/v1/teams [post]
 func (h *Handler) addTeam(c *gin.Context) {
 	var request AddTeamRequest
 
@@ -103,12 +126,20 @@ func (h *Handler) addTeam(c *gin.Context) {
 		return
 	}
 
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), request.TeamName, "add_team")) {
+		return
+	}
+
 	team := &models.Team{
 		TeamName: request.TeamName,
-		Members:  request.Members,
+		TeamSettings: models.TeamSettings{
+			SelectionStrategy: request.SelectionStrategy,
+			ReviewerCount:     request.ReviewerCount,
+		},
+		Members: request.Members,
 	}
 
-	if err := h.teamService.CreateTeam(team); err != nil {
+	if err := h.teamService.CreateTeam(c.Request.Context(), team); err != nil {
 		handleError(c, err)
 		return
 	}
@@ -122,6 +153,7 @@ func (h *Handler) addTeam(c *gin.Context) {
 // @Tags teams
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param team_name query string true "Название команды" example:backend
 // @Success 200 {object} models.Team "Информация о команде"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
@@ -133,7 +165,7 @@ func (h *Handler) getTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.GetTeam(teamName)
+	team, err := h.teamService.GetTeam(c.Request.Context(), teamName)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -141,3 +173,259 @@ func (h *Handler) getTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+// UpdateTeamSettings godoc
+// @Summary Изменение настроек команды
+// @Description Меняет стратегию выбора, число ревьюеров по умолчанию и политику мерджа команды
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateTeamSettingsRequest true "Новые настройки" example:{"team_name":"backend","selection_strategy":"least_loaded"}
+// @Success 200 {object} TeamSettingsResponse "Обновленные настройки"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Router /team/settings [post]
+func (h *Handler) updateTeamSettings(c *gin.Context) {
+	var request UpdateTeamSettingsRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), request.TeamName, "update_team_settings")) {
+		return
+	}
+
+	settings, err := h.teamService.UpdateTeamSettings(c.Request.Context(), request.TeamName, service.TeamSettingsUpdate{
+		SelectionStrategy: request.SelectionStrategy,
+		ReviewerCount:     request.ReviewerCount,
+
+		RequiredApprovals:       request.RequiredApprovals,
+		BlockOnChangesRequested: request.BlockOnChangesRequested,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamSettingsResponse{
+		TeamName: request.TeamName,
+		Settings: settings,
+	})
+}
+
+// ListTeams godoc
+// @Summary Список команд
+// @Description Возвращает все команды с настройками и участниками
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TeamsResponse "Команды"
+// @Router /api/v1/teams [get]
+func (h *Handler) listTeams(c *gin.Context) {
+	teams, err := h.teamService.ListTeams(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamsResponse{Teams: teams})
+}
+
+// GetTeamByName godoc
+// @Summary Получение команды
+// @Description Возвращает команду с настройками и участниками
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Success 200 {object} models.Team "Информация о команде"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Router /api/v1/teams/{teamName} [get]
+func (h *Handler) getTeamByName(c *gin.Context) {
+	team, err := h.teamService.GetTeam(c.Request.Context(), c.Param("teamName"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// PatchTeam godoc
+// @Summary Изменение настроек команды
+// @Description Меняет переданные настройки команды, остальные остаются прежними
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Param request body PatchTeamRequest true "Новые настройки" example:{"selection_strategy":"least_loaded"}
+// @Success 200 {object} TeamSettingsResponse "Обновленные настройки"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Router /api/v1/teams/{teamName} [patch]
+func (h *Handler) patchTeam(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	var request PatchTeamRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), teamName, "update_team_settings")) {
+		return
+	}
+
+	settings, err := h.teamService.UpdateTeamSettings(c.Request.Context(), teamName, service.TeamSettingsUpdate{
+		SelectionStrategy: request.SelectionStrategy,
+		ReviewerCount:     request.ReviewerCount,
+
+		RequiredApprovals:       request.RequiredApprovals,
+		BlockOnChangesRequested: request.BlockOnChangesRequested,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamSettingsResponse{
+		TeamName: teamName,
+		Settings: settings,
+	})
+}
+
+// DeleteTeam godoc
+// @Summary Удаление команды
+// @Description Удаляет команду без участников
+// @Tags teams
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Success 204 "Команда удалена"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Failure 409 {object} ErrorResponse "В команде есть участники"
+// @Router /api/v1/teams/{teamName} [delete]
+func (h *Handler) deleteTeam(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), teamName, "delete_team")) {
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(c.Request.Context(), teamName); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTeamMembers godoc
+// @Summary Участники команды
+// @Description Возвращает участников команды постранично по возрастанию user_id
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Param limit query int false "Размер страницы, по умолчанию 50, максимум 200" example:50
+// @Param cursor query string false "next_cursor из предыдущего ответа"
+// @Success 200 {object} TeamMembersResponse "Страница участников"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации или негодный курсор"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Router /api/v1/teams/{teamName}/members [get]
+func (h *Handler) listTeamMembers(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	var query PageQuery
+	if !validateQuery(c, &query) {
+		return
+	}
+
+	members, next, err := h.teamService.ListMembers(c.Request.Context(), teamName, query.page())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamMembersResponse{
+		TeamName:   teamName,
+		Members:    members,
+		NextCursor: next,
+	})
+}
+
+// ListTeamLeads godoc
+// @Summary Лиды команды
+// @Description Возвращает пользователей, которые управляют участниками и PR команды
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Success 200 {object} TeamLeadsResponse "Лиды команды"
+// @Failure 404 {object} ErrorResponse "Команда не найдена"
+// @Router /api/v1/teams/{teamName}/leads [get]
+func (h *Handler) listTeamLeads(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	leads, err := h.teamService.GetLeads(c.Request.Context(), teamName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamLeadsResponse{TeamName: teamName, Leads: leads})
+}
+
+// AddTeamLead godoc
+// @Summary Назначение лида команды
+// @Description Делает пользователя лидом команды. Лид не обязан состоять в команде, повторное назначение ничего не меняет
+// @Tags teams
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Param userID path string true "ID пользователя" example:u1
+// @Success 204 "Лид назначен"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Failure 404 {object} ErrorResponse "Команда или пользователь не найдены"
+// @Router /api/v1/teams/{teamName}/leads/{userID} [put]
+func (h *Handler) addTeamLead(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), teamName, "add_team_lead")) {
+		return
+	}
+
+	if err := h.teamService.AddLead(c.Request.Context(), teamName, c.Param("userID")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveTeamLead godoc
+// @Summary Снятие лида команды
+// @Tags teams
+// @Security BearerAuth
+// @Param teamName path string true "Название команды" example:backend
+// @Param userID path string true "ID пользователя" example:u1
+// @Success 204 "Лид снят"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Failure 404 {object} ErrorResponse "Пользователь не лид этой команды"
+// @Router /api/v1/teams/{teamName}/leads/{userID} [delete]
+func (h *Handler) removeTeamLead(c *gin.Context) {
+	teamName := c.Param("teamName")
+
+	if !allowed(c, h.accessService.CanManageTeam(currentPrincipal(c), teamName, "remove_team_lead")) {
+		return
+	}
+
+	if err := h.teamService.RemoveLead(c.Request.Context(), teamName, c.Param("userID")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -75,6 +75,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// PatchUserRequest изменение пользователя через /api/v1
+type PatchUserRequest struct {
+	IsActive *bool `json:"is_active" binding:"required" example:"false"`
+}
+
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id" binding:"required" example:"user-123"`
 	IsActive bool   `json:"is_active" example:"false"`
@@ -86,9 +91,11 @@ type SetUserActiveRequest struct {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body SetUserActiveRequest true "Данные пользователя" example:{"user_id":"user-123","is_active":false}
 // @Success 200 {object} UserResponse "Обновленный пользователь"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
 // @Failure 404 {object} ErrorResponse "Пользователь не найден"
 // @Router /users/setIsActive [post]
 func (h *Handler) setUserActive(c *gin.Context) {
@@ -97,7 +104,11 @@ func (h *Handler) setUserActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.SetUserActive(request.UserID, request.IsActive)
+	if !allowed(c, h.accessService.CanChangeUser(c.Request.Context(), currentPrincipal(c), request.UserID, "set_user_active")) {
+		return
+	}
+
+	user, err := h.userService.SetUserActive(c.Request.Context(), request.UserID, request.IsActive)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -112,7 +123,10 @@ func (h *Handler) setUserActive(c *gin.Context) {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user_id query string true "ID пользователя" example:user-123
+// @Param limit query int false "Размер страницы, по умолчанию 50, максимум 200" example:50
+// @Param cursor query string false "next_cursor из предыдущего ответа"
 // @Success 200 {object} UserPRsResponse "Список PR пользователя"
 // @Failure 400 {object} ErrorResponse "Ошибка валидации"
 // @Failure 404 {object} ErrorResponse "Пользователь не найден"
@@ -122,8 +136,86 @@ func (h *Handler) getUserReviews(c *gin.Context) {
 	if !validateRequiredParam(c, userID, "user_id") {
 		return
 	}
+	h.writeUserReviews(c, userID)
+}
+
+// GetUser godoc
+// @Summary Получение пользователя
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param userID path string true "ID пользователя" example:user-123
+// @Success 200 {object} UserResponse "Пользователь"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Router /api/v1/users/{userID} [get]
+func (h *Handler) getUser(c *gin.Context) {
+	user, err := h.userService.GetUser(c.Request.Context(), c.Param("userID"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UserResponse{User: user})
+}
+
+// PatchUser godoc
+// @Summary Изменение пользователя
+// @Description Активирует или деактивирует пользователя
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userID path string true "ID пользователя" example:user-123
+// @Param request body PatchUserRequest true "Новые значения" example:{"is_active":false}
+// @Success 200 {object} UserResponse "Обновленный пользователь"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Router /api/v1/users/{userID} [patch]
+func (h *Handler) patchUser(c *gin.Context) {
+	var request PatchUserRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if !allowed(c, h.accessService.CanChangeUser(c.Request.Context(), currentPrincipal(c), c.Param("userID"), "set_user_active")) {
+		return
+	}
+
+	user, err := h.userService.SetUserActive(c.Request.Context(), c.Param("userID"), *request.IsActive)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UserResponse{User: user})
+}
+
+// GetUserReviewsByID godoc
+// @Summary Получение назначенных PR пользователя
+// @Description Возвращает открытые PR, назначенные на пользователя для ревью
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param userID path string true "ID пользователя" example:user-123
+// @Param limit query int false "Размер страницы, по умолчанию 50, максимум 200" example:50
+// @Param cursor query string false "next_cursor из предыдущего ответа"
+// @Success 200 {object} UserPRsResponse "Список PR пользователя"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 404 {object} ErrorResponse "Пользователь не найден"
+// @Router /api/v1/users/{userID}/reviews [get]
+func (h *Handler) getUserReviewsByID(c *gin.Context) {
+	h.writeUserReviews(c, c.Param("userID"))
+}
+
+// writeUserReviews отдаёт страницу назначенных PR, общий код двух маршрутов
+func (h *Handler) writeUserReviews(c *gin.Context, userID string) {
+	var query PageQuery
+	if !validateQuery(c, &query) {
+		return
+	}
 
-	prs, err := h.userService.GetAssignedPRs(userID)
+	prs, next, err := h.userService.GetAssignedPRs(c.Request.Context(), userID, query.page())
 	if err != nil {
 		handleError(c, err)
 		return
@@ -132,5 +224,6 @@ func (h *Handler) getUserReviews(c *gin.Context) {
 	c.JSON(http.StatusOK, UserPRsResponse{
 		UserID:       userID,
 		PullRequests: prs,
+		NextCursor:   next,
 	})
 }
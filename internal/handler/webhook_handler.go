@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/integration"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookStatusProcessed = "processed"
+	webhookStatusIgnored   = "ignored"
+)
+
+// GitHubWebhook godoc
+// @Summary Вебхук GitHub
+// @Description Принимает события pull_request из GitHub. Подпись X-Hub-Signature-256 проверяется секретом GITHUB_WEBHOOK_SECRET. Поддерживаются opened, ready_for_review, closed (с мерджем и без), reopened и review_request_removed, остальные события игнорируются
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "Тип события GitHub"
+// @Param X-Hub-Signature-256 header string true "HMAC-SHA256 подпись тела запроса"
+// @Success 200 {object} WebhookResponse "Событие обработано или проигнорировано"
+// @Failure 400 {object} ErrorResponse "Некорректное тело события"
+// @Failure 401 {object} ErrorResponse "Неверная подпись"
+// @Failure 409 {object} ErrorResponse "Событие противоречит состоянию PR"
+// @Router /webhooks/github [post]
+func (h *Handler) githubWebhook(c *gin.Context) {
+	body, ok := readBody(c)
+	if !ok {
+		return
+	}
+
+	if !integration.VerifyGitHubSignature(h.githubSecret, body, c.GetHeader("X-Hub-Signature-256")) {
+		handleError(c, errors.ErrInvalidSignature)
+		return
+	}
+
+	if c.GetHeader("X-GitHub-Event") != "pull_request" {
+		// ping при подключении вебхука и прочие события
+		c.JSON(http.StatusOK, WebhookResponse{Status: webhookStatusIgnored})
+		return
+	}
+
+	event, err := integration.ParseGitHubPullRequestEvent(body)
+	if err != nil {
+		handleError(c, errors.NewError("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	h.applyWebhookEvent(c, event)
+}
+
+// GitLabWebhook godoc
+// @Summary Вебхук GitLab
+// @Description Принимает события Merge Request Hook из GitLab. Заголовок X-Gitlab-Token сверяется с GITLAB_WEBHOOK_TOKEN. Поддерживаются open, reopen, merge, close и update (снятие draft и снятие ревьюера), остальные события игнорируются
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Gitlab-Event header string true "Тип события GitLab"
+// @Param X-Gitlab-Token header string true "Секретный токен вебхука"
+// @Success 200 {object} WebhookResponse "Событие обработано или проигнорировано"
+// @Failure 400 {object} ErrorResponse "Некорректное тело события"
+// @Failure 401 {object} ErrorResponse "Неверный токен"
+// @Failure 409 {object} ErrorResponse "Событие противоречит состоянию PR"
+// @Router /webhooks/gitlab [post]
+func (h *Handler) gitlabWebhook(c *gin.Context) {
+	if !integration.VerifyGitLabToken(h.gitlabToken, c.GetHeader("X-Gitlab-Token")) {
+		handleError(c, errors.ErrInvalidSignature)
+		return
+	}
+
+	if c.GetHeader("X-Gitlab-Event") != "Merge Request Hook" {
+		c.JSON(http.StatusOK, WebhookResponse{Status: webhookStatusIgnored})
+		return
+	}
+
+	body, ok := readBody(c)
+	if !ok {
+		return
+	}
+
+	event, err := integration.ParseGitLabMergeRequestEvent(body)
+	if err != nil {
+		handleError(c, errors.NewError("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	h.applyWebhookEvent(c, event)
+}
+
+// applyWebhookEvent применяет нормализованное событие и отвечает источнику
+func (h *Handler) applyWebhookEvent(c *gin.Context, event *integration.Event) {
+	if event == nil {
+		c.JSON(http.StatusOK, WebhookResponse{Status: webhookStatusIgnored})
+		return
+	}
+
+	ctx := service.WithActor(c.Request.Context(), models.Actor{
+		Subject: "webhook:" + event.Source,
+		Name:    event.Source,
+		Method:  models.AuthMethodWebhook,
+		IP:      c.ClientIP(),
+	})
+	pr, err := h.prService.ApplyEvent(ctx, event)
+	if err != nil {
+		// события о PR и пользователях, которых сервис не ведёт, не считаются ошибкой
+		if errors.Is(err, errors.ErrPRNotFound) || errors.Is(err, errors.ErrNotAssigned) {
+			c.JSON(http.StatusOK, WebhookResponse{Status: webhookStatusIgnored, Reason: err.Error()})
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookResponse{Status: webhookStatusProcessed, PR: pr})
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"ReviewAssigner/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddWebhookSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required" example:"https://bot.example.com/hooks/review"`
+	Secret string   `json:"secret" example:"s3cret"`
+	Events []string `json:"events" example:"reviewer.assigned,pr.merged"`
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	ID int64 `json:"id" binding:"required" example:"1"`
+}
+
+// AddWebhookSubscription godoc
+// @Summary Создание подписки на вебхуки
+// @Description Подписывает URL на события pr.created, pr.status_changed, pr.merged, reviewer.assigned и reviewer.replaced. Пустой events — все события. Тело доставки подписывается HMAC-SHA256 секретом подписки в заголовке X-Webhook-Signature. Если secret не передан, он генерируется и возвращается только в этом ответе
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AddWebhookSubscriptionRequest true "Данные подписки" example:{"url":"https://bot.example.com/hooks/review","events":["reviewer.assigned"]}
+// @Success 201 {object} WebhookSubscriptionResponse "Созданная подписка"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Router /webhooks/subscriptions/add [post]
+func (h *Handler) addWebhookSubscription(c *gin.Context) {
+	var request AddWebhookSubscriptionRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	sub, err := h.webhookService.CreateSubscription(c.Request.Context(), request.URL, request.Secret, request.Events)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookSubscriptionResponse{
+		Subscription: sub,
+		Secret:       sub.Secret,
+	})
+}
+
+// ListWebhookSubscriptions godoc
+// @Summary Список подписок на вебхуки
+// @Description Возвращает все подписки без секретов
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} WebhookSubscriptionsResponse "Подписки"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Router /webhooks/subscriptions/list [get]
+func (h *Handler) listWebhookSubscriptions(c *gin.Context) {
+	subs, err := h.webhookService.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookSubscriptionsResponse{Subscriptions: subs})
+}
+
+// DeleteWebhookSubscription godoc
+// @Summary Удаление подписки на вебхуки
+// @Description Удаляет подписку вместе с журналом доставок
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteWebhookSubscriptionRequest true "ID подписки" example:{"id":1}
+// @Success 204 "Подписка удалена"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Failure 404 {object} ErrorResponse "Подписка не найдена"
+// @Router /webhooks/subscriptions/delete [post]
+func (h *Handler) deleteWebhookSubscription(c *gin.Context) {
+	var request DeleteWebhookSubscriptionRequest
+	if !validateRequest(c, &request) {
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), request.ID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Журнал доставок вебхуков
+// @Description Возвращает последние доставки подписки: статус, число попыток, код ответа и последнюю ошибку
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param subscription_id query int true "ID подписки" example:1
+// @Param limit query int false "Максимум записей, по умолчанию 50" example:50
+// @Success 200 {object} WebhookDeliveriesResponse "Доставки"
+// @Failure 400 {object} ErrorResponse "Ошибка валидации"
+// @Failure 403 {object} ErrorResponse "Нужна область admin"
+// @Failure 404 {object} ErrorResponse "Подписка не найдена"
+// @Router /webhooks/deliveries [get]
+func (h *Handler) getWebhookDeliveries(c *gin.Context) {
+	rawID := c.Query("subscription_id")
+	if !validateRequiredParam(c, rawID, "subscription_id") {
+		return
+	}
+	subscriptionID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		handleError(c, errors.NewError("INVALID_REQUEST", "subscription_id must be an integer"))
+		return
+	}
+
+	limit := 0
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			handleError(c, errors.NewError("INVALID_REQUEST", "limit must be an integer"))
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), subscriptionID, limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookDeliveriesResponse{
+		SubscriptionID: subscriptionID,
+		Deliveries:     deliveries,
+	})
+}
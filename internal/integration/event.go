@@ -0,0 +1,25 @@
+package integration
+
+// действия над PR, к которым сводятся события внешних систем
+const (
+	ActionCreate          = "create"
+	ActionReady           = "ready"
+	ActionMerge           = "merge"
+	ActionClose           = "close"
+	ActionReopen          = "reopen"
+	ActionReplaceReviewer = "replace_reviewer"
+)
+
+// Event нормализованное событие PR из GitHub или GitLab.
+// Пользователи внешней системы сопоставляются с user_id по логину.
+// Пустой AuthorID — внешняя система не сообщила автора PR.
+type Event struct {
+	Action        string
+	PullRequestID string
+	Title         string
+	AuthorID      string
+	ReviewerID    string
+	Draft         bool
+	Source        string
+	URL           string
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ReviewAssigner/internal/models"
+)
+
+const githubSignaturePrefix = "sha256="
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Number  int        `json:"number"`
+		Title   string     `json:"title"`
+		HTMLURL string     `json:"html_url"`
+		Draft   bool       `json:"draft"`
+		Merged  bool       `json:"merged"`
+		User    githubUser `json:"user"`
+	} `json:"pull_request"`
+	RequestedReviewer *githubUser `json:"requested_reviewer"`
+	Repository        struct {
+		ID       int64  `json:"id"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifyGitHubSignature проверяет заголовок X-Hub-Signature-256 (HMAC-SHA256 тела запроса)
+func VerifyGitHubSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, githubSignaturePrefix) {
+		return false
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, githubSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// GitHubPullRequestID строит pull_request_id для PR из GitHub
+func GitHubPullRequestID(repositoryID int64, number int) string {
+	return fmt.Sprintf("gh-%d-%d", repositoryID, number)
+}
+
+// ParseGitHubPullRequestEvent разбирает событие pull_request.
+// Для действий, которые сервис не обрабатывает, возвращает nil без ошибки.
+func ParseGitHubPullRequestEvent(body []byte) (*Event, error) {
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid pull_request payload: %w", err)
+	}
+
+	number := payload.PullRequest.Number
+	if number == 0 {
+		number = payload.Number
+	}
+	if payload.Repository.ID == 0 || number == 0 {
+		return nil, fmt.Errorf("pull_request payload without repository id or number")
+	}
+
+	event := &Event{
+		PullRequestID: GitHubPullRequestID(payload.Repository.ID, number),
+		Title:         payload.PullRequest.Title,
+		AuthorID:      payload.PullRequest.User.Login,
+		Draft:         payload.PullRequest.Draft,
+		Source:        models.PRSourceGitHub,
+		URL:           payload.PullRequest.HTMLURL,
+	}
+
+	switch payload.Action {
+	case "opened":
+		event.Action = ActionCreate
+	case "ready_for_review":
+		event.Action = ActionReady
+	case "closed":
+		if payload.PullRequest.Merged {
+			event.Action = ActionMerge
+		} else {
+			event.Action = ActionClose
+		}
+	case "reopened":
+		event.Action = ActionReopen
+	case "review_request_removed":
+		if payload.RequestedReviewer == nil {
+			// запрос ревью у команды GitHub, а не у пользователя
+			return nil, nil
+		}
+		event.Action = ActionReplaceReviewer
+		event.ReviewerID = payload.RequestedReviewer.Login
+	default:
+		return nil, nil
+	}
+
+	return event, nil
+}
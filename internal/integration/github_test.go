@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return body
+}
+
+func githubSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := loadFixture(t, "github_pull_request_opened.json")
+	signature := githubSignature("s3cret", body)
+
+	assert.True(t, VerifyGitHubSignature("s3cret", body, signature))
+	assert.False(t, VerifyGitHubSignature("other", body, signature))
+	assert.False(t, VerifyGitHubSignature("s3cret", append(body, ' '), signature))
+	assert.False(t, VerifyGitHubSignature("s3cret", body, "sha1=abcdef"))
+	assert.False(t, VerifyGitHubSignature("s3cret", body, "sha256=not-hex"))
+	assert.False(t, VerifyGitHubSignature("", body, githubSignature("", body)))
+}
+
+func TestParseGitHubPullRequestEvent(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		action     string
+		prID       string
+		authorID   string
+		reviewerID string
+	}{
+		{"github_pull_request_opened.json", ActionCreate, "gh-556677-42", "u1", ""},
+		{"github_pull_request_ready_for_review.json", ActionReady, "gh-556677-43", "u2", ""},
+		{"github_pull_request_closed_merged.json", ActionMerge, "gh-556677-42", "u1", ""},
+		{"github_pull_request_review_request_removed.json", ActionReplaceReviewer, "gh-556677-42", "u1", "u2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			event, err := ParseGitHubPullRequestEvent(loadFixture(t, tt.fixture))
+			require.NoError(t, err)
+			require.NotNil(t, event)
+
+			assert.Equal(t, tt.action, event.Action)
+			assert.Equal(t, tt.prID, event.PullRequestID)
+			assert.Equal(t, tt.authorID, event.AuthorID)
+			assert.Equal(t, tt.reviewerID, event.ReviewerID)
+			assert.Equal(t, "github", event.Source)
+		})
+	}
+}
+
+func TestParseGitHubPullRequestEvent_Opened(t *testing.T) {
+	event, err := ParseGitHubPullRequestEvent(loadFixture(t, "github_pull_request_opened.json"))
+	require.NoError(t, err)
+	require.NotNil(t, event)
+
+	assert.Equal(t, "Add least-loaded strategy", event.Title)
+	assert.Equal(t, "https://github.com/acme/review-assigner/pull/42", event.URL)
+	assert.False(t, event.Draft)
+}
+
+func TestParseGitHubPullRequestEvent_Ignored(t *testing.T) {
+	event, err := ParseGitHubPullRequestEvent(loadFixture(t, "github_pull_request_labeled.json"))
+	require.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestParseGitHubPullRequestEvent_Invalid(t *testing.T) {
+	_, err := ParseGitHubPullRequestEvent([]byte(`{"action":"opened"}`))
+	assert.Error(t, err)
+
+	_, err = ParseGitHubPullRequestEvent([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+
+	"ReviewAssigner/internal/models"
+)
+
+const gitlabObjectKindMergeRequest = "merge_request"
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabMergeRequestEvent struct {
+	ObjectKind string     `json:"object_kind"`
+	User       gitlabUser `json:"user"`
+	Project    struct {
+		ID int64 `json:"id"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		URL            string `json:"url"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+		Reviewers *struct {
+			Previous []gitlabUser `json:"previous"`
+			Current  []gitlabUser `json:"current"`
+		} `json:"reviewers"`
+	} `json:"changes"`
+}
+
+// VerifyGitLabToken сравнивает заголовок X-Gitlab-Token с секретом вебхука
+func VerifyGitLabToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+// GitLabPullRequestID строит pull_request_id для merge request из GitLab
+func GitLabPullRequestID(projectID int64, iid int) string {
+	return fmt.Sprintf("gl-%d-%d", projectID, iid)
+}
+
+// ParseGitLabMergeRequestEvent разбирает событие Merge Request Hook.
+// Для действий, которые сервис не обрабатывает, возвращает nil без ошибки.
+func ParseGitLabMergeRequestEvent(body []byte) (*Event, error) {
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid merge_request payload: %w", err)
+	}
+
+	if payload.ObjectKind != gitlabObjectKindMergeRequest {
+		return nil, nil
+	}
+
+	attrs := payload.ObjectAttributes
+	if payload.Project.ID == 0 || attrs.IID == 0 {
+		return nil, fmt.Errorf("merge_request payload without project id or iid")
+	}
+
+	event := &Event{
+		PullRequestID: GitLabPullRequestID(payload.Project.ID, attrs.IID),
+		Title:         attrs.Title,
+		Draft:         attrs.Draft || attrs.WorkInProgress,
+		Source:        models.PRSourceGitLab,
+		URL:           attrs.URL,
+	}
+
+	switch attrs.Action {
+	case "open":
+		// GitLab передаёт автора MR только числовым author_id, а открывает MR
+		// сам автор. В остальных событиях user — тот, кто их вызвал, поэтому
+		// AuthorID остаётся пустым и неизвестный сервису MR не заводится.
+		event.Action = ActionCreate
+		event.AuthorID = payload.User.Username
+	case "reopen":
+		event.Action = ActionReopen
+	case "merge":
+		event.Action = ActionMerge
+	case "close":
+		event.Action = ActionClose
+	case "update":
+		return gitlabUpdateEvent(event, &payload), nil
+	default:
+		return nil, nil
+	}
+
+	return event, nil
+}
+
+// gitlabUpdateEvent определяет действие по полю changes события update:
+// снятие draft переводит PR в OPEN, снятие ревьюера — замена ревьюера
+func gitlabUpdateEvent(event *Event, payload *gitlabMergeRequestEvent) *Event {
+	changes := payload.Changes
+
+	if changes.Draft != nil && changes.Draft.Previous && !changes.Draft.Current {
+		event.Action = ActionReady
+		event.Draft = false
+		return event
+	}
+
+	if changes.Reviewers != nil {
+		current := make(map[string]bool, len(changes.Reviewers.Current))
+		for _, reviewer := range changes.Reviewers.Current {
+			current[reviewer.Username] = true
+		}
+		for _, reviewer := range changes.Reviewers.Previous {
+			if !current[reviewer.Username] {
+				event.Action = ActionReplaceReviewer
+				event.ReviewerID = reviewer.Username
+				return event
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyGitLabToken(t *testing.T) {
+	assert.True(t, VerifyGitLabToken("s3cret", "s3cret"))
+	assert.False(t, VerifyGitLabToken("s3cret", "other"))
+	assert.False(t, VerifyGitLabToken("s3cret", ""))
+	assert.False(t, VerifyGitLabToken("", ""))
+}
+
+func TestParseGitLabMergeRequestEvent(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		action     string
+		prID       string
+		reviewerID string
+		authorID   string
+	}{
+		{"gitlab_merge_request_open.json", ActionCreate, "gl-1234-17", "", "u1"},
+		// user в остальных событиях — кто их вызвал, а не автор MR
+		{"gitlab_merge_request_update_ready.json", ActionReady, "gl-1234-18", "", ""},
+		{"gitlab_merge_request_update_reviewers.json", ActionReplaceReviewer, "gl-1234-17", "u2", ""},
+		{"gitlab_merge_request_merge.json", ActionMerge, "gl-1234-17", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			event, err := ParseGitLabMergeRequestEvent(loadFixture(t, tt.fixture))
+			require.NoError(t, err)
+			require.NotNil(t, event)
+
+			assert.Equal(t, tt.action, event.Action)
+			assert.Equal(t, tt.prID, event.PullRequestID)
+			assert.Equal(t, tt.reviewerID, event.ReviewerID)
+			assert.Equal(t, tt.authorID, event.AuthorID)
+			assert.Equal(t, "gitlab", event.Source)
+		})
+	}
+}
+
+func TestParseGitLabMergeRequestEvent_Open(t *testing.T) {
+	event, err := ParseGitLabMergeRequestEvent(loadFixture(t, "gitlab_merge_request_open.json"))
+	require.NoError(t, err)
+	require.NotNil(t, event)
+
+	assert.Equal(t, "u1", event.AuthorID)
+	assert.Equal(t, "Add round-robin strategy", event.Title)
+	assert.Equal(t, "https://gitlab.example.com/platform/review-assigner/-/merge_requests/17", event.URL)
+	assert.False(t, event.Draft)
+}
+
+func TestParseGitLabMergeRequestEvent_Ignored(t *testing.T) {
+	event, err := ParseGitLabMergeRequestEvent(loadFixture(t, "gitlab_merge_request_update_labels.json"))
+	require.NoError(t, err)
+	assert.Nil(t, event)
+
+	event, err = ParseGitLabMergeRequestEvent([]byte(`{"object_kind":"push"}`))
+	require.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestParseGitLabMergeRequestEvent_Invalid(t *testing.T) {
+	_, err := ParseGitLabMergeRequestEvent([]byte(`{"object_kind":"merge_request"}`))
+	assert.Error(t, err)
+}
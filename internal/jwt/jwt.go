@@ -0,0 +1,356 @@
+// Package jwt проверяет подписанные JWT внутреннего SSO по ключам из JWKS.
+// Поддерживаются RS256/384/512 и ES256/384/512, симметричные алгоритмы и
+// "none" отклоняются.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidToken токен не разобран, подпись или утверждения не прошли проверку
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpired срок действия токена истёк
+	ErrExpired = errors.New("token expired")
+	// ErrKeysUnavailable JWKS не удалось загрузить, проверить подпись нечем
+	ErrKeysUnavailable = errors.New("jwks unavailable")
+)
+
+// допуск на расхождение часов с SSO
+const leeway = time.Minute
+
+// Config источник ключей и ожидаемые утверждения токена
+type Config struct {
+	// JWKS путь к файлу или http(s)-адрес набора ключей
+	JWKS string
+	// Issuer ожидаемый iss, пусто — не проверяется
+	Issuer string
+	// Audience ожидаемый aud, пусто — не проверяется
+	Audience string
+	// GroupsClaim утверждение со списком групп, по умолчанию groups
+	GroupsClaim string
+	// RefreshInterval как часто можно перечитывать JWKS, встретив незнакомый kid
+	RefreshInterval time.Duration
+}
+
+// Claims проверенные утверждения токена
+type Claims struct {
+	Subject   string
+	Name      string
+	Groups    []string
+	Issuer    string
+	ExpiresAt time.Time
+}
+
+// Verifier проверяет JWT. Ключи загружаются при создании и перечитываются,
+// когда токен подписан ещё неизвестным ключом (ротация на стороне SSO).
+type Verifier struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      keySet
+	fetchedAt time.Time
+
+	now func() time.Time
+}
+
+// NewVerifier загружает JWKS и возвращает готовый к работе Verifier
+func NewVerifier(ctx context.Context, cfg Config) (*Verifier, error) {
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = time.Minute
+	}
+
+	v := &Verifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Verifier) refresh(ctx context.Context) error {
+	keys, err := loadKeySet(ctx, v.client, v.cfg.JWKS)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKeysUnavailable, err)
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = v.now()
+	v.mu.Unlock()
+	return nil
+}
+
+// key ключ по kid; незнакомый kid перечитывает JWKS не чаще RefreshInterval
+func (v *Verifier) key(ctx context.Context, kid string) (verifyKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	stale := v.now().Sub(v.fetchedAt) >= v.cfg.RefreshInterval
+	v.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return verifyKey{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		return verifyKey{}, err
+	}
+
+	v.mu.RLock()
+	key, ok = v.keys[kid]
+	v.mu.RUnlock()
+	if !ok {
+		return verifyKey{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+// LooksLikeJWT отличает JWT от непрозрачного API-токена: три части через точку
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify проверяет подпись, срок действия, iss и aud и возвращает утверждения
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidToken, err)
+	}
+	key, err := v.key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != "" && key.alg != h.Alg {
+		return nil, fmt.Errorf("%w: key %q does not allow %s", ErrInvalidToken, h.Kid, h.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %w", ErrInvalidToken, err)
+	}
+	if err := verifySignature(h.Alg, key.key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var raw map[string]interface{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, fmt.Errorf("%w: payload: %w", ErrInvalidToken, err)
+	}
+	return v.claims(raw)
+}
+
+func (v *Verifier) claims(raw map[string]interface{}) (*Claims, error) {
+	now := v.now()
+
+	exp, ok := numericDate(raw["exp"])
+	if !ok {
+		return nil, fmt.Errorf("%w: exp is required", ErrInvalidToken)
+	}
+	if now.After(exp.Add(leeway)) {
+		return nil, fmt.Errorf("%w: at %s", ErrExpired, exp.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := numericDate(raw["nbf"]); ok && now.Add(leeway).Before(nbf) {
+		return nil, fmt.Errorf("%w: not valid before %s", ErrInvalidToken, nbf.UTC().Format(time.RFC3339))
+	}
+
+	claims := &Claims{
+		Subject:   stringClaim(raw["sub"]),
+		Name:      stringClaim(raw["preferred_username"]),
+		Groups:    stringList(raw[v.cfg.GroupsClaim]),
+		Issuer:    stringClaim(raw["iss"]),
+		ExpiresAt: exp,
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: sub is required", ErrInvalidToken)
+	}
+	if v.cfg.Issuer != "" && claims.Issuer != v.cfg.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	// aud бывает строкой или списком строк
+	if v.cfg.Audience != "" && !slices.Contains(stringList(raw["aud"]), v.cfg.Audience) {
+		return nil, fmt.Errorf("%w: audience does not include %q", ErrInvalidToken, v.cfg.Audience)
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func numericDate(value interface{}) (time.Time, bool) {
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func stringClaim(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func hashFor(alg string) (crypto.Hash, error) {
+	if len(alg) != 5 {
+		return 0, fmt.Errorf("unsupported alg %q", alg)
+	}
+	switch alg[2:] {
+	case "256":
+		return crypto.SHA256, nil
+	case "384":
+		return crypto.SHA384, nil
+	case "512":
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("unsupported alg %q", alg)
+}
+
+func digest(hash crypto.Hash, data string) []byte {
+	switch hash {
+	case crypto.SHA384:
+		sum := sha512.Sum384([]byte(data))
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512([]byte(data))
+		return sum[:]
+	default:
+		sum := sha256.Sum256([]byte(data))
+		return sum[:]
+	}
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	hash, err := hashFor(alg)
+	if err != nil {
+		return err
+	}
+	sum := digest(hash, signed)
+
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("alg %s does not match key type", alg)
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, sum, signature)
+
+	case strings.HasPrefix(alg, "ES"):
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("alg %s does not match key type", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("bad signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, sum, r, s) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported alg %q", alg)
+}
+
+// Sign выпускает токен, подписанный ключом RSA (RS256) или ECDSA P-256 (ES256).
+// Сервису это не нужно, пригодится тестам и локальной отладке без SSO.
+func Sign(claims map[string]interface{}, kid string, key crypto.Signer) (string, error) {
+	var alg string
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		alg = "RS256"
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().Name != "P-256" {
+			return "", fmt.Errorf("only P-256 keys are supported")
+		}
+		alg = "ES256"
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+
+	h, err := json.Marshal(header{Alg: alg, Kid: kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(h) + "." + enc.EncodeToString(payload)
+	sum := digest(crypto.SHA256, signed)
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, sum)
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, sum)
+		if err == nil {
+			signature = make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + enc.EncodeToString(signature), nil
+}
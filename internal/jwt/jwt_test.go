@@ -0,0 +1,153 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJWKS(t *testing.T, path string, keys map[string]crypto.PublicKey) {
+	t.Helper()
+	var jwks JWKS
+	for kid, pub := range keys {
+		jwk, err := NewJWK(kid, pub)
+		require.NoError(t, err)
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func claimsFor(sub string, exp time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":                sub,
+		"preferred_username": "Alice",
+		"groups":             []string{"backend", "reviewers"},
+		"iss":                "https://sso.example.com",
+		"aud":                []string{"review-assigner"},
+		"exp":                exp.Unix(),
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, map[string]crypto.PublicKey{"rsa-1": &rsaKey.PublicKey, "ec-1": &ecKey.PublicKey})
+
+	v, err := NewVerifier(context.Background(), Config{
+		JWKS:     path,
+		Issuer:   "https://sso.example.com",
+		Audience: "review-assigner",
+	})
+	require.NoError(t, err)
+
+	for kid, key := range map[string]crypto.Signer{"rsa-1": rsaKey, "ec-1": ecKey} {
+		token, err := Sign(claimsFor("u1", time.Now().Add(time.Hour)), kid, key)
+		require.NoError(t, err)
+
+		claims, err := v.Verify(context.Background(), token)
+		require.NoError(t, err, kid)
+		assert.Equal(t, "u1", claims.Subject)
+		assert.Equal(t, "Alice", claims.Name)
+		assert.Equal(t, []string{"backend", "reviewers"}, claims.Groups)
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, map[string]crypto.PublicKey{"k1": &key.PublicKey})
+	v, err := NewVerifier(context.Background(), Config{JWKS: path, Audience: "review-assigner"})
+	require.NoError(t, err)
+
+	sign := func(claims map[string]interface{}, kid string, key crypto.Signer) string {
+		token, err := Sign(claims, kid, key)
+		require.NoError(t, err)
+		return token
+	}
+	valid := sign(claimsFor("u1", time.Now().Add(time.Hour)), "k1", key)
+	parts := strings.Split(valid, ".")
+
+	wrongAudience := claimsFor("u1", time.Now().Add(time.Hour))
+	wrongAudience["aud"] = "another-service"
+
+	cases := map[string]struct {
+		token string
+		want  error
+	}{
+		"expired":        {sign(claimsFor("u1", time.Now().Add(-time.Hour)), "k1", key), ErrExpired},
+		"foreign key":    {sign(claimsFor("u1", time.Now().Add(time.Hour)), "k1", other), ErrInvalidToken},
+		"unknown kid":    {sign(claimsFor("u1", time.Now().Add(time.Hour)), "k2", key), ErrInvalidToken},
+		"wrong audience": {sign(wrongAudience, "k1", key), ErrInvalidToken},
+		"alg none":       {"eyJhbGciOiJub25lIiwia2lkIjoiazEifQ." + parts[1] + ".", ErrInvalidToken},
+		"malformed":      {"not-a-jwt", ErrInvalidToken},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), tc.token)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestVerifier_RefreshFromURL(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	current := map[string]crypto.PublicKey{"old": &oldKey.PublicKey}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		var jwks JWKS
+		for kid, pub := range current {
+			jwk, err := NewJWK(kid, pub)
+			require.NoError(t, err)
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	defer server.Close()
+
+	v, err := NewVerifier(context.Background(), Config{JWKS: server.URL, RefreshInterval: time.Hour})
+	require.NoError(t, err)
+
+	// SSO сменил ключ
+	current = map[string]crypto.PublicKey{"new": &newKey.PublicKey}
+	token, err := Sign(claimsFor("u1", time.Now().Add(3*time.Hour)), "new", newKey)
+	require.NoError(t, err)
+
+	_, err = v.Verify(context.Background(), token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "JWKS перечитывается не чаще RefreshInterval")
+	assert.Equal(t, 1, fetches)
+
+	v.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	claims, err := v.Verify(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.Subject)
+	assert.Equal(t, 2, fetches)
+}
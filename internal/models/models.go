@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type User struct {
 	UserID    string    `json:"user_id" db:"user_id"`
@@ -17,9 +20,50 @@ type TeamMember struct {
 	IsActive bool   `json:"is_active" db:"is_active"`
 }
 
+// TeamSettings настройки команды, влияющие на назначение ревьюеров
+type TeamSettings struct {
+	SelectionStrategy string `json:"selection_strategy" db:"selection_strategy"`
+	ReviewerCount     int    `json:"reviewer_count" db:"reviewer_count"`
+
+	// политика мерджа
+	RequiredApprovals       int  `json:"required_approvals" db:"required_approvals"`
+	BlockOnChangesRequested bool `json:"block_on_changes_requested" db:"block_on_changes_requested"`
+}
+
 type Team struct {
-	TeamName string       `json:"team_name" db:"team_name"`
-	Members  []TeamMember `json:"members" db:"-"`
+	TeamName string `json:"team_name" db:"team_name"`
+	TeamSettings
+	Members []TeamMember `json:"members" db:"-"`
+}
+
+// статусы PR
+const (
+	PRStatusDraft  = "DRAFT"
+	PRStatusOpen   = "OPEN"
+	PRStatusMerged = "MERGED"
+	PRStatusClosed = "CLOSED"
+)
+
+// системы-источники PR
+const (
+	PRSourceAPI    = "api"
+	PRSourceGitHub = "github"
+	PRSourceGitLab = "gitlab"
+)
+
+// состояния ревью у назначенного ревьюера
+const (
+	ReviewStatePending          = "PENDING"
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+)
+
+type Review struct {
+	ReviewerID string     `json:"reviewer_id" db:"reviewer_id"`
+	State      string     `json:"state" db:"review_state"`
+	AssignedAt *time.Time `json:"assignedAt,omitempty" db:"assigned_at"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty" db:"reviewed_at"`
 }
 
 type PullRequest struct {
@@ -27,14 +71,296 @@ type PullRequest struct {
 	PullRequestName   string     `json:"pull_request_name" db:"pull_request_name"`
 	AuthorID          string     `json:"author_id" db:"author_id"`
 	Status            string     `json:"status" db:"status"`
+	Source            string     `json:"source" db:"source"`
+	SourceURL         string     `json:"source_url,omitempty" db:"source_url"`
 	AssignedReviewers []string   `json:"assigned_reviewers" db:"-"`
+	Reviews           []Review   `json:"reviews" db:"-"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty" db:"created_at"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty" db:"closed_at"`
 }
 
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id" db:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name" db:"pull_request_name"`
-	AuthorID        string `json:"author_id" db:"author_id"`
-	Status          string `json:"status" db:"status"`
+	PullRequestID   string     `json:"pull_request_id" db:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name" db:"pull_request_name"`
+	AuthorID        string     `json:"author_id" db:"author_id"`
+	Status          string     `json:"status" db:"status"`
+	CreatedAt       *time.Time `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// доменные события, которые пишутся в outbox вместе с изменениями PR
+const (
+	EventPRCreated        = "pr.created"
+	EventPRStatusChanged  = "pr.status_changed"
+	EventPRMerged         = "pr.merged"
+	EventReviewerAssigned = "reviewer.assigned"
+	EventReviewerReplaced = "reviewer.replaced"
+)
+
+// EventTypes все доменные события, на них же можно подписаться вебхуком
+var EventTypes = []string{
+	EventPRCreated,
+	EventPRStatusChanged,
+	EventPRMerged,
+	EventReviewerAssigned,
+	EventReviewerReplaced,
+}
+
+// Event запись outbox: событие сохраняется в той же транзакции, что и изменение,
+// и публикуется диспетчером после коммита
+type Event struct {
+	ID          int64      `json:"id" db:"id"`
+	EventType   string     `json:"event_type" db:"event_type"`
+	AggregateID string     `json:"aggregate_id" db:"aggregate_id"`
+	Payload     string     `json:"payload" db:"payload"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	LastError   string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty" db:"created_at"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty" db:"published_at"`
+	// ClaimedUntil до этого времени событие публикует забравший его диспетчер
+	ClaimedUntil *time.Time `json:"-" db:"claimed_until"`
+}
+
+// PRCreatedData данные события pr.created
+type PRCreatedData struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	Status          string `json:"status"`
+	Source          string `json:"source"`
+	SourceURL       string `json:"source_url,omitempty"`
+}
+
+// PRStatusChangedData данные события pr.status_changed
+type PRStatusChangedData struct {
+	PullRequestID string `json:"pull_request_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+}
+
+// PRMergedData данные события pr.merged
+type PRMergedData struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+// ReviewerAssignedData данные события reviewer.assigned
+type ReviewerAssignedData struct {
+	PullRequestID string   `json:"pull_request_id"`
+	ReviewerIDs   []string `json:"reviewer_ids"`
+}
+
+// ReviewerReplacedData данные события reviewer.replaced
+type ReviewerReplacedData struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+}
+
+// статусы доставки вебхука
+const (
+	DeliveryStatusPending   = "PENDING"
+	DeliveryStatusDelivered = "DELIVERED"
+	DeliveryStatusFailed    = "FAILED"
+)
+
+// WebhookSubscription подписка на исходящие вебхуки.
+// Пустой Events означает подписку на все события.
+type WebhookSubscription struct {
+	ID        int64      `json:"id" db:"id"`
+	URL       string     `json:"url" db:"url"`
+	Secret    string     `json:"-" db:"secret"`
+	Events    []string   `json:"events" db:"-"`
+	IsActive  bool       `json:"is_active" db:"is_active"`
+	CreatedAt *time.Time `json:"createdAt,omitempty" db:"created_at"`
+}
+
+// Matches проверяет, подписана ли подписка на событие
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	if !s.IsActive {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery доставка события одной подписке. Пока Status PENDING,
+// очередная попытка выполняется не раньше NextAttemptAt.
+type WebhookDelivery struct {
+	ID             int64      `json:"id" db:"id"`
+	SubscriptionID int64      `json:"subscription_id" db:"subscription_id"`
+	EventID        *int64     `json:"event_id,omitempty" db:"event_id"`
+	EventType      string     `json:"event_type" db:"event_type"`
+	Payload        string     `json:"payload" db:"payload"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	ResponseCode   *int       `json:"response_code,omitempty" db:"response_code"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty" db:"created_at"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty" db:"delivered_at"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+}
+
+// IdempotencyRecord первый ответ на запрос с заголовком Idempotency-Key.
+// StatusCode 0 — запрос с этим ключом ещё выполняется; после LockedUntil
+// такой запрос считается упавшим и ключ можно занять повтором.
+type IdempotencyRecord struct {
+	Key          string     `db:"idempotency_key"`
+	Scope        string     `db:"scope"`
+	RequestHash  string     `db:"request_hash"`
+	StatusCode   int        `db:"status_code"`
+	ContentType  string     `db:"content_type"`
+	ResponseBody string     `db:"response_body"`
+	CreatedAt    time.Time  `db:"created_at"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	LockedUntil  *time.Time `db:"locked_until"`
+}
+
+// области доступа API-токенов
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// Scopes все области доступа, от узкой к широкой
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// APIToken токен доступа к API. В хранилище только хэш, сам токен
+// показывается один раз при создании.
+type APIToken struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Prefix    string     `json:"prefix" db:"token_prefix"`
+	Scopes    []string   `json:"scopes" db:"-"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	CreatedAt *time.Time `json:"createdAt,omitempty" db:"created_at"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// HasScope проверяет доступ: admin включает write, write включает read
+func (t *APIToken) HasScope(scope string) bool {
+	return hasScope(t.Scopes, scope)
+}
+
+func hasScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope ||
+			g == ScopeAdmin ||
+			g == ScopeWrite && scope == ScopeRead {
+			return true
+		}
+	}
+	return false
+}
+
+// способы аутентификации запроса
+const (
+	AuthMethodAPIToken = "api_token"
+	AuthMethodJWT      = "jwt"
+	// AuthMethodWebhook вебхуки GitHub и GitLab, проверенные подписью
+	AuthMethodWebhook = "webhook"
+)
+
+// роли автора запроса
+const (
+	// RoleOrgAdmin любые операции, у него область admin
+	RoleOrgAdmin = "org_admin"
+	// RoleTeamLead управляет участниками и PR команд из team_leads
+	RoleTeamLead = "team_lead"
+	// RoleMember меняет только себя и PR, где он автор или ревьюер
+	RoleMember = "member"
+	// RoleBot API-токен интеграции: работает с PR, но не с людьми и командами
+	RoleBot = "bot"
+)
+
+// Principal кто делает запрос: API-токен или человек, вошедший через SSO
+type Principal struct {
+	// Subject устойчивый идентификатор: token:<id> или sub из JWT
+	Subject string `json:"subject"`
+	Name    string `json:"name"`
+	Method  string `json:"method"`
+	Role    string `json:"role"`
+	// LeadOf команды, которыми руководит пользователь
+	LeadOf []string `json:"lead_of,omitempty"`
+	// UserID пользователь сервиса, от имени которого пришёл запрос; пусто для API-токенов
+	UserID string   `json:"user_id,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	Scopes []string `json:"scopes"`
+	// TokenID API-токен запроса
+	TokenID   int64      `json:"token_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return hasScope(p.Scopes, scope)
+}
+
+// Leads проверяет, что автор запроса — лид команды
+func (p *Principal) Leads(teamName string) bool {
+	for _, team := range p.LeadOf {
+		if team == teamName {
+			return true
+		}
+	}
+	return false
+}
+
+// операции журнала аудита
+const (
+	AuditTeamCreated      = "team.create"
+	AuditUserActiveSet    = "user.set_active"
+	AuditUsersDeactivated = "team.deactivate_users"
+	AuditPRCreated        = "pr.create"
+	AuditPRMerged         = "pr.merge"
+	AuditReviewerReplaced = "pr.replace_reviewer"
+	AuditPRReady          = "pr.ready"
+	AuditPRClosed         = "pr.close"
+	AuditPRReopened       = "pr.reopen"
+	AuditPRReviewed       = "pr.review"
+	AuditTeamSettings     = "team.update_settings"
+	AuditTeamDeleted      = "team.delete"
+	AuditTeamLeadAdded    = "team.add_lead"
+	AuditTeamLeadRemoved  = "team.remove_lead"
+	AuditTokenCreated     = "token.create"
+	AuditTokenRevoked     = "token.revoke"
+)
+
+// сущности журнала аудита
+const (
+	AuditEntityTeam  = "team"
+	AuditEntityUser  = "user"
+	AuditEntityPR    = "pull_request"
+	AuditEntityToken = "api_token"
+)
+
+// Actor кто и откуда выполняет изменение, попадает в журнал аудита
+type Actor struct {
+	// Subject как в Principal: token:<id>, sub из JWT или webhook:<источник>
+	Subject string
+	Name    string
+	Method  string
+	IP      string
+}
+
+// AuditEntry запись журнала аудита. Before и After — состояние сущности
+// в JSON до и после операции, null для создания.
+type AuditEntry struct {
+	ID          int64           `json:"id"`
+	Actor       string          `json:"actor"`
+	ActorName   string          `json:"actor_name,omitempty"`
+	ActorMethod string          `json:"actor_method,omitempty"`
+	Operation   string          `json:"operation"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Before      json.RawMessage `json:"before" swaggertype:"object"`
+	After       json.RawMessage `json:"after" swaggertype:"object"`
+	IP          string          `json:"ip,omitempty"`
+	CreatedAt   *time.Time      `json:"createdAt,omitempty"`
 }
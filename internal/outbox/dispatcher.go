@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// параметры диспетчера по умолчанию
+const (
+	DefaultInterval    = time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 10
+	// DefaultClaimLease сколько пачка событий закреплена за диспетчером: события
+	// упавшего экземпляра публикуются снова, когда срок истечёт
+	DefaultClaimLease = time.Minute
+)
+
+// Sink получатель событий из outbox. Доставка «хотя бы один раз»:
+// при ошибке любого sink событие будет отправлено повторно во все sink'и,
+// поэтому Handle должен быть идемпотентным по Event.ID.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, event models.Event) error
+}
+
+// Dispatcher периодически забирает неопубликованные события и передаёт их sink'ам.
+// Экземпляры сервиса на одной базе делят события: забранную пачку другие
+// диспетчеры пропускают, пока не истечёт claimLease.
+type Dispatcher struct {
+	eventRepo   repository.EventRepository
+	sinks       []Sink
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	claimLease  time.Duration
+	logger      *slog.Logger
+}
+
+func NewDispatcher(eventRepo repository.EventRepository, logger *slog.Logger, sinks ...Sink) *Dispatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Dispatcher{
+		eventRepo:   eventRepo,
+		sinks:       sinks,
+		interval:    DefaultInterval,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: DefaultMaxAttempts,
+		claimLease:  DefaultClaimLease,
+		logger:      logger,
+	}
+}
+
+// AddSink подключает ещё одного получателя событий
+func (d *Dispatcher) AddSink(sink Sink) {
+	d.sinks = append(d.sinks, sink)
+}
+
+// SetInterval задаёт период опроса outbox
+func (d *Dispatcher) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		d.interval = interval
+	}
+}
+
+// Run публикует события до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("outbox dispatcher started", "interval", d.interval, "sinks", len(d.sinks))
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil {
+			d.logger.Error("failed to dispatch outbox events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending публикует одну пачку событий и возвращает число опубликованных
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	events, err := d.eventRepo.ClaimPendingEvents(ctx, d.batchSize, d.maxAttempts, time.Now().Add(d.claimLease))
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := d.dispatch(ctx, event); err != nil {
+			d.logger.Warn("failed to publish event",
+				"event_id", event.ID,
+				"event_type", event.EventType,
+				"attempt", event.Attempts+1,
+				"error", err)
+
+			if markErr := d.eventRepo.MarkEventFailed(ctx, event.ID, err.Error()); markErr != nil {
+				d.logger.Error("failed to mark event as failed", "event_id", event.ID, "error", markErr)
+			}
+			if event.Attempts+1 >= d.maxAttempts {
+				d.logger.Error("event dropped after max attempts",
+					"event_id", event.ID, "event_type", event.EventType, "attempts", d.maxAttempts)
+			}
+			continue
+		}
+
+		if err := d.eventRepo.MarkEventPublished(ctx, event.ID); err != nil {
+			d.logger.Error("failed to mark event as published", "event_id", event.ID, "error", err)
+			continue
+		}
+		published++
+	}
+
+	if published > 0 {
+		d.logger.Debug("outbox events published", "count", published)
+	}
+	return published, nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event models.Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Handle(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubEventRepo struct {
+	events    []models.Event
+	published []int64
+	failed    map[int64]string
+}
+
+func (r *stubEventRepo) GetPendingEvents(_ context.Context, limit, maxAttempts int) ([]models.Event, error) {
+	var pending []models.Event
+	for _, event := range r.events {
+		if event.PublishedAt == nil && event.Attempts < maxAttempts && len(pending) < limit {
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (r *stubEventRepo) ClaimPendingEvents(ctx context.Context, limit, maxAttempts int, _ time.Time) ([]models.Event, error) {
+	return r.GetPendingEvents(ctx, limit, maxAttempts)
+}
+
+func (r *stubEventRepo) MarkEventPublished(_ context.Context, id int64) error {
+	r.published = append(r.published, id)
+	return nil
+}
+
+func (r *stubEventRepo) MarkEventFailed(_ context.Context, id int64, lastError string) error {
+	if r.failed == nil {
+		r.failed = make(map[int64]string)
+	}
+	r.failed[id] = lastError
+	return nil
+}
+
+type recordingSink struct {
+	name    string
+	handled []int64
+	failOn  int64
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Handle(_ context.Context, event models.Event) error {
+	if event.ID == s.failOn {
+		return fmt.Errorf("unavailable")
+	}
+	s.handled = append(s.handled, event.ID)
+	return nil
+}
+
+func TestDispatcher_DispatchPending(t *testing.T) {
+	repo := &stubEventRepo{events: []models.Event{
+		{ID: 1, EventType: models.EventPRCreated},
+		{ID: 2, EventType: models.EventReviewerAssigned},
+		{ID: 3, EventType: models.EventPRMerged},
+	}}
+	first := &recordingSink{name: "first"}
+	second := &recordingSink{name: "second", failOn: 2}
+
+	d := NewDispatcher(repo, nil, first)
+	d.AddSink(second)
+
+	published, err := d.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []int64{1, 3}, repo.published)
+	assert.Equal(t, "sink second: unavailable", repo.failed[2])
+	assert.Equal(t, []int64{1, 2, 3}, first.handled)
+	assert.Equal(t, []int64{1, 3}, second.handled)
+}
+
+func TestDispatcher_SkipsExhaustedEvents(t *testing.T) {
+	repo := &stubEventRepo{events: []models.Event{
+		{ID: 1, EventType: models.EventPRCreated, Attempts: DefaultMaxAttempts},
+	}}
+	sink := &recordingSink{name: "sink"}
+
+	published, err := NewDispatcher(repo, nil, sink).DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	assert.Empty(t, sink.handled)
+}
+
+// nestedSink на первом событии запускает второй диспетчер, как параллельный
+// экземпляр сервиса на той же базе
+type nestedSink struct {
+	recordingSink
+	other     *Dispatcher
+	published int
+}
+
+func (s *nestedSink) Handle(ctx context.Context, event models.Event) error {
+	if s.other != nil {
+		other := s.other
+		s.other = nil
+		published, err := other.DispatchPending(ctx)
+		if err != nil {
+			return err
+		}
+		s.published = published
+	}
+	return s.recordingSink.Handle(ctx, event)
+}
+
+func TestDispatcher_ClaimedEventsSkippedByOtherInstance(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	prs := memory.NewPRRepository(store)
+	events := memory.NewEventRepository(store)
+	require.NoError(t, users.CreateOrUpdateUser(ctx, &models.User{UserID: "u1", TeamName: "backend", IsActive: true}))
+	for _, id := range []string{"pr-1", "pr-2"} {
+		require.NoError(t, prs.CreatePR(ctx, &models.PullRequest{PullRequestID: id, PullRequestName: id, AuthorID: "u1", Status: models.PRStatusOpen}))
+	}
+
+	secondSink := &recordingSink{name: "second"}
+	first := &nestedSink{recordingSink: recordingSink{name: "first"}, other: NewDispatcher(events, nil, secondSink)}
+
+	published, err := NewDispatcher(events, nil, first).DispatchPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Zero(t, first.published, "пачка занята первым диспетчером")
+	assert.Empty(t, secondSink.handled)
+	assert.Len(t, first.handled, 2)
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+
+	"ReviewAssigner/internal/models"
+)
+
+// LogSink пишет опубликованные события в лог
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) *LogSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Handle(_ context.Context, event models.Event) error {
+	s.logger.Info("domain event",
+		"event_id", event.ID,
+		"event_type", event.EventType,
+		"aggregate_id", event.AggregateID,
+		"payload", event.Payload)
+	return nil
+}
@@ -0,0 +1,116 @@
+// Package ratelimit ограничивает частоту запросов клиента алгоритмом token bucket:
+// у каждого клиента корзина на Burst запросов, которая пополняется со
+// скоростью Rate запросов в секунду.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// как часто удаляются корзины клиентов, переставших присылать запросы
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter корзины клиентов по ключу. Нулевой Rate означает отсутствие лимита.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+// New создаёт лимитер на rate запросов в секунду с запасом burst;
+// rate <= 0 пропускает все запросы
+func New(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Enabled включён ли лимит
+func (l *Limiter) Enabled() bool {
+	return l != nil && l.rate > 0
+}
+
+// Allow забирает запрос из корзины клиента key. Если корзина пуста,
+// возвращает false и время до появления следующего запроса.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if !l.Enabled() {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Wait время до появления запроса в корзине клиента key, 0 — запрос разрешён.
+// В отличие от Allow корзину не расходует.
+func (l *Limiter) Wait(key string) time.Duration {
+	if !l.Enabled() {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return 0
+	}
+	tokens := math.Min(l.burst, b.tokens+l.now().Sub(b.last).Seconds()*l.rate)
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / l.rate * float64(time.Second))
+}
+
+// sweep удаляет корзины, которые уже успели наполниться: такой клиент
+// при следующем запросе получит новую полную корзину, разницы нет
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	l := New(2, 3)
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("token:1")
+		assert.True(t, ok, "запрос %d из запаса", i+1)
+	}
+	ok, wait := l.Allow("token:1")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// у другого клиента своя корзина
+	ok, _ = l.Allow("10.0.0.1")
+	assert.True(t, ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("token:1")
+	assert.True(t, ok, "за полсекунды пополнился один запрос")
+	ok, _ = l.Allow("token:1")
+	assert.False(t, ok)
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := New(2, 2)
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.Wait("ip:10.0.0.1"), "новый клиент")
+	l.Allow("ip:10.0.0.1")
+	assert.Zero(t, l.Wait("ip:10.0.0.1"))
+	l.Allow("ip:10.0.0.1")
+	assert.Equal(t, 500*time.Millisecond, l.Wait("ip:10.0.0.1"))
+	assert.Equal(t, 500*time.Millisecond, l.Wait("ip:10.0.0.1"), "Wait не расходует корзину")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Zero(t, l.Wait("ip:10.0.0.1"))
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l := New(1, 2)
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	l.Allow("a")
+	l.Allow("b")
+	assert.Len(t, l.buckets, 2)
+
+	now = now.Add(2 * sweepInterval)
+	l.Allow("b")
+	assert.Len(t, l.buckets, 1, "корзина a простаивала и удалена")
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	l := New(0, 1)
+	assert.False(t, l.Enabled())
+	for i := 0; i < 100; i++ {
+		ok, _ := l.Allow("token:1")
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// реализует AuditRepository интерфейс
+type AuditRepositoryImpl struct {
+	db dbtx
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepositoryImpl {
+	return &AuditRepositoryImpl{db: db}
+}
+
+// auditRow строка audit_log, состояния хранятся текстом JSON
+type auditRow struct {
+	ID          int64          `db:"id"`
+	Actor       string         `db:"actor"`
+	ActorName   string         `db:"actor_name"`
+	ActorMethod string         `db:"actor_method"`
+	Operation   string         `db:"operation"`
+	EntityType  string         `db:"entity_type"`
+	EntityID    string         `db:"entity_id"`
+	Before      sql.NullString `db:"before_state"`
+	After       sql.NullString `db:"after_state"`
+	IP          string         `db:"ip"`
+	CreatedAt   *time.Time     `db:"created_at"`
+}
+
+func (row auditRow) toModel() models.AuditEntry {
+	return models.AuditEntry{
+		ID:          row.ID,
+		Actor:       row.Actor,
+		ActorName:   row.ActorName,
+		ActorMethod: row.ActorMethod,
+		Operation:   row.Operation,
+		EntityType:  row.EntityType,
+		EntityID:    row.EntityID,
+		Before:      rawJSON(row.Before),
+		After:       rawJSON(row.After),
+		IP:          row.IP,
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+func rawJSON(s sql.NullString) json.RawMessage {
+	if !s.Valid {
+		return nil
+	}
+	return json.RawMessage(s.String)
+}
+
+func nullJSON(raw json.RawMessage) sql.NullString {
+	if len(raw) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(raw), Valid: true}
+}
+
+func (r *AuditRepositoryImpl) AppendAudit(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, actor_name, actor_method, operation, entity_type, entity_id,
+			before_state, after_state, ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING id, created_at
+	`
+	var created struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := r.db.GetContext(ctx, &created, query,
+		entry.Actor, entry.ActorName, entry.ActorMethod, entry.Operation, entry.EntityType, entry.EntityID,
+		nullJSON(entry.Before), nullJSON(entry.After), entry.IP)
+	if err != nil {
+		return dbError(err)
+	}
+
+	entry.ID = created.ID
+	entry.CreatedAt = &created.CreatedAt
+	return nil
+}
+
+// ListAudit выбирает записи журнала по фильтру от новых к старым,
+// следующая страница начинается строго после id из курсора
+func (r *AuditRepositoryImpl) ListAudit(ctx context.Context, filter AuditFilter, page Page) ([]models.AuditEntry, string, error) {
+	after, err := DecodeCursor(page.Cursor, AuditSort)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := PageLimit(page.Limit)
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Actor != "" {
+		conds = append(conds, "actor = "+arg(filter.Actor))
+	}
+	if filter.Operation != "" {
+		conds = append(conds, "operation = "+arg(filter.Operation))
+	}
+	if filter.EntityType != "" {
+		conds = append(conds, "entity_type = "+arg(filter.EntityType))
+	}
+	if filter.EntityID != "" {
+		conds = append(conds, "entity_id = "+arg(filter.EntityID))
+	}
+	if filter.From != nil {
+		conds = append(conds, "created_at >= "+arg(timeParam(*filter.From)))
+	}
+	if filter.To != nil {
+		conds = append(conds, "created_at < "+arg(timeParam(*filter.To)))
+	}
+	if after != nil {
+		id, err := strconv.ParseInt(after.ID, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		conds = append(conds, "id < "+arg(id))
+	}
+
+	query := `
+		SELECT id, actor, actor_name, actor_method, operation, entity_type, entity_id,
+			before_state, after_state, ip, created_at
+		FROM audit_log
+	`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	// лишняя запись показывает, есть ли следующая страница
+	query += " ORDER BY id DESC LIMIT " + arg(limit+1)
+
+	rows := []auditRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+	entries := make([]models.AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = row.toModel()
+	}
+	return entries, NextAuditCursor(entries, more), nil
+}
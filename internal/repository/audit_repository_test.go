@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_AppendAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(sqlx.NewDb(db, "sqlmock"))
+
+	created := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO audit_log (.+) RETURNING id, created_at`).
+		WithArgs("token:1", "ci", models.AuthMethodAPIToken, models.AuditUserActiveSet, models.AuditEntityUser, "u1",
+			`{"is_active":true}`, `{"is_active":false}`, "10.0.0.1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, created))
+
+	entry := &models.AuditEntry{
+		Actor:       "token:1",
+		ActorName:   "ci",
+		ActorMethod: models.AuthMethodAPIToken,
+		Operation:   models.AuditUserActiveSet,
+		EntityType:  models.AuditEntityUser,
+		EntityID:    "u1",
+		Before:      json.RawMessage(`{"is_active":true}`),
+		After:       json.RawMessage(`{"is_active":false}`),
+		IP:          "10.0.0.1",
+	}
+	require.NoError(t, repo.AppendAudit(context.Background(), entry))
+	assert.Equal(t, int64(5), entry.ID)
+	assert.Equal(t, created, *entry.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_ListAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepository(sqlx.NewDb(db, "sqlmock"))
+
+	created := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "actor", "actor_name", "actor_method", "operation", "entity_type", "entity_id",
+		"before_state", "after_state", "ip", "created_at"}
+	mock.ExpectQuery(`SELECT (.+) FROM audit_log WHERE entity_type = \$1 AND entity_id = \$2 AND id < \$3 ORDER BY id DESC LIMIT \$4`).
+		WithArgs(models.AuditEntityPR, "pr-1006", int64(10), 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(9, "sso-bob", "bob", models.AuthMethodJWT, models.AuditReviewerReplaced, models.AuditEntityPR, "pr-1006",
+				`{"assigned_reviewers":["u2"]}`, `{"assigned_reviewers":["u3"]}`, "10.0.0.2", created).
+			AddRow(8, "token:1", "ci", models.AuthMethodAPIToken, models.AuditPRCreated, models.AuditEntityPR, "pr-1006",
+				nil, `{"pull_request_id":"pr-1006"}`, "10.0.0.1", created).
+			AddRow(7, "token:1", "ci", models.AuthMethodAPIToken, models.AuditPRCreated, models.AuditEntityPR, "pr-1006",
+				nil, `{}`, "", created))
+
+	cursor := Cursor{Sort: AuditSort, ID: "10"}.Encode()
+	entries, next, err := repo.ListAudit(context.Background(),
+		AuditFilter{EntityType: models.AuditEntityPR, EntityID: "pr-1006"},
+		Page{Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "sso-bob", entries[0].Actor)
+	assert.JSONEq(t, `{"assigned_reviewers":["u2"]}`, string(entries[0].Before))
+	assert.Nil(t, entries[1].Before, "у создания нет состояния до")
+	assert.Equal(t, Cursor{Sort: AuditSort, ID: "8"}.Encode(), next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
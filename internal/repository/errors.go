@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+// Ошибки репозиториев. Конкретная ошибка драйвера остаётся в цепочке
+// и доступна через errors.As, сервисы сравнивают только с этими значениями.
+var (
+	// ErrNotFound запись не найдена
+	ErrNotFound = errors.New("not found")
+	// ErrConflict нарушено ограничение уникальности или внешнего ключа
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable база недоступна: нет соединения, перегрузка, блокировка
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrInvalidCursor курсор страницы не разобран или выдан для другой сортировки
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// коды SQLite (младший байт расширенного кода)
+const (
+	sqliteBusy       = 5
+	sqliteLocked     = 6
+	sqliteConstraint = 19
+)
+
+// sqliteError ошибка modernc.org/sqlite, без зависимости репозиториев от драйвера
+type sqliteError interface {
+	error
+	Code() int
+}
+
+// dbError относит ошибку драйвера к ErrNotFound, ErrConflict или ErrUnavailable.
+// Остальные ошибки и уже классифицированные возвращаются как есть.
+func dbError(err error) error {
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) || errors.Is(err, ErrUnavailable) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505", pqErr.Code == "23503":
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		// 08 — ошибки соединения, 53 — нехватка ресурсов, 57P0x — остановка сервера
+		case pqErr.Code.Class() == "08", pqErr.Code.Class() == "53",
+			pqErr.Code == "57P01", pqErr.Code == "57P02", pqErr.Code == "57P03":
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var liteErr sqliteError
+	if errors.As(err, &liteErr) {
+		switch liteErr.Code() & 0xff {
+		case sqliteConstraint:
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		case sqliteBusy, sqliteLocked:
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return err
+}
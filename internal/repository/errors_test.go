@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSQLiteError повторяет метод Code() ошибки modernc.org/sqlite
+type fakeSQLiteError int
+
+func (e fakeSQLiteError) Error() string { return fmt.Sprintf("sqlite error %d", int(e)) }
+func (e fakeSQLiteError) Code() int     { return int(e) }
+
+func TestDBError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", sql.ErrNoRows, ErrNotFound},
+		{"unique violation", &pq.Error{Code: "23505"}, ErrConflict},
+		{"foreign key violation", &pq.Error{Code: "23503"}, ErrConflict},
+		{"connection failure", &pq.Error{Code: "08006"}, ErrUnavailable},
+		{"too many connections", &pq.Error{Code: "53300"}, ErrUnavailable},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, ErrUnavailable},
+		{"bad conn", driver.ErrBadConn, ErrUnavailable},
+		{"conn done", fmt.Errorf("query: %w", sql.ErrConnDone), ErrUnavailable},
+		{"sqlite unique", fakeSQLiteError(2067), ErrConflict},
+		{"sqlite foreign key", fakeSQLiteError(787), ErrConflict},
+		{"sqlite busy", fakeSQLiteError(5), ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dbError(tt.err)
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, err, tt.err, "ошибка драйвера остаётся в цепочке")
+		})
+	}
+}
+
+func TestDBError_PassThrough(t *testing.T) {
+	assert.NoError(t, dbError(nil))
+
+	syntax := &pq.Error{Code: "42601"}
+	assert.Same(t, syntax, dbError(syntax))
+	assert.Equal(t, context.Canceled, dbError(context.Canceled))
+
+	// повторная классификация не оборачивает ошибку ещё раз
+	notFound := dbError(sql.ErrNoRows)
+	assert.Equal(t, notFound, dbError(notFound))
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// реализует EventRepository интерфейс
+type EventRepositoryImpl struct {
+	db dbtx
+}
+
+func NewEventRepository(db *sqlx.DB) *EventRepositoryImpl {
+	return &EventRepositoryImpl{db: db}
+}
+
+// insertEvent пишет событие в outbox в рамках переданной транзакции
+func insertEvent(ctx context.Context, tx sqlx.ExecerContext, eventType, aggregateID string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	query := `
+		INSERT INTO events (event_type, aggregate_id, payload, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`
+	if _, err := tx.ExecContext(ctx, query, eventType, aggregateID, string(payload)); err != nil {
+		return fmt.Errorf("failed to write %s event: %w", eventType, dbError(err))
+	}
+	return nil
+}
+
+// GetPendingEvents возвращает неопубликованные события в порядке записи,
+// пропуская события, исчерпавшие maxAttempts попыток
+func (r *EventRepositoryImpl) GetPendingEvents(ctx context.Context, limit, maxAttempts int) ([]models.Event, error) {
+	events := []models.Event{}
+	query := `
+		SELECT id, event_type, aggregate_id, payload, attempts, last_error, created_at, published_at
+		FROM events
+		WHERE published_at IS NULL AND attempts < $1
+		ORDER BY id
+		LIMIT $2
+	`
+	err := r.db.SelectContext(ctx, &events, query, maxAttempts, limit)
+	return events, dbError(err)
+}
+
+// ClaimPendingEvents условие повторяется во внешнем UPDATE: в Postgres
+// параллельный диспетчер ждёт блокировку строки, перепроверяет claimed_until
+// и пропускает занятое событие, поэтому событие публикует один экземпляр
+func (r *EventRepositoryImpl) ClaimPendingEvents(ctx context.Context, limit, maxAttempts int, leaseUntil time.Time) ([]models.Event, error) {
+	events := []models.Event{}
+	query := `
+		UPDATE events
+		SET claimed_until = $1
+		WHERE id IN (
+			SELECT id
+			FROM events
+			WHERE published_at IS NULL AND attempts < $2
+				AND (claimed_until IS NULL OR claimed_until <= NOW())
+			ORDER BY id
+			LIMIT $3
+		)
+		AND published_at IS NULL AND (claimed_until IS NULL OR claimed_until <= NOW())
+		RETURNING id, event_type, aggregate_id, payload, attempts, last_error, created_at, published_at, claimed_until
+	`
+	if err := r.db.SelectContext(ctx, &events, query, timeParam(leaseUntil), maxAttempts, limit); err != nil {
+		return nil, dbError(err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (r *EventRepositoryImpl) MarkEventPublished(ctx context.Context, id int64) error {
+	query := `
+		UPDATE events
+		SET published_at = NOW(), attempts = attempts + 1, last_error = '', claimed_until = NULL
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return dbError(err)
+}
+
+func (r *EventRepositoryImpl) MarkEventFailed(ctx context.Context, id int64, lastError string) error {
+	query := `
+		UPDATE events
+		SET attempts = attempts + 1, last_error = $1, claimed_until = NULL
+		WHERE id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, lastError, id)
+	return dbError(err)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRepository_GetPendingEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewEventRepository(sqlxDB)
+
+	rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "last_error", "created_at", "published_at"}).
+		AddRow(1, "pr.created", "pr-1001", `{"pull_request_id":"pr-1001"}`, 0, "", time.Now(), nil).
+		AddRow(2, "reviewer.assigned", "pr-1001", `{"pull_request_id":"pr-1001","reviewer_ids":["u2"]}`, 1, "timeout", time.Now(), nil)
+	mock.ExpectQuery(`SELECT (.+) FROM events WHERE published_at IS NULL AND attempts < \$1`).
+		WithArgs(10, 100).
+		WillReturnRows(rows)
+
+	events, err := repo.GetPendingEvents(context.Background(), 100, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "pr.created", events[0].EventType)
+	assert.Equal(t, 1, events[1].Attempts)
+	assert.Nil(t, events[1].PublishedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventRepository_ClaimPendingEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewEventRepository(sqlxDB)
+
+	lease := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "last_error", "created_at", "published_at", "claimed_until"}).
+		AddRow(7, "pr.merged", "pr-1001", `{}`, 0, "", time.Now(), nil, lease).
+		AddRow(3, "pr.created", "pr-1001", `{}`, 0, "", time.Now(), nil, lease)
+	mock.ExpectQuery(`UPDATE events SET claimed_until = \$1 WHERE id IN \(.+claimed_until <= NOW\(\).+\) AND published_at IS NULL AND \(claimed_until IS NULL OR claimed_until <= NOW\(\)\) RETURNING`).
+		WithArgs("2025-03-01 12:01:00.000000000+00:00", 10, 100).
+		WillReturnRows(rows)
+
+	events, err := repo.ClaimPendingEvents(context.Background(), 100, 10, lease)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].ID, "в порядке записи")
+	assert.Equal(t, int64(7), events[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventRepository_MarkEventFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewEventRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE events SET attempts = attempts \+ 1, last_error = \$1`).
+		WithArgs("sink webhooks: connection refused", int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.MarkEventFailed(context.Background(), 5, "sink webhooks: connection refused")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// реализует IdempotencyRepository интерфейс
+type IdempotencyRepositoryImpl struct {
+	db dbtx
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{db: db}
+}
+
+func (r *IdempotencyRepositoryImpl) ReserveKey(ctx context.Context, rec *models.IdempotencyRecord) error {
+	// истёкший ключ можно занять заново, заодно чистим остальные истёкшие
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`); err != nil {
+		return dbError(err)
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (idempotency_key, scope, request_hash, expires_at, locked_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+	_, err := r.db.ExecContext(ctx, query, rec.Key, rec.Scope, rec.RequestHash,
+		timeParam(rec.ExpiresAt), nullTimeParam(rec.LockedUntil))
+	return dbError(err)
+}
+
+// TakeOverKey условие в UPDATE: из двух повторов ключ достаётся одному
+func (r *IdempotencyRepositoryImpl) TakeOverKey(ctx context.Context, rec *models.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET expires_at = $4, locked_until = $5, created_at = NOW()
+		WHERE idempotency_key = $1 AND scope = $2 AND request_hash = $3
+			AND status_code = 0 AND locked_until <= NOW()
+	`
+	result, err := r.db.ExecContext(ctx, query, rec.Key, rec.Scope, rec.RequestHash,
+		timeParam(rec.ExpiresAt), nullTimeParam(rec.LockedUntil))
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: idempotency key %s is held", ErrConflict, rec.Key)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) GetKey(ctx context.Context, key, scope string) (*models.IdempotencyRecord, error) {
+	var rec models.IdempotencyRecord
+	query := `
+		SELECT idempotency_key, scope, request_hash, status_code, content_type, response_body, created_at, expires_at, locked_until
+		FROM idempotency_keys
+		WHERE idempotency_key = $1 AND scope = $2
+	`
+	if err := r.db.GetContext(ctx, &rec, query, key, scope); err != nil {
+		return nil, dbError(err)
+	}
+	return &rec, nil
+}
+
+func (r *IdempotencyRepositoryImpl) CompleteKey(ctx context.Context, key, scope string, statusCode int, contentType, body string) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE idempotency_key = $1 AND scope = $2
+	`
+	result, err := r.db.ExecContext(ctx, query, key, scope, statusCode, contentType, body)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: idempotency key %s", ErrNotFound, key)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) DeleteKey(ctx context.Context, key, scope string) error {
+	query := `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND scope = $2`
+	_, err := r.db.ExecContext(ctx, query, key, scope)
+	return dbError(err)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepository_ReserveKey_Conflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(sqlx.NewDb(db, "sqlmock"))
+
+	expires := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	locked := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE expires_at <= NOW\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO idempotency_keys`).
+		WithArgs("key-1", "POST /pullRequest/create", "hash",
+			"2025-03-02 12:00:00.000000000+00:00", "2025-03-01 12:01:00.000000000+00:00").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.ReserveKey(context.Background(), &models.IdempotencyRecord{
+		Key:         "key-1",
+		Scope:       "POST /pullRequest/create",
+		RequestHash: "hash",
+		ExpiresAt:   expires,
+		LockedUntil: &locked,
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_CompleteKey_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE idempotency_keys`).
+		WithArgs("key-1", "scope", 201, "application/json", `{}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.CompleteKey(context.Background(), "key-1", "scope", 201, "application/json", `{}`)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepository_TakeOverKey_Held(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewIdempotencyRepository(sqlx.NewDb(db, "sqlmock"))
+
+	expires := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	locked := time.Date(2025, 3, 1, 12, 1, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE idempotency_keys SET expires_at = \$4, locked_until = \$5.+status_code = 0 AND locked_until <= NOW\(\)`).
+		WithArgs("key-1", "scope", "hash",
+			"2025-03-02 12:00:00.000000000+00:00", "2025-03-01 12:01:00.000000000+00:00").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.TakeOverKey(context.Background(), &models.IdempotencyRecord{
+		Key:         "key-1",
+		Scope:       "scope",
+		RequestHash: "hash",
+		ExpiresAt:   expires,
+		LockedUntil: &locked,
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,38 +1,121 @@
 package repository
 
-import "ReviewAssigner/internal/models"
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/models"
+)
 
 type UserRepository interface {
-	CreateOrUpdateUser(user *models.User) error
-	GetUserByID(userID string) (*models.User, error)
-	SetUserActive(userID string, isActive bool) error
-	GetActiveTeamMembers(teamName string, excludeUserID string) ([]models.User, error)
-	GetUsersByTeam(teamName string) ([]models.User, error)
+	CreateOrUpdateUser(ctx context.Context, user *models.User) error
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	SetUserActive(ctx context.Context, userID string, isActive bool) error
+	GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error)
+	GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
 }
 
 type TeamRepository interface {
-	CreateTeam(teamName string) error
-	TeamExists(teamName string) (bool, error)
-	GetTeam(teamName string) (*models.Team, error)
-	GetUsersByTeam(teamName string) ([]models.User, error)
+	CreateTeam(ctx context.Context, teamName string) error
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, teamName string, settings *models.TeamSettings) error
+	ListTeams(ctx context.Context) ([]models.Team, error)
+	// DeleteTeam удаляет команду; ErrConflict — в команде есть участники
+	DeleteTeam(ctx context.Context, teamName string) error
+	// ListTeamMembers страница участников команды по возрастанию user_id
+	ListTeamMembers(ctx context.Context, teamName string, page Page) ([]models.TeamMember, string, error)
+	// AddTeamLead назначает лида команды, повторное назначение ничего не меняет
+	AddTeamLead(ctx context.Context, teamName, userID string) error
+	// RemoveTeamLead снимает лида, ErrNotFound если он не был лидом команды
+	RemoveTeamLead(ctx context.Context, teamName, userID string) error
+	GetTeamLeads(ctx context.Context, teamName string) ([]string, error)
+	// GetLedTeams команды, которыми руководит пользователь
+	GetLedTeams(ctx context.Context, userID string) ([]string, error)
 }
 
 type PRRepository interface {
-	CreatePR(pr *models.PullRequest) error
-	PRExists(prID string) (bool, error)
-	GetPRByID(prID string) (*models.PullRequest, error)
-	MergePR(prID string) error
-	AddPRReviewer(prID, reviewerID string) error
-	ReplacePRReviewer(prID, oldReviewerID, newReviewerID string) error
-	GetPRReviewers(prID string) ([]string, error)
-	IsReviewerAssigned(prID, reviewerID string) (bool, error)
-	GetAssignedPRs(userID string) ([]models.PullRequestShort, error)
-	GetUserAssignmentStats() (map[string]int, error)
-	GetPRMetrics() (map[string]interface{}, error)
-	DeletePR(prID string) error //new
+	CreatePR(ctx context.Context, pr *models.PullRequest) error
+	PRExists(ctx context.Context, prID string) (bool, error)
+	GetPRByID(ctx context.Context, prID string) (*models.PullRequest, error)
+	MergePR(ctx context.Context, prID string) error
+	TransitionPR(ctx context.Context, prID, fromStatus, toStatus string) error
+	ReleaseReviewers(ctx context.Context, prID string) error
+	AddPRReviewers(ctx context.Context, prID string, reviewerIDs []string) error
+	ReplacePRReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
+	GetPRReviewers(ctx context.Context, prID string) ([]string, error)
+	GetPRReviews(ctx context.Context, prID string) ([]models.Review, error)
+	SetReviewState(ctx context.Context, prID, reviewerID, state string) error
+	IsReviewerAssigned(ctx context.Context, prID, reviewerID string) (bool, error)
+	GetAssignedPRs(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	// ListPRs страница PR по фильтру и курсор следующей страницы ("" — последняя)
+	ListPRs(ctx context.Context, filter PRFilter, page Page) ([]models.PullRequestShort, string, error)
+	GetUserAssignmentStats(ctx context.Context) (map[string]int, error)
+	GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetPRMetrics(ctx context.Context) (map[string]interface{}, error)
+	DeletePR(ctx context.Context, prID string) error //new
+}
+
+// EventRepository outbox доменных событий. События пишут сами репозитории
+// в транзакции изменения, здесь только чтение и отметка о публикации.
+type EventRepository interface {
+	GetPendingEvents(ctx context.Context, limit, maxAttempts int) ([]models.Event, error)
+	// ClaimPendingEvents забирает до limit неопубликованных событий, не занятых другим
+	// диспетчером, и занимает их до leaseUntil. События отдаются в порядке записи.
+	ClaimPendingEvents(ctx context.Context, limit, maxAttempts int, leaseUntil time.Time) ([]models.Event, error)
+	MarkEventPublished(ctx context.Context, id int64) error
+	MarkEventFailed(ctx context.Context, id int64, lastError string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	// CreateDelivery ставит доставку в очередь; ErrConflict — доставка этого
+	// события подписке уже есть
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ClaimDueDeliveries забирает до limit доставок, чья попытка уже наступила,
+	// и откладывает их следующую попытку до leaseUntil, чтобы другой процесс
+	// не отправил их одновременно
+	ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+}
+
+// IdempotencyRepository ответы на запросы с ключом идемпотентности
+type IdempotencyRepository interface {
+	// ReserveKey занимает ключ под выполняющийся запрос; ErrConflict — ключ уже занят
+	// и не истёк. Истёкшие ключи удаляются.
+	ReserveKey(ctx context.Context, rec *models.IdempotencyRecord) error
+	// TakeOverKey занимает ключ запроса с тем же хэшем, который не завершился до
+	// LockedUntil; ErrConflict — запрос ещё держит ключ или уже завершён
+	TakeOverKey(ctx context.Context, rec *models.IdempotencyRecord) error
+	GetKey(ctx context.Context, key, scope string) (*models.IdempotencyRecord, error)
+	CompleteKey(ctx context.Context, key, scope string, statusCode int, contentType, body string) error
+	DeleteKey(ctx context.Context, key, scope string) error
+}
+
+// TokenRepository API-токены, поиск только по хэшу токена
+type TokenRepository interface {
+	CreateToken(ctx context.Context, token *models.APIToken) error
+	GetTokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	ListTokens(ctx context.Context) ([]models.APIToken, error)
+	// RevokeToken отзывает действующий токен; ErrNotFound — нет такого или уже отозван
+	RevokeToken(ctx context.Context, id int64) error
+}
+
+// AuditRepository журнал аудита: записи только добавляются, изменять и
+// удалять их нельзя
+type AuditRepository interface {
+	AppendAudit(ctx context.Context, entry *models.AuditEntry) error
+	// ListAudit страница записей по фильтру, сначала новые, и курсор следующей страницы
+	ListAudit(ctx context.Context, filter AuditFilter, page Page) ([]models.AuditEntry, string, error)
 }
 
 type ReviewService interface {
-	AssignReviewers(teamName, authorID, prID string) ([]string, error)
-	ReplaceReviewer(prID, oldReviewerID string) (string, error)
+	AssignReviewers(ctx context.Context, teamName, authorID, prID string, count int) ([]string, error)
+	ReplaceReviewer(ctx context.Context, prID, oldReviewerID string) (string, error)
 }
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// реализует repository.AuditRepository в памяти
+type AuditRepository struct {
+	conn
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{conn: conn{store: store}}
+}
+
+func (r *AuditRepository) AppendAudit(ctx context.Context, entry *models.AuditEntry) error {
+	return r.do(ctx, func(d *state) error {
+		d.nextAuditID++
+		now := time.Now()
+		entry.ID = d.nextAuditID
+		entry.CreatedAt = &now
+
+		d.audit = append(d.audit, *entry)
+		return nil
+	})
+}
+
+func (r *AuditRepository) ListAudit(ctx context.Context, filter repository.AuditFilter, page repository.Page) ([]models.AuditEntry, string, error) {
+	after, err := repository.DecodeCursor(page.Cursor, repository.AuditSort)
+	if err != nil {
+		return nil, "", err
+	}
+	var afterID int64
+	if after != nil {
+		if afterID, err = strconv.ParseInt(after.ID, 10, 64); err != nil {
+			return nil, "", fmt.Errorf("%w: %w", repository.ErrInvalidCursor, err)
+		}
+	}
+	limit := repository.PageLimit(page.Limit)
+
+	entries := []models.AuditEntry{}
+	err = r.do(ctx, func(d *state) error {
+		// записи добавляются по возрастанию id, новые — в конце
+		for i := len(d.audit) - 1; i >= 0; i-- {
+			entry := d.audit[i]
+			if after != nil && entry.ID >= afterID || !auditMatches(entry, filter) {
+				continue
+			}
+			entries = append(entries, entry)
+			// лишняя запись показывает, есть ли следующая страница
+			if len(entries) > limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	more := len(entries) > limit
+	if more {
+		entries = entries[:limit]
+	}
+	return entries, repository.NextAuditCursor(entries, more), nil
+}
+
+func auditMatches(entry models.AuditEntry, filter repository.AuditFilter) bool {
+	switch {
+	case filter.Actor != "" && entry.Actor != filter.Actor,
+		filter.Operation != "" && entry.Operation != filter.Operation,
+		filter.EntityType != "" && entry.EntityType != filter.EntityType,
+		filter.EntityID != "" && entry.EntityID != filter.EntityID,
+		filter.From != nil && entry.CreatedAt.Before(*filter.From),
+		filter.To != nil && !entry.CreatedAt.Before(*filter.To):
+		return false
+	}
+	return true
+}
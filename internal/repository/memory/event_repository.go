@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ReviewAssigner/internal/models"
+)
+
+// реализует repository.EventRepository в памяти
+type EventRepository struct {
+	conn
+}
+
+func NewEventRepository(store *Store) *EventRepository {
+	return &EventRepository{conn: conn{store: store}}
+}
+
+// insertEvent пишет событие в outbox вместе с изменением
+func (d *state) insertEvent(eventType, aggregateID string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	d.nextEventID++
+	now := time.Now()
+	d.events = append(d.events, models.Event{
+		ID:          d.nextEventID,
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     string(payload),
+		CreatedAt:   &now,
+	})
+	return nil
+}
+
+// GetPendingEvents возвращает неопубликованные события в порядке записи,
+// пропуская события, исчерпавшие maxAttempts попыток
+func (r *EventRepository) GetPendingEvents(ctx context.Context, limit, maxAttempts int) ([]models.Event, error) {
+	events := []models.Event{}
+	err := r.do(ctx, func(d *state) error {
+		for _, event := range d.events {
+			if len(events) >= limit {
+				break
+			}
+			if event.PublishedAt == nil && event.Attempts < maxAttempts {
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	return events, err
+}
+
+func (r *EventRepository) ClaimPendingEvents(ctx context.Context, limit, maxAttempts int, leaseUntil time.Time) ([]models.Event, error) {
+	events := []models.Event{}
+	err := r.do(ctx, func(d *state) error {
+		now := time.Now()
+		for i := range d.events {
+			if len(events) >= limit {
+				break
+			}
+			event := &d.events[i]
+			if event.PublishedAt != nil || event.Attempts >= maxAttempts ||
+				event.ClaimedUntil != nil && event.ClaimedUntil.After(now) {
+				continue
+			}
+			claimed := leaseUntil
+			event.ClaimedUntil = &claimed
+			events = append(events, *event)
+		}
+		return nil
+	})
+	return events, err
+}
+
+func (r *EventRepository) MarkEventPublished(ctx context.Context, id int64) error {
+	return r.do(ctx, func(d *state) error {
+		if event := d.event(id); event != nil {
+			now := time.Now()
+			event.PublishedAt = &now
+			event.Attempts++
+			event.LastError = ""
+			event.ClaimedUntil = nil
+		}
+		return nil
+	})
+}
+
+func (r *EventRepository) MarkEventFailed(ctx context.Context, id int64, lastError string) error {
+	return r.do(ctx, func(d *state) error {
+		if event := d.event(id); event != nil {
+			event.Attempts++
+			event.LastError = lastError
+			event.ClaimedUntil = nil
+		}
+		return nil
+	})
+}
+
+func (d *state) event(id int64) *models.Event {
+	for i := range d.events {
+		if d.events[i].ID == id {
+			return &d.events[i]
+		}
+	}
+	return nil
+}
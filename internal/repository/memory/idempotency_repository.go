@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// idempotencyID ключ идемпотентности уникален в пределах маршрута
+type idempotencyID struct {
+	key   string
+	scope string
+}
+
+// реализует repository.IdempotencyRepository в памяти
+type IdempotencyRepository struct {
+	conn
+}
+
+func NewIdempotencyRepository(store *Store) *IdempotencyRepository {
+	return &IdempotencyRepository{conn: conn{store: store}}
+}
+
+func (r *IdempotencyRepository) ReserveKey(ctx context.Context, rec *models.IdempotencyRecord) error {
+	return r.do(ctx, func(d *state) error {
+		now := time.Now()
+		for id, existing := range d.idempotency {
+			if !existing.ExpiresAt.After(now) {
+				delete(d.idempotency, id)
+			}
+		}
+
+		id := idempotencyID{key: rec.Key, scope: rec.Scope}
+		if _, ok := d.idempotency[id]; ok {
+			return fmt.Errorf("%w: idempotency key %s", repository.ErrConflict, rec.Key)
+		}
+		row := *rec
+		row.StatusCode = 0
+		row.CreatedAt = now
+		d.idempotency[id] = row
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) TakeOverKey(ctx context.Context, rec *models.IdempotencyRecord) error {
+	return r.do(ctx, func(d *state) error {
+		id := idempotencyID{key: rec.Key, scope: rec.Scope}
+		row, ok := d.idempotency[id]
+		now := time.Now()
+		if !ok || row.RequestHash != rec.RequestHash || row.StatusCode != 0 ||
+			row.LockedUntil == nil || row.LockedUntil.After(now) {
+			return fmt.Errorf("%w: idempotency key %s is held", repository.ErrConflict, rec.Key)
+		}
+		row.ExpiresAt = rec.ExpiresAt
+		row.LockedUntil = rec.LockedUntil
+		row.CreatedAt = now
+		d.idempotency[id] = row
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) GetKey(ctx context.Context, key, scope string) (*models.IdempotencyRecord, error) {
+	var rec models.IdempotencyRecord
+	err := r.do(ctx, func(d *state) error {
+		row, ok := d.idempotency[idempotencyID{key: key, scope: scope}]
+		if !ok {
+			return fmt.Errorf("%w: idempotency key %s", repository.ErrNotFound, key)
+		}
+		rec = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *IdempotencyRepository) CompleteKey(ctx context.Context, key, scope string, statusCode int, contentType, body string) error {
+	return r.do(ctx, func(d *state) error {
+		id := idempotencyID{key: key, scope: scope}
+		row, ok := d.idempotency[id]
+		if !ok {
+			return fmt.Errorf("%w: idempotency key %s", repository.ErrNotFound, key)
+		}
+		row.StatusCode = statusCode
+		row.ContentType = contentType
+		row.ResponseBody = body
+		d.idempotency[id] = row
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) DeleteKey(ctx context.Context, key, scope string) error {
+	return r.do(ctx, func(d *state) error {
+		delete(d.idempotency, idempotencyID{key: key, scope: scope})
+		return nil
+	})
+}
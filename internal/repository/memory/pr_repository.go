@@ -0,0 +1,491 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// реализует repository.PRRepository в памяти
+type PRRepository struct {
+	conn
+}
+
+func NewPRRepository(store *Store) *PRRepository {
+	return &PRRepository{conn: conn{store: store}}
+}
+
+func (r *PRRepository) CreatePR(ctx context.Context, pr *models.PullRequest) error {
+	return r.do(ctx, func(d *state) error {
+		if _, ok := d.prs[pr.PullRequestID]; ok {
+			return fmt.Errorf("%w: PR %s already exists", repository.ErrConflict, pr.PullRequestID)
+		}
+		if _, ok := d.users[pr.AuthorID]; !ok {
+			return fmt.Errorf("%w: author %s not found", repository.ErrConflict, pr.AuthorID)
+		}
+
+		row := models.PullRequest{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          pr.Status,
+			Source:          pr.Source,
+			SourceURL:       pr.SourceURL,
+		}
+		if row.Status == "" {
+			row.Status = models.PRStatusOpen
+		}
+		if row.Source == "" {
+			row.Source = models.PRSourceAPI
+		}
+		now := time.Now()
+		row.CreatedAt = &now
+		d.prs[row.PullRequestID] = row
+
+		return d.insertEvent(models.EventPRCreated, row.PullRequestID, models.PRCreatedData{
+			PullRequestID:   row.PullRequestID,
+			PullRequestName: row.PullRequestName,
+			AuthorID:        row.AuthorID,
+			Status:          row.Status,
+			Source:          row.Source,
+			SourceURL:       row.SourceURL,
+		})
+	})
+}
+
+// DeletePR удаляет PR вместе с назначениями, как ON DELETE CASCADE
+func (r *PRRepository) DeletePR(ctx context.Context, prID string) error {
+	return r.do(ctx, func(d *state) error {
+		delete(d.prs, prID)
+
+		kept := d.reviewers[:0]
+		for _, row := range d.reviewers {
+			if row.prID != prID {
+				kept = append(kept, row)
+			}
+		}
+		d.reviewers = kept
+		return nil
+	})
+}
+
+func (r *PRRepository) PRExists(ctx context.Context, prID string) (bool, error) {
+	var exists bool
+	err := r.do(ctx, func(d *state) error {
+		_, exists = d.prs[prID]
+		return nil
+	})
+	return exists, err
+}
+
+func (r *PRRepository) GetPRByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	var pr models.PullRequest
+	err := r.do(ctx, func(d *state) error {
+		found, ok := d.prs[prID]
+		if !ok {
+			return fmt.Errorf("%w: PR %s", repository.ErrNotFound, prID)
+		}
+		pr = found
+
+		pr.Reviews = d.activeReviews(prID)
+		pr.AssignedReviewers = make([]string, len(pr.Reviews))
+		for i, review := range pr.Reviews {
+			pr.AssignedReviewers[i] = review.ReviewerID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// MergePR мерджит открытый PR; ErrConflict — PR уже не в статусе OPEN
+func (r *PRRepository) MergePR(ctx context.Context, prID string) error {
+	return r.do(ctx, func(d *state) error {
+		pr, ok := d.prs[prID]
+		if !ok || pr.Status != models.PRStatusOpen {
+			return fmt.Errorf("%w: PR %s is not open", repository.ErrConflict, prID)
+		}
+		now := time.Now()
+		pr.Status = models.PRStatusMerged
+		pr.MergedAt = &now
+		d.prs[prID] = pr
+
+		return d.insertEvent(models.EventPRMerged, prID, models.PRMergedData{PullRequestID: prID})
+	})
+}
+
+// TransitionPR меняет статус PR, только если текущий статус равен fromStatus
+func (r *PRRepository) TransitionPR(ctx context.Context, prID, fromStatus, toStatus string) error {
+	return r.do(ctx, func(d *state) error {
+		pr, ok := d.prs[prID]
+		if !ok || pr.Status != fromStatus {
+			return fmt.Errorf("%w: PR %s is not in status %s", repository.ErrConflict, prID, fromStatus)
+		}
+
+		pr.Status = toStatus
+		pr.ClosedAt = nil
+		if toStatus == models.PRStatusClosed {
+			now := time.Now()
+			pr.ClosedAt = &now
+		}
+		d.prs[prID] = pr
+
+		return d.insertEvent(models.EventPRStatusChanged, prID, models.PRStatusChangedData{
+			PullRequestID: prID,
+			From:          fromStatus,
+			To:            toStatus,
+		})
+	})
+}
+
+// ReleaseReviewers снимает всех активных ревьюеров с PR
+func (r *PRRepository) ReleaseReviewers(ctx context.Context, prID string) error {
+	return r.do(ctx, func(d *state) error {
+		now := time.Now()
+		for i := range d.reviewers {
+			row := &d.reviewers[i]
+			if row.prID == prID && row.isActive {
+				row.isActive = false
+				row.replacedAt = &now
+			}
+		}
+		return nil
+	})
+}
+
+// AddPRReviewers назначает ревьюеров вместе с событием reviewer.assigned
+func (r *PRRepository) AddPRReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
+	if len(reviewerIDs) == 0 {
+		return nil
+	}
+
+	return r.do(ctx, func(d *state) error {
+		if _, ok := d.prs[prID]; !ok {
+			return fmt.Errorf("%w: PR %s not found", repository.ErrConflict, prID)
+		}
+		for _, reviewerID := range reviewerIDs {
+			if err := d.assignReviewer(prID, reviewerID); err != nil {
+				return fmt.Errorf("failed to add reviewer %s: %w", reviewerID, err)
+			}
+		}
+
+		return d.insertEvent(models.EventReviewerAssigned, prID, models.ReviewerAssignedData{
+			PullRequestID: prID,
+			ReviewerIDs:   reviewerIDs,
+		})
+	})
+}
+
+func (r *PRRepository) ReplacePRReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+	return r.do(ctx, func(d *state) error {
+		old := d.activeReviewer(prID, oldReviewerID)
+		if old == nil {
+			return fmt.Errorf("%w: reviewer not assigned to this PR", repository.ErrNotFound)
+		}
+		if _, ok := d.users[newReviewerID]; !ok {
+			return fmt.Errorf("%w: user %s not found", repository.ErrConflict, newReviewerID)
+		}
+
+		now := time.Now()
+		old.isActive = false
+		old.replacedAt = &now
+
+		if err := d.assignReviewer(prID, newReviewerID); err != nil {
+			return err
+		}
+
+		return d.insertEvent(models.EventReviewerReplaced, prID, models.ReviewerReplacedData{
+			PullRequestID: prID,
+			OldReviewerID: oldReviewerID,
+			NewReviewerID: newReviewerID,
+		})
+	})
+}
+
+func (r *PRRepository) GetPRReviewers(ctx context.Context, prID string) ([]string, error) {
+	var reviewers []string
+	err := r.do(ctx, func(d *state) error {
+		for _, review := range d.activeReviews(prID) {
+			reviewers = append(reviewers, review.ReviewerID)
+		}
+		return nil
+	})
+	return reviewers, err
+}
+
+func (r *PRRepository) GetPRReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.do(ctx, func(d *state) error {
+		reviews = d.activeReviews(prID)
+		return nil
+	})
+	return reviews, err
+}
+
+func (r *PRRepository) SetReviewState(ctx context.Context, prID, reviewerID, reviewState string) error {
+	return r.do(ctx, func(d *state) error {
+		row := d.activeReviewer(prID, reviewerID)
+		if row == nil {
+			return fmt.Errorf("%w: reviewer not assigned to this PR", repository.ErrNotFound)
+		}
+
+		now := time.Now()
+		row.state = reviewState
+		row.reviewedAt = &now
+		return nil
+	})
+}
+
+func (r *PRRepository) GetAssignedPRs(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	var prs []models.PullRequestShort
+	err := r.do(ctx, func(d *state) error {
+		for _, row := range d.reviewers {
+			if row.reviewerID != userID || !row.isActive {
+				continue
+			}
+			pr, ok := d.prs[row.prID]
+			if !ok || pr.Status != models.PRStatusOpen {
+				continue
+			}
+			prs = append(prs, models.PullRequestShort{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				Status:          pr.Status,
+			})
+		}
+		return nil
+	})
+	return prs, err
+}
+
+func (r *PRRepository) ListPRs(ctx context.Context, filter repository.PRFilter, page repository.Page) ([]models.PullRequestShort, string, error) {
+	after, err := repository.DecodePRCursor(page.Cursor, filter.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	key, desc := repository.SplitPRSort(filter.Sort)
+	limit := repository.PageLimit(page.Limit)
+
+	// compare упорядочивает PR так же, как ORDER BY в postgres
+	compare := func(at time.Time, id string, other time.Time, otherID string) int {
+		c := 0
+		if key == repository.PRSortCreatedAt {
+			c = at.Compare(other)
+		}
+		if c == 0 {
+			c = strings.Compare(id, otherID)
+		}
+		if desc {
+			c = -c
+		}
+		return c
+	}
+	createdAt := func(pr models.PullRequestShort) time.Time {
+		if pr.CreatedAt == nil {
+			return time.Time{}
+		}
+		return *pr.CreatedAt
+	}
+	var afterAt time.Time
+	if after != nil && key == repository.PRSortCreatedAt {
+		afterAt, _ = after.Time()
+	}
+
+	prs := []models.PullRequestShort{}
+	err = r.do(ctx, func(d *state) error {
+		for _, pr := range d.prs {
+			short := models.PullRequestShort{
+				PullRequestID:   pr.PullRequestID,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				Status:          pr.Status,
+				CreatedAt:       pr.CreatedAt,
+			}
+			at := createdAt(short)
+			switch {
+			case filter.Status != "" && pr.Status != filter.Status,
+				filter.AuthorID != "" && pr.AuthorID != filter.AuthorID,
+				filter.TeamName != "" && d.users[pr.AuthorID].TeamName != filter.TeamName,
+				filter.ReviewerID != "" && d.activeReviewer(pr.PullRequestID, filter.ReviewerID) == nil,
+				filter.CreatedFrom != nil && at.Before(*filter.CreatedFrom),
+				filter.CreatedTo != nil && !at.Before(*filter.CreatedTo),
+				after != nil && compare(at, pr.PullRequestID, afterAt, after.ID) <= 0:
+				continue
+			}
+			prs = append(prs, short)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(prs, func(i, j int) bool {
+		return compare(createdAt(prs[i]), prs[i].PullRequestID, createdAt(prs[j]), prs[j].PullRequestID) < 0
+	})
+	more := len(prs) > limit
+	if more {
+		prs = prs[:limit]
+	}
+	return prs, repository.NextPRCursor(prs, filter.Sort, more), nil
+}
+
+func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID, reviewerID string) (bool, error) {
+	var assigned bool
+	err := r.do(ctx, func(d *state) error {
+		assigned = d.activeReviewer(prID, reviewerID) != nil
+		return nil
+	})
+	return assigned, err
+}
+
+func (r *PRRepository) GetUserAssignmentStats(ctx context.Context) (map[string]int, error) {
+	stats := make(map[string]int)
+	err := r.do(ctx, func(d *state) error {
+		for _, row := range d.reviewers {
+			if row.isActive {
+				stats[row.reviewerID]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetOpenReviewLoad считает активные назначения пользователей только на открытые PR
+func (r *PRRepository) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	load := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return load, nil
+	}
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	err := r.do(ctx, func(d *state) error {
+		for _, row := range d.reviewers {
+			if !row.isActive || !wanted[row.reviewerID] {
+				continue
+			}
+			if pr, ok := d.prs[row.prID]; ok && pr.Status == models.PRStatusOpen {
+				load[row.reviewerID]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return load, nil
+}
+
+func (r *PRRepository) GetPRMetrics(ctx context.Context) (map[string]interface{}, error) {
+	metrics := make(map[string]interface{})
+	err := r.do(ctx, func(d *state) error {
+		byStatus := make(map[string]int)
+		for _, pr := range d.prs {
+			byStatus[pr.Status]++
+		}
+		metrics["total_prs"] = len(d.prs)
+		metrics["open_prs"] = byStatus[models.PRStatusOpen]
+		metrics["merged_prs"] = byStatus[models.PRStatusMerged]
+		metrics["draft_prs"] = byStatus[models.PRStatusDraft]
+		metrics["closed_prs"] = byStatus[models.PRStatusClosed]
+
+		// ср.кол-во ревьюеров на PR (учитываем только активные записи)
+		perPR := make(map[string]int)
+		for _, row := range d.reviewers {
+			if row.isActive {
+				perPR[row.prID]++
+			}
+		}
+		var avgReviewers float64
+		if len(perPR) > 0 {
+			total := 0
+			for _, count := range perPR {
+				total += count
+			}
+			avgReviewers = float64(total) / float64(len(perPR))
+		}
+		metrics["avg_reviewers"] = avgReviewers
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// activeReviewer активное назначение ревьюера на PR или nil
+func (d *state) activeReviewer(prID, reviewerID string) *reviewerRow {
+	for i := range d.reviewers {
+		row := &d.reviewers[i]
+		if row.prID == prID && row.reviewerID == reviewerID && row.isActive {
+			return row
+		}
+	}
+	return nil
+}
+
+// assignReviewer добавляет активное назначение. Активная пара (PR, ревьюер)
+// уникальна, повторное назначение сбрасывает ревью, как ON CONFLICT в postgres.
+func (d *state) assignReviewer(prID, reviewerID string) error {
+	if _, ok := d.users[reviewerID]; !ok {
+		return fmt.Errorf("%w: user %s not found", repository.ErrConflict, reviewerID)
+	}
+
+	now := time.Now()
+	if row := d.activeReviewer(prID, reviewerID); row != nil {
+		row.replacedAt = nil
+		row.assignedAt = now
+		row.state = models.ReviewStatePending
+		row.reviewedAt = nil
+		return nil
+	}
+
+	d.reviewers = append(d.reviewers, reviewerRow{
+		prID:       prID,
+		reviewerID: reviewerID,
+		state:      models.ReviewStatePending,
+		assignedAt: now,
+		isActive:   true,
+	})
+	return nil
+}
+
+// activeReviews ревью активных ревьюеров в порядке назначения
+func (d *state) activeReviews(prID string) []models.Review {
+	var reviews []models.Review
+	for _, row := range d.reviewers {
+		if row.prID != prID || !row.isActive {
+			continue
+		}
+		assignedAt := row.assignedAt
+		reviews = append(reviews, models.Review{
+			ReviewerID: row.reviewerID,
+			State:      row.state,
+			AssignedAt: &assignedAt,
+			ReviewedAt: row.reviewedAt,
+		})
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		if !reviews[i].AssignedAt.Equal(*reviews[j].AssignedAt) {
+			return reviews[i].AssignedAt.Before(*reviews[j].AssignedAt)
+		}
+		return reviews[i].ReviewerID < reviews[j].ReviewerID
+	})
+	return reviews
+}
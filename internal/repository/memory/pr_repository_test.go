@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSeededStore(t *testing.T) *Store {
+	t.Helper()
+	store := NewStore()
+	Seed(store)
+	return store
+}
+
+func TestPRRepository_CreatePR(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	pr := &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Test PR", AuthorID: "u1"}
+	require.NoError(t, repo.CreatePR(ctx, pr))
+
+	created, err := repo.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusOpen, created.Status)
+	assert.Equal(t, models.PRSourceAPI, created.Source)
+	assert.Empty(t, created.AssignedReviewers)
+
+	assert.ErrorIs(t, repo.CreatePR(ctx, pr), repository.ErrConflict, "duplicate PR id")
+	assert.ErrorIs(t, repo.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-2", AuthorID: "ghost"}), repository.ErrConflict, "unknown author")
+}
+
+func TestPRRepository_UniqueActiveReviewer(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	require.NoError(t, repo.SetReviewState(ctx, "pr-1005", "u2", models.ReviewStateApproved))
+
+	// повторное назначение не дублирует ревьюера и сбрасывает вердикт
+	require.NoError(t, repo.AddPRReviewers(ctx, "pr-1005", []string{"u2"}))
+
+	reviews, err := repo.GetPRReviews(ctx, "pr-1005")
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	for _, review := range reviews {
+		assert.Equal(t, models.ReviewStatePending, review.State)
+	}
+}
+
+func TestPRRepository_ReplacePRReviewer(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	require.NoError(t, repo.ReplacePRReviewer(ctx, "pr-1008", "u2", "u3"))
+
+	reviewers, err := repo.GetPRReviewers(ctx, "pr-1008")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3"}, reviewers)
+
+	err = repo.ReplacePRReviewer(ctx, "pr-1008", "u2", "u3")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestPRRepository_DeletePRCascades(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	require.NoError(t, repo.DeletePR(ctx, "pr-1005"))
+
+	exists, err := repo.PRExists(ctx, "pr-1005")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	stats, err := repo.GetUserAssignmentStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats["u2"], "только pr-1008")
+	assert.Equal(t, 1, stats["u3"], "только pr-1006")
+}
+
+func TestPRRepository_GetAssignedPRsOnlyOpen(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	// pr-1007 смерджен, назначение u5 не считается
+	prs, err := repo.GetAssignedPRs(ctx, "u5")
+	require.NoError(t, err)
+	assert.Empty(t, prs)
+
+	require.NoError(t, repo.MergePR(ctx, "pr-1008"))
+	prs, err = repo.GetAssignedPRs(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "pr-1005", prs[0].PullRequestID)
+
+	load, err := repo.GetOpenReviewLoad(ctx, []string{"u2", "u5"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"u2": 1}, load)
+}
+
+func TestPRRepository_ListPRsPages(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	// у сида одинаковый created_at, порядок решает pull_request_id
+	var ids []string
+	cursor := ""
+	for {
+		prs, next, err := repo.ListPRs(ctx, repository.PRFilter{}, repository.Page{Limit: 1, Cursor: cursor})
+		require.NoError(t, err)
+		for _, pr := range prs {
+			ids = append(ids, pr.PullRequestID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []string{"pr-1008", "pr-1007", "pr-1006", "pr-1005"}, ids)
+
+	prs, next, err := repo.ListPRs(ctx, repository.PRFilter{
+		Status:     models.PRStatusOpen,
+		ReviewerID: "u2",
+		Sort:       repository.PRSortID,
+	}, repository.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, next)
+	require.Len(t, prs, 2)
+	assert.Equal(t, "pr-1005", prs[0].PullRequestID)
+	assert.Equal(t, "pr-1008", prs[1].PullRequestID)
+
+	prs, _, err = repo.ListPRs(ctx, repository.PRFilter{TeamName: "frontend"}, repository.Page{})
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "pr-1007", prs[0].PullRequestID)
+
+	_, _, err = repo.ListPRs(ctx, repository.PRFilter{Sort: repository.PRSortID}, repository.Page{Cursor: cursor})
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor, "курсор выдан для -created_at")
+}
+
+func TestPRRepository_TransitionPR(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPRRepository(newSeededStore(t))
+
+	require.NoError(t, repo.TransitionPR(ctx, "pr-1005", models.PRStatusOpen, models.PRStatusClosed))
+
+	pr, err := repo.GetPRByID(ctx, "pr-1005")
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusClosed, pr.Status)
+	assert.NotNil(t, pr.ClosedAt)
+
+	err = repo.TransitionPR(ctx, "pr-1005", models.PRStatusOpen, models.PRStatusClosed)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+}
+
+func TestPRRepository_WritesEvents(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	Seed(store)
+	repo := NewPRRepository(store)
+
+	require.NoError(t, repo.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "x", AuthorID: "u1"}))
+	require.NoError(t, repo.AddPRReviewers(ctx, "pr-1", []string{"u2", "u3"}))
+	require.NoError(t, repo.MergePR(ctx, "pr-1"))
+
+	events, err := NewEventRepository(store).GetPendingEvents(ctx, 10, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, models.EventPRCreated, events[0].EventType)
+	assert.Equal(t, models.EventReviewerAssigned, events[1].EventType)
+	assert.JSONEq(t, `{"pull_request_id":"pr-1","reviewer_ids":["u2","u3"]}`, events[1].Payload)
+	assert.Equal(t, models.EventPRMerged, events[2].EventType)
+}
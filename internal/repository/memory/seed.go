@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+var (
+	_ repository.UserRepository    = (*UserRepository)(nil)
+	_ repository.TeamRepository    = (*TeamRepository)(nil)
+	_ repository.PRRepository      = (*PRRepository)(nil)
+	_ repository.EventRepository   = (*EventRepository)(nil)
+	_ repository.WebhookRepository = (*WebhookRepository)(nil)
+	_ repository.TxManager         = (*TxManager)(nil)
+)
+
+// Seed заполняет хранилище теми же данными, что и миграция 002_seed_data
+func Seed(store *Store) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	d := store.data
+	now := time.Now()
+
+	for _, team := range []string{"backend", "frontend", "payments", "mobile"} {
+		d.teams[team] = teamRow{settings: defaultTeamSettings, createdAt: now}
+	}
+
+	for _, user := range []models.User{
+		{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{UserID: "u3", Username: "Charlie", TeamName: "backend", IsActive: true},
+		{UserID: "u4", Username: "David", TeamName: "frontend", IsActive: true},
+		{UserID: "u5", Username: "Eva", TeamName: "frontend", IsActive: true},
+		{UserID: "u6", Username: "Frank", TeamName: "payments", IsActive: true},
+		{UserID: "u7", Username: "Grace", TeamName: "mobile", IsActive: false},
+	} {
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		d.users[user.UserID] = user
+	}
+
+	for _, pr := range []models.PullRequest{
+		{PullRequestID: "pr-1005", PullRequestName: "Add search", AuthorID: "u1", Status: models.PRStatusOpen},
+		{PullRequestID: "pr-1006", PullRequestName: "Fix authentication bug", AuthorID: "u2", Status: models.PRStatusOpen},
+		{PullRequestID: "pr-1007", PullRequestName: "Update documentation", AuthorID: "u4", Status: models.PRStatusMerged},
+		{PullRequestID: "pr-1008", PullRequestName: "Refactor API", AuthorID: "u1", Status: models.PRStatusOpen},
+	} {
+		pr.Source = models.PRSourceAPI
+		pr.CreatedAt = &now
+		d.prs[pr.PullRequestID] = pr
+	}
+
+	for _, pair := range [][2]string{
+		{"pr-1005", "u2"},
+		{"pr-1005", "u3"},
+		{"pr-1006", "u1"},
+		{"pr-1006", "u3"},
+		{"pr-1007", "u5"},
+		{"pr-1008", "u2"},
+	} {
+		d.reviewers = append(d.reviewers, reviewerRow{
+			prID:       pair[0],
+			reviewerID: pair[1],
+			state:      models.ReviewStatePending,
+			assignedAt: now,
+			isActive:   true,
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// teamRow команда с настройками
+type teamRow struct {
+	settings  models.TeamSettings
+	createdAt time.Time
+	// leads user_id лидов по возрастанию, аналог team_leads
+	leads []string
+}
+
+// reviewerRow назначение ревьюера, аналог строки pr_reviewers
+type reviewerRow struct {
+	prID       string
+	reviewerID string
+	state      string
+	assignedAt time.Time
+	reviewedAt *time.Time
+	replacedAt *time.Time
+	isActive   bool
+}
+
+// state все данные хранилища
+type state struct {
+	users     map[string]models.User
+	teams     map[string]teamRow
+	prs       map[string]models.PullRequest
+	reviewers []reviewerRow
+
+	events      []models.Event
+	nextEventID int64
+
+	subscriptions  []models.WebhookSubscription
+	deliveries     []models.WebhookDelivery
+	nextSubID      int64
+	nextDeliveryID int64
+
+	idempotency map[idempotencyID]models.IdempotencyRecord
+
+	tokens      []models.APIToken
+	nextTokenID int64
+
+	audit       []models.AuditEntry
+	nextAuditID int64
+}
+
+func newState() *state {
+	return &state{
+		users: make(map[string]models.User),
+		teams: make(map[string]teamRow),
+		prs:   make(map[string]models.PullRequest),
+
+		idempotency: make(map[idempotencyID]models.IdempotencyRecord),
+	}
+}
+
+// clone делает глубокую копию для отката транзакции
+func (s *state) clone() *state {
+	c := &state{
+		users:          make(map[string]models.User, len(s.users)),
+		teams:          make(map[string]teamRow, len(s.teams)),
+		prs:            make(map[string]models.PullRequest, len(s.prs)),
+		reviewers:      append([]reviewerRow(nil), s.reviewers...),
+		events:         append([]models.Event(nil), s.events...),
+		nextEventID:    s.nextEventID,
+		subscriptions:  make([]models.WebhookSubscription, len(s.subscriptions)),
+		deliveries:     append([]models.WebhookDelivery(nil), s.deliveries...),
+		nextSubID:      s.nextSubID,
+		nextDeliveryID: s.nextDeliveryID,
+		idempotency:    make(map[idempotencyID]models.IdempotencyRecord, len(s.idempotency)),
+		tokens:         make([]models.APIToken, len(s.tokens)),
+		nextTokenID:    s.nextTokenID,
+		audit:          append([]models.AuditEntry(nil), s.audit...),
+		nextAuditID:    s.nextAuditID,
+	}
+	for id, user := range s.users {
+		c.users[id] = user
+	}
+	for name, team := range s.teams {
+		team.leads = append([]string(nil), team.leads...)
+		c.teams[name] = team
+	}
+	for id, pr := range s.prs {
+		c.prs[id] = pr
+	}
+	for i, sub := range s.subscriptions {
+		sub.Events = append([]string(nil), sub.Events...)
+		c.subscriptions[i] = sub
+	}
+	for id, rec := range s.idempotency {
+		c.idempotency[id] = rec
+	}
+	for i, token := range s.tokens {
+		token.Scopes = append([]string(nil), token.Scopes...)
+		c.tokens[i] = token
+	}
+	return c
+}
+
+// Store хранилище в памяти для тестов и локальных демо.
+// Все репозитории одного Store видят одни и те же данные.
+type Store struct {
+	mu   sync.Mutex
+	data *state
+}
+
+func NewStore() *Store {
+	return &Store{data: newState()}
+}
+
+// conn доступ репозитория к Store. Внутри транзакции блокировка уже
+// взята TxManager, поэтому операции выполняются без неё.
+type conn struct {
+	store *Store
+	inTx  bool
+}
+
+func (c conn) do(ctx context.Context, fn func(d *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !c.inTx {
+		c.store.mu.Lock()
+		defer c.store.mu.Unlock()
+	}
+	return fn(c.store.data)
+}
+
+// TxManager транзакции поверх Store: на время fn хранилище блокируется,
+// при ошибке данные восстанавливаются из снимка.
+// Внутри fn нельзя обращаться к репозиториям вне транзакции — это дедлок.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(repos repository.Repositories) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	snapshot := m.store.data.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			m.store.data = snapshot
+			panic(p)
+		}
+	}()
+
+	c := conn{store: m.store, inTx: true}
+	repos := repository.Repositories{
+		Users:  &UserRepository{conn: c},
+		Teams:  &TeamRepository{conn: c},
+		PRs:    &PRRepository{conn: c},
+		Tokens: &TokenRepository{conn: c},
+		Audit:  &AuditRepository{conn: c},
+	}
+
+	if err := fn(repos); err != nil {
+		m.store.data = snapshot
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_WithinTx_Commit(t *testing.T) {
+	ctx := context.Background()
+	store := newSeededStore(t)
+
+	err := NewTxManager(store).WithinTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.PRs.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "x", AuthorID: "u1"}); err != nil {
+			return err
+		}
+		return repos.PRs.AddPRReviewers(ctx, "pr-1", []string{"u2"})
+	})
+	require.NoError(t, err)
+
+	pr, err := NewPRRepository(store).GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+}
+
+func TestTxManager_WithinTx_Rollback(t *testing.T) {
+	ctx := context.Background()
+	store := newSeededStore(t)
+
+	assignErr := fmt.Errorf("no reviewers")
+	err := NewTxManager(store).WithinTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Users.SetUserActive(ctx, "u2", false); err != nil {
+			return err
+		}
+		if err := repos.PRs.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "x", AuthorID: "u1"}); err != nil {
+			return err
+		}
+		return assignErr
+	})
+	assert.ErrorIs(t, err, assignErr)
+
+	exists, err := NewPRRepository(store).PRExists(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	user, err := NewUserRepository(store).GetUserByID(ctx, "u2")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+
+	events, err := NewEventRepository(store).GetPendingEvents(ctx, 10, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events, "события отменённой транзакции не публикуются")
+}
+
+func TestStore_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewUserRepository(newSeededStore(t)).GetUserByID(ctx, "u1")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// настройки новой команды, как DEFAULT в схеме postgres
+var defaultTeamSettings = models.TeamSettings{
+	SelectionStrategy: "random",
+	ReviewerCount:     2,
+}
+
+// реализует repository.TeamRepository в памяти
+type TeamRepository struct {
+	conn
+}
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{conn: conn{store: store}}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, teamName string) error {
+	return r.do(ctx, func(d *state) error {
+		if _, ok := d.teams[teamName]; ok {
+			return fmt.Errorf("%w: team '%s' already exists", repository.ErrConflict, teamName)
+		}
+		d.teams[teamName] = teamRow{settings: defaultTeamSettings, createdAt: time.Now()}
+		return nil
+	})
+}
+
+func (r *TeamRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	err := r.do(ctx, func(d *state) error {
+		_, exists = d.teams[teamName]
+		return nil
+	})
+	return exists, err
+}
+
+func (r *TeamRepository) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	err := r.do(ctx, func(d *state) error {
+		team, ok := d.teams[teamName]
+		if !ok {
+			return fmt.Errorf("failed to get settings for team %s: %w", teamName, repository.ErrNotFound)
+		}
+		settings = team.settings
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *TeamRepository) UpdateTeamSettings(ctx context.Context, teamName string, settings *models.TeamSettings) error {
+	return r.do(ctx, func(d *state) error {
+		team, ok := d.teams[teamName]
+		if !ok {
+			return fmt.Errorf("%w: team '%s'", repository.ErrNotFound, teamName)
+		}
+		team.settings = *settings
+		d.teams[teamName] = team
+		return nil
+	})
+}
+
+func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	var team *models.Team
+	err := r.do(ctx, func(d *state) error {
+		if _, ok := d.teams[teamName]; !ok {
+			return fmt.Errorf("%w: team '%s'", repository.ErrNotFound, teamName)
+		}
+
+		users := usersByTeam(d, teamName)
+		members := make([]models.TeamMember, len(users))
+		for i, user := range users {
+			members[i] = models.TeamMember{
+				UserID:   user.UserID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			}
+		}
+
+		team = &models.Team{
+			TeamName: teamName,
+			Members:  members,
+		}
+		return nil
+	})
+	return team, err
+}
+
+func (r *TeamRepository) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	var users []models.User
+	err := r.do(ctx, func(d *state) error {
+		users = usersByTeam(d, teamName)
+		return nil
+	})
+	return users, err
+}
+
+func (r *TeamRepository) ListTeams(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.do(ctx, func(d *state) error {
+		teams = make([]models.Team, 0, len(d.teams))
+		for name, row := range d.teams {
+			members := []models.TeamMember{}
+			for _, user := range usersByTeam(d, name) {
+				members = append(members, models.TeamMember{
+					UserID:   user.UserID,
+					Username: user.Username,
+					IsActive: user.IsActive,
+				})
+			}
+			teams = append(teams, models.Team{TeamName: name, TeamSettings: row.settings, Members: members})
+		}
+		sort.Slice(teams, func(i, j int) bool {
+			return teams[i].TeamName < teams[j].TeamName
+		})
+		return nil
+	})
+	return teams, err
+}
+
+func (r *TeamRepository) ListTeamMembers(ctx context.Context, teamName string, page repository.Page) ([]models.TeamMember, string, error) {
+	after, err := repository.DecodeCursor(page.Cursor, repository.MemberSort)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := repository.PageLimit(page.Limit)
+
+	members := []models.TeamMember{}
+	err = r.do(ctx, func(d *state) error {
+		for _, user := range usersByTeam(d, teamName) {
+			if after != nil && user.UserID <= after.ID {
+				continue
+			}
+			members = append(members, models.TeamMember{
+				UserID:   user.UserID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+			// лишняя запись показывает, есть ли следующая страница
+			if len(members) > limit {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	more := len(members) > limit
+	if more {
+		members = members[:limit]
+	}
+	return members, repository.NextMemberCursor(members, more), nil
+}
+
+func (r *TeamRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	return r.do(ctx, func(d *state) error {
+		if _, ok := d.teams[teamName]; !ok {
+			return fmt.Errorf("%w: team '%s'", repository.ErrNotFound, teamName)
+		}
+		// как внешний ключ users.team_name в SQL-хранилищах
+		for _, user := range d.users {
+			if user.TeamName == teamName {
+				return fmt.Errorf("%w: team '%s' has members", repository.ErrConflict, teamName)
+			}
+		}
+		delete(d.teams, teamName)
+		return nil
+	})
+}
+
+func (r *TeamRepository) AddTeamLead(ctx context.Context, teamName, userID string) error {
+	return r.do(ctx, func(d *state) error {
+		team, ok := d.teams[teamName]
+		if !ok {
+			return fmt.Errorf("%w: team '%s' does not exist", repository.ErrConflict, teamName)
+		}
+		if _, ok := d.users[userID]; !ok {
+			return fmt.Errorf("%w: user %s does not exist", repository.ErrConflict, userID)
+		}
+		if i, found := slices.BinarySearch(team.leads, userID); !found {
+			team.leads = slices.Insert(team.leads, i, userID)
+			d.teams[teamName] = team
+		}
+		return nil
+	})
+}
+
+func (r *TeamRepository) RemoveTeamLead(ctx context.Context, teamName, userID string) error {
+	return r.do(ctx, func(d *state) error {
+		team := d.teams[teamName]
+		i, found := slices.BinarySearch(team.leads, userID)
+		if !found {
+			return fmt.Errorf("%w: lead %s of team '%s'", repository.ErrNotFound, userID, teamName)
+		}
+		team.leads = slices.Delete(team.leads, i, i+1)
+		d.teams[teamName] = team
+		return nil
+	})
+}
+
+func (r *TeamRepository) GetTeamLeads(ctx context.Context, teamName string) ([]string, error) {
+	leads := []string{}
+	err := r.do(ctx, func(d *state) error {
+		leads = append(leads, d.teams[teamName].leads...)
+		return nil
+	})
+	return leads, err
+}
+
+func (r *TeamRepository) GetLedTeams(ctx context.Context, userID string) ([]string, error) {
+	teams := []string{}
+	err := r.do(ctx, func(d *state) error {
+		for name, team := range d.teams {
+			if slices.Contains(team.leads, userID) {
+				teams = append(teams, name)
+			}
+		}
+		return nil
+	})
+	sort.Strings(teams)
+	return teams, err
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// реализует repository.TokenRepository в памяти
+type TokenRepository struct {
+	conn
+}
+
+func NewTokenRepository(store *Store) *TokenRepository {
+	return &TokenRepository{conn: conn{store: store}}
+}
+
+func (r *TokenRepository) CreateToken(ctx context.Context, token *models.APIToken) error {
+	return r.do(ctx, func(d *state) error {
+		for _, existing := range d.tokens {
+			if existing.TokenHash == token.TokenHash {
+				return fmt.Errorf("%w: api token already exists", repository.ErrConflict)
+			}
+		}
+
+		d.nextTokenID++
+		now := time.Now()
+		token.ID = d.nextTokenID
+		token.CreatedAt = &now
+
+		row := *token
+		row.Scopes = append([]string{}, token.Scopes...)
+		d.tokens = append(d.tokens, row)
+		return nil
+	})
+}
+
+func (r *TokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.do(ctx, func(d *state) error {
+		for _, row := range d.tokens {
+			if row.TokenHash == tokenHash {
+				token = row
+				token.Scopes = append([]string{}, row.Scopes...)
+				return nil
+			}
+		}
+		return fmt.Errorf("api token: %w", repository.ErrNotFound)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *TokenRepository) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	err := r.do(ctx, func(d *state) error {
+		tokens = make([]models.APIToken, len(d.tokens))
+		for i, row := range d.tokens {
+			row.Scopes = append([]string{}, row.Scopes...)
+			tokens[i] = row
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *TokenRepository) RevokeToken(ctx context.Context, id int64) error {
+	return r.do(ctx, func(d *state) error {
+		for i := range d.tokens {
+			if d.tokens[i].ID == id && d.tokens[i].RevokedAt == nil {
+				now := time.Now()
+				d.tokens[i].RevokedAt = &now
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: api token %d", repository.ErrNotFound, id)
+	})
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// реализует repository.UserRepository в памяти
+type UserRepository struct {
+	conn
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{conn: conn{store: store}}
+}
+
+func (r *UserRepository) CreateOrUpdateUser(ctx context.Context, user *models.User) error {
+	return r.do(ctx, func(d *state) error {
+		now := time.Now()
+		row := *user
+		row.UpdatedAt = now
+		if existing, ok := d.users[user.UserID]; ok {
+			row.CreatedAt = existing.CreatedAt
+		} else {
+			row.CreatedAt = now
+		}
+		d.users[user.UserID] = row
+		return nil
+	})
+}
+
+func (r *UserRepository) SetUserActive(ctx context.Context, userID string, isActive bool) error {
+	return r.do(ctx, func(d *state) error {
+		user, ok := d.users[userID]
+		if !ok {
+			return fmt.Errorf("%w: user %s", repository.ErrNotFound, userID)
+		}
+		user.IsActive = isActive
+		user.UpdatedAt = time.Now()
+		d.users[userID] = user
+		return nil
+	})
+}
+
+func (r *UserRepository) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	var users []models.User
+	err := r.do(ctx, func(d *state) error {
+		users = usersByTeam(d, teamName)
+		return nil
+	})
+	return users, err
+}
+
+func (r *UserRepository) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
+	var users []models.User
+	err := r.do(ctx, func(d *state) error {
+		for _, user := range usersByTeam(d, teamName) {
+			if user.IsActive && user.UserID != excludeUserID {
+				users = append(users, user)
+			}
+		}
+		return nil
+	})
+
+	// как ORDER BY RANDOM() в postgres
+	rand.Shuffle(len(users), func(i, j int) {
+		users[i], users[j] = users[j], users[i]
+	})
+	return users, err
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.do(ctx, func(d *state) error {
+		found, ok := d.users[userID]
+		if !ok {
+			return fmt.Errorf("%w: user %s", repository.ErrNotFound, userID)
+		}
+		user = found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// usersByTeam участники команды в порядке user_id
+func usersByTeam(d *state, teamName string) []models.User {
+	var users []models.User
+	for _, user := range d.users {
+		if user.TeamName == teamName {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	return users
+}
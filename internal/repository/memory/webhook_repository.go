@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// реализует repository.WebhookRepository в памяти
+type WebhookRepository struct {
+	conn
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{conn: conn{store: store}}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return r.do(ctx, func(d *state) error {
+		d.nextSubID++
+		now := time.Now()
+		sub.ID = d.nextSubID
+		sub.CreatedAt = &now
+
+		row := *sub
+		row.Events = append([]string{}, sub.Events...)
+		d.subscriptions = append(d.subscriptions, row)
+		return nil
+	})
+}
+
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.do(ctx, func(d *state) error {
+		subs = make([]models.WebhookSubscription, len(d.subscriptions))
+		for i, sub := range d.subscriptions {
+			sub.Events = append([]string{}, sub.Events...)
+			subs[i] = sub
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := r.do(ctx, func(d *state) error {
+		for _, row := range d.subscriptions {
+			if row.ID == id {
+				sub = row
+				sub.Events = append([]string{}, row.Events...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: webhook subscription %d", repository.ErrNotFound, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// DeleteSubscription удаляет подписку вместе с журналом доставок
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	return r.do(ctx, func(d *state) error {
+		idx := -1
+		for i, row := range d.subscriptions {
+			if row.ID == id {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("%w: webhook subscription %d", repository.ErrNotFound, id)
+		}
+		d.subscriptions = append(d.subscriptions[:idx], d.subscriptions[idx+1:]...)
+
+		kept := d.deliveries[:0]
+		for _, delivery := range d.deliveries {
+			if delivery.SubscriptionID != id {
+				kept = append(kept, delivery)
+			}
+		}
+		d.deliveries = kept
+		return nil
+	})
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.do(ctx, func(d *state) error {
+		// повтор события из outbox не создаёт вторую доставку той же подписке
+		if delivery.EventID != nil {
+			for _, row := range d.deliveries {
+				if row.SubscriptionID == delivery.SubscriptionID && row.EventID != nil && *row.EventID == *delivery.EventID {
+					return fmt.Errorf("%w: delivery of event %d to subscription %d",
+						repository.ErrConflict, *delivery.EventID, delivery.SubscriptionID)
+				}
+			}
+		}
+
+		d.nextDeliveryID++
+		now := time.Now()
+		delivery.ID = d.nextDeliveryID
+		delivery.CreatedAt = &now
+		d.deliveries = append(d.deliveries, *delivery)
+		return nil
+	})
+}
+
+// ClaimDueDeliveries забирает доставки с наступившей попыткой и откладывает
+// их следующую попытку до leaseUntil
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := r.do(ctx, func(d *state) error {
+		now := time.Now()
+		var due []*models.WebhookDelivery
+		for i := range d.deliveries {
+			row := &d.deliveries[i]
+			if row.Status == models.DeliveryStatusPending && row.NextAttemptAt != nil && !row.NextAttemptAt.After(now) {
+				due = append(due, row)
+			}
+		}
+		sort.SliceStable(due, func(i, j int) bool {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		})
+
+		for _, row := range due {
+			if len(deliveries) >= limit {
+				break
+			}
+			lease := leaseUntil
+			row.NextAttemptAt = &lease
+			deliveries = append(deliveries, *row)
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+// UpdateDelivery сохраняет результат очередной попытки доставки
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.do(ctx, func(d *state) error {
+		for i := range d.deliveries {
+			row := &d.deliveries[i]
+			if row.ID != delivery.ID {
+				continue
+			}
+			row.Status = delivery.Status
+			row.Attempts = delivery.Attempts
+			row.ResponseCode = delivery.ResponseCode
+			row.LastError = delivery.LastError
+			row.DeliveredAt = delivery.DeliveredAt
+			row.NextAttemptAt = delivery.NextAttemptAt
+		}
+		return nil
+	})
+}
+
+// GetDeliveries возвращает последние доставки подписки, новые первыми
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	err := r.do(ctx, func(d *state) error {
+		for i := len(d.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+			if d.deliveries[i].SubscriptionID == subscriptionID {
+				deliveries = append(deliveries, d.deliveries[i])
+			}
+		}
+		return nil
+	})
+	return deliveries, err
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/models"
+)
+
+// размер страницы списков
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ключи сортировки списка PR, "-" перед ключом — по убыванию
+const (
+	PRSortCreatedAt = "created_at"
+	PRSortID        = "pull_request_id"
+	// DefaultPRSort сначала новые
+	DefaultPRSort = "-" + PRSortCreatedAt
+)
+
+// MemberSort единственный порядок участников команды
+const MemberSort = "user_id"
+
+// AuditSort единственный порядок журнала аудита: сначала новые
+const AuditSort = "-id"
+
+// формат времени в параметрах запросов: фиксированная ширина в UTC, чтобы
+// SQLite сравнивал строки как время, postgres разбирает его как timestamptz
+const timeParamFormat = "2006-01-02 15:04:05.000000000-07:00"
+
+func timeParam(t time.Time) string {
+	return t.UTC().Format(timeParamFormat)
+}
+
+// nullTimeParam timeParam для необязательного времени, nil — NULL
+func nullTimeParam(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timeParam(*t)
+}
+
+// Page запрос страницы: не больше Limit записей после курсора из прошлого ответа
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// PRFilter отбор и порядок списка PR, пустые поля выборку не ограничивают
+type PRFilter struct {
+	Status string
+	// AuthorID автор PR
+	AuthorID string
+	// ReviewerID ревьюер с активным назначением
+	ReviewerID string
+	// TeamName команда автора
+	TeamName    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Sort ключ сортировки, по умолчанию DefaultPRSort
+	Sort string
+}
+
+// AuditFilter отбор журнала аудита, пустые поля выборку не ограничивают
+type AuditFilter struct {
+	// Actor subject автора изменения
+	Actor      string
+	Operation  string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+// ValidPRSort проверяет ключ сортировки списка PR
+func ValidPRSort(sort string) bool {
+	switch strings.TrimPrefix(sort, "-") {
+	case PRSortCreatedAt, PRSortID:
+		return true
+	}
+	return false
+}
+
+// SplitPRSort разбирает ключ сортировки PR на колонку и направление
+func SplitPRSort(sort string) (key string, desc bool) {
+	if sort == "" {
+		sort = DefaultPRSort
+	}
+	return strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+}
+
+// PageLimit приводит размер страницы к допустимому
+func PageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultPageLimit
+	case limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// Cursor позиция в списке: значение ключа сортировки и id последней записи.
+// Сортировка входит в курсор, чтобы его нельзя было применить к другому порядку.
+// Клиенту курсор отдаётся непрозрачной строкой.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k,omitempty"`
+	ID   string `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Time значение ключа created_at
+func (c Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return t, nil
+}
+
+// DecodeCursor разбирает курсор для сортировки sort; пустая строка — первая страница
+func DecodeCursor(s, sort string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if c.Sort != sort || c.ID == "" {
+		return nil, fmt.Errorf("%w: issued for another sort order", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// DecodePRCursor разбирает курсор списка PR
+func DecodePRCursor(s, sort string) (*Cursor, error) {
+	if sort == "" {
+		sort = DefaultPRSort
+	}
+	c, err := DecodeCursor(s, sort)
+	if err != nil || c == nil {
+		return c, err
+	}
+	if key, _ := SplitPRSort(sort); key == PRSortCreatedAt {
+		if _, err := c.Time(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// NextPRCursor курсор после последнего PR страницы; more — есть ли записи дальше
+func NextPRCursor(prs []models.PullRequestShort, sort string, more bool) string {
+	if !more || len(prs) == 0 {
+		return ""
+	}
+	if sort == "" {
+		sort = DefaultPRSort
+	}
+	last := prs[len(prs)-1]
+	c := Cursor{Sort: sort, ID: last.PullRequestID}
+	if key, _ := SplitPRSort(sort); key == PRSortCreatedAt && last.CreatedAt != nil {
+		c.Key = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c.Encode()
+}
+
+// NextMemberCursor курсор после последнего участника страницы
+func NextMemberCursor(members []models.TeamMember, more bool) string {
+	if !more || len(members) == 0 {
+		return ""
+	}
+	return Cursor{Sort: MemberSort, ID: members[len(members)-1].UserID}.Encode()
+}
+
+// NextAuditCursor курсор после последней записи журнала на странице
+func NextAuditCursor(entries []models.AuditEntry, more bool) string {
+	if !more || len(entries) == 0 {
+		return ""
+	}
+	return Cursor{Sort: AuditSort, ID: strconv.FormatInt(entries[len(entries)-1].ID, 10)}.Encode()
+}
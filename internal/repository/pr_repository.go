@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"ReviewAssigner/internal/models"
 
@@ -10,36 +12,58 @@ import (
 
 // реализует PRRepository интерфейс
 type PRRepositoryImpl struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewPRRepository(db *sqlx.DB) *PRRepositoryImpl {
 	return &PRRepositoryImpl{db: db}
 }
 
-func (r *PRRepositoryImpl) CreatePR(pr *models.PullRequest) error {
+func (r *PRRepositoryImpl) CreatePR(ctx context.Context, pr *models.PullRequest) error {
 	query := `
-		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
+		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, source, source_url, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
 	`
-	_, err := r.db.Exec(query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, "OPEN")
-	return err
+	status := pr.Status
+	if status == "" {
+		status = models.PRStatusOpen
+	}
+	source := pr.Source
+	if source == "" {
+		source = models.PRSourceAPI
+	}
+
+	return withTx(ctx, r.db, func(tx dbtx) error {
+		_, err := tx.ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, status, source, pr.SourceURL)
+		if err != nil {
+			return dbError(err)
+		}
+
+		return insertEvent(ctx, tx, models.EventPRCreated, pr.PullRequestID, models.PRCreatedData{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          status,
+			Source:          source,
+			SourceURL:       pr.SourceURL,
+		})
+	})
 }
 
-func (r *PRRepositoryImpl) DeletePR(prID string) error {
+func (r *PRRepositoryImpl) DeletePR(ctx context.Context, prID string) error {
 	query := `DELETE FROM pull_requests WHERE pull_request_id = $1`
-	_, err := r.db.Exec(query, prID)
-	return err
+	_, err := r.db.ExecContext(ctx, query, prID)
+	return dbError(err)
 }
 
-func (r *PRRepositoryImpl) PRExists(prID string) (bool, error) {
+func (r *PRRepositoryImpl) PRExists(ctx context.Context, prID string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
-	err := r.db.Get(&exists, query, prID)
-	return exists, err
+	err := r.db.GetContext(ctx, &exists, query, prID)
+	return exists, dbError(err)
 }
 
-func (r *PRRepositoryImpl) GetPRByID(prID string) (*models.PullRequest, error) {
+func (r *PRRepositoryImpl) GetPRByID(ctx context.Context, prID string) (*models.PullRequest, error) {
 	var pr models.PullRequest
 	query := `
         SELECT 
@@ -47,99 +71,199 @@ func (r *PRRepositoryImpl) GetPRByID(prID string) (*models.PullRequest, error) {
             pull_request_name, 
             author_id, 
             status,
+            source,
+            source_url,
             created_at,
-            merged_at
+            merged_at,
+            closed_at
         FROM pull_requests 
         WHERE pull_request_id = $1
     `
-	err := r.db.Get(&pr, query, prID)
+	err := r.db.GetContext(ctx, &pr, query, prID)
 	if err != nil {
-		return nil, fmt.Errorf("PR not found")
+		return nil, fmt.Errorf("PR %s: %w", prID, dbError(err))
 	}
 
-	reviewers, err := r.GetPRReviewers(prID)
+	reviews, err := r.GetPRReviews(ctx, prID)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
+	}
+	pr.Reviews = reviews
+	pr.AssignedReviewers = make([]string, len(reviews))
+	for i, review := range reviews {
+		pr.AssignedReviewers[i] = review.ReviewerID
 	}
-	pr.AssignedReviewers = reviewers
 
 	return &pr, nil
 }
 
-func (r *PRRepositoryImpl) MergePR(prID string) error {
+// MergePR мерджит открытый PR; ErrConflict — PR уже не в статусе OPEN
+func (r *PRRepositoryImpl) MergePR(ctx context.Context, prID string) error {
 	query := `
 		UPDATE pull_requests 
 		SET status = 'MERGED', merged_at = NOW(), updated_at = NOW()
-		WHERE pull_request_id = $1
+		WHERE pull_request_id = $1 AND status = 'OPEN'
 	`
-	_, err := r.db.Exec(query, prID)
-	return err
+	return withTx(ctx, r.db, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx, query, prID)
+		if err != nil {
+			return dbError(err)
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return fmt.Errorf("%w: PR %s is not open", ErrConflict, prID)
+		}
+
+		return insertEvent(ctx, tx, models.EventPRMerged, prID, models.PRMergedData{PullRequestID: prID})
+	})
 }
 
-func (r *PRRepositoryImpl) AddPRReviewer(prID, reviewerID string) error {
-	// предполагаем, что на пару (pull_request_id, reviewer_id) есть уникальный индекс
+// TransitionPR меняет статус PR, только если текущий статус равен fromStatus
+func (r *PRRepositoryImpl) TransitionPR(ctx context.Context, prID, fromStatus, toStatus string) error {
 	query := `
-		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at, is_active)
-		VALUES ($1, $2, NOW(), true)
-		ON CONFLICT (pull_request_id, reviewer_id)
-		DO UPDATE SET is_active = true, replaced_at = NULL, assigned_at = NOW()
+		UPDATE pull_requests
+		SET
+			status = $1,
+			closed_at = CASE WHEN $2 THEN NOW() ELSE NULL END,
+			updated_at = NOW()
+		WHERE pull_request_id = $3 AND status = $4
 	`
-	_, err := r.db.Exec(query, prID, reviewerID)
-	return err
+	return withTx(ctx, r.db, func(tx dbtx) error {
+		result, err := tx.ExecContext(ctx, query, toStatus, toStatus == models.PRStatusClosed, prID, fromStatus)
+		if err != nil {
+			return dbError(err)
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return fmt.Errorf("%w: PR %s is not in status %s", ErrConflict, prID, fromStatus)
+		}
+
+		return insertEvent(ctx, tx, models.EventPRStatusChanged, prID, models.PRStatusChangedData{
+			PullRequestID: prID,
+			From:          fromStatus,
+			To:            toStatus,
+		})
+	})
 }
 
-func (r *PRRepositoryImpl) ReplacePRReviewer(prID, oldReviewerID, newReviewerID string) error {
-	tx, err := r.db.Beginx()
-	if err != nil {
-		return err
-	}
-	// если не коммит — откатим
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	// деактивация старого ревьювера
-	updateQuery := `
-		UPDATE pr_reviewers 
+// ReleaseReviewers снимает всех активных ревьюеров с PR
+func (r *PRRepositoryImpl) ReleaseReviewers(ctx context.Context, prID string) error {
+	query := `
+		UPDATE pr_reviewers
 		SET is_active = false, replaced_at = NOW()
-		WHERE pull_request_id = $1 AND reviewer_id = $2 AND is_active = true
+		WHERE pull_request_id = $1 AND is_active = true
 	`
-	result, err := tx.Exec(updateQuery, prID, oldReviewerID)
-	if err != nil {
-		return err
-	}
+	_, err := r.db.ExecContext(ctx, query, prID)
+	return dbError(err)
+}
 
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("reviewer not assigned to this PR")
+// AddPRReviewers назначает ревьюеров одной транзакцией с событием reviewer.assigned
+func (r *PRRepositoryImpl) AddPRReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
+	if len(reviewerIDs) == 0 {
+		return nil
 	}
 
-	// добавление или активация нового ревьювера
-	insertQuery := `
+	// на пару (pull_request_id, reviewer_id) есть частичный уникальный индекс по активным записям
+	query := `
 		INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at, is_active)
 		VALUES ($1, $2, NOW(), true)
-		ON CONFLICT (pull_request_id, reviewer_id)
-		DO UPDATE SET is_active = true, replaced_at = NULL, assigned_at = NOW()
+		ON CONFLICT (pull_request_id, reviewer_id) WHERE is_active = true
+		DO UPDATE SET replaced_at = NULL, assigned_at = NOW(), review_state = 'PENDING', reviewed_at = NULL
 	`
-	_, err = tx.Exec(insertQuery, prID, newReviewerID)
-	if err != nil {
-		return err
-	}
+	return withTx(ctx, r.db, func(tx dbtx) error {
+		for _, reviewerID := range reviewerIDs {
+			if _, err := tx.ExecContext(ctx, query, prID, reviewerID); err != nil {
+				return fmt.Errorf("failed to add reviewer %s: %w", reviewerID, dbError(err))
+			}
+		}
+
+		return insertEvent(ctx, tx, models.EventReviewerAssigned, prID, models.ReviewerAssignedData{
+			PullRequestID: prID,
+			ReviewerIDs:   reviewerIDs,
+		})
+	})
+}
 
-	return tx.Commit()
+func (r *PRRepositoryImpl) ReplacePRReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+	return withTx(ctx, r.db, func(tx dbtx) error {
+		// деактивация старого ревьювера
+		updateQuery := `
+			UPDATE pr_reviewers 
+			SET is_active = false, replaced_at = NOW()
+			WHERE pull_request_id = $1 AND reviewer_id = $2 AND is_active = true
+		`
+		result, err := tx.ExecContext(ctx, updateQuery, prID, oldReviewerID)
+		if err != nil {
+			return dbError(err)
+		}
+
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			return fmt.Errorf("%w: reviewer not assigned to this PR", ErrNotFound)
+		}
+
+		// добавление или активация нового ревьювера
+		insertQuery := `
+			INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at, is_active)
+			VALUES ($1, $2, NOW(), true)
+			ON CONFLICT (pull_request_id, reviewer_id) WHERE is_active = true
+			DO UPDATE SET replaced_at = NULL, assigned_at = NOW(), review_state = 'PENDING', reviewed_at = NULL
+		`
+		_, err = tx.ExecContext(ctx, insertQuery, prID, newReviewerID)
+		if err != nil {
+			return dbError(err)
+		}
+
+		return insertEvent(ctx, tx, models.EventReviewerReplaced, prID, models.ReviewerReplacedData{
+			PullRequestID: prID,
+			OldReviewerID: oldReviewerID,
+			NewReviewerID: newReviewerID,
+		})
+	})
 }
 
-func (r *PRRepositoryImpl) GetPRReviewers(prID string) ([]string, error) {
+func (r *PRRepositoryImpl) GetPRReviewers(ctx context.Context, prID string) ([]string, error) {
 	var reviewers []string
 	query := `
 		SELECT reviewer_id FROM pr_reviewers 
 		WHERE pull_request_id = $1 AND is_active = true
 	`
-	err := r.db.Select(&reviewers, query, prID)
-	return reviewers, err
+	err := r.db.SelectContext(ctx, &reviewers, query, prID)
+	return reviewers, dbError(err)
 }
 
-func (r *PRRepositoryImpl) GetAssignedPRs(userID string) ([]models.PullRequestShort, error) {
+func (r *PRRepositoryImpl) GetPRReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	var reviews []models.Review
+	query := `
+		SELECT reviewer_id, review_state, assigned_at, reviewed_at FROM pr_reviewers
+		WHERE pull_request_id = $1 AND is_active = true
+		ORDER BY assigned_at, reviewer_id
+	`
+	err := r.db.SelectContext(ctx, &reviews, query, prID)
+	return reviews, dbError(err)
+}
+
+func (r *PRRepositoryImpl) SetReviewState(ctx context.Context, prID, reviewerID, state string) error {
+	query := `
+		UPDATE pr_reviewers
+		SET review_state = $1, reviewed_at = NOW()
+		WHERE pull_request_id = $2 AND reviewer_id = $3 AND is_active = true
+	`
+	result, err := r.db.ExecContext(ctx, query, state, prID, reviewerID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: reviewer not assigned to this PR", ErrNotFound)
+	}
+	return nil
+}
+
+func (r *PRRepositoryImpl) GetAssignedPRs(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	var prs []models.PullRequestShort
 	query := `
 		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
@@ -148,11 +272,99 @@ func (r *PRRepositoryImpl) GetAssignedPRs(userID string) ([]models.PullRequestSh
 		WHERE prr.reviewer_id = $1 AND prr.is_active = true
 		AND pr.status = 'OPEN'
 	`
-	err := r.db.Select(&prs, query, userID)
-	return prs, err
+	err := r.db.SelectContext(ctx, &prs, query, userID)
+	return prs, dbError(err)
+}
+
+// ListPRs выбирает PR по фильтру с пагинацией по ключу: следующая страница
+// начинается строго после (ключ сортировки, pull_request_id) из курсора,
+// поэтому вставки между запросами не сдвигают уже просмотренные записи
+func (r *PRRepositoryImpl) ListPRs(ctx context.Context, filter PRFilter, page Page) ([]models.PullRequestShort, string, error) {
+	after, err := DecodePRCursor(page.Cursor, filter.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	key, desc := SplitPRSort(filter.Sort)
+	limit := PageLimit(page.Limit)
+
+	var (
+		conds []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != "" {
+		conds = append(conds, "pr.status = "+arg(filter.Status))
+	}
+	if filter.AuthorID != "" {
+		conds = append(conds, "pr.author_id = "+arg(filter.AuthorID))
+	}
+	if filter.TeamName != "" {
+		conds = append(conds, "author.team_name = "+arg(filter.TeamName))
+	}
+	if filter.ReviewerID != "" {
+		conds = append(conds, `EXISTS (
+			SELECT 1 FROM pr_reviewers prr
+			WHERE prr.pull_request_id = pr.pull_request_id
+			AND prr.reviewer_id = `+arg(filter.ReviewerID)+` AND prr.is_active = true
+		)`)
+	}
+	if filter.CreatedFrom != nil {
+		conds = append(conds, "pr.created_at >= "+arg(timeParam(*filter.CreatedFrom)))
+	}
+	if filter.CreatedTo != nil {
+		conds = append(conds, "pr.created_at < "+arg(timeParam(*filter.CreatedTo)))
+	}
+
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+	if after != nil {
+		if key == PRSortCreatedAt {
+			t, _ := after.Time()
+			k, id := arg(timeParam(t)), arg(after.ID)
+			conds = append(conds, fmt.Sprintf(
+				"(pr.created_at %s %s OR (pr.created_at = %s AND pr.pull_request_id %s %s))", op, k, k, op, id))
+		} else {
+			conds = append(conds, fmt.Sprintf("pr.pull_request_id %s %s", op, arg(after.ID)))
+		}
+	}
+
+	query := `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at
+		FROM pull_requests pr
+	`
+	if filter.TeamName != "" {
+		query += " JOIN users author ON author.user_id = pr.author_id"
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	if key == PRSortCreatedAt {
+		query += fmt.Sprintf(" ORDER BY pr.created_at %s, pr.pull_request_id %s", order, order)
+	} else {
+		query += " ORDER BY pr.pull_request_id " + order
+	}
+	// лишняя запись показывает, есть ли следующая страница
+	query += " LIMIT " + arg(limit+1)
+
+	prs := []models.PullRequestShort{}
+	if err := r.db.SelectContext(ctx, &prs, query, args...); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	more := len(prs) > limit
+	if more {
+		prs = prs[:limit]
+	}
+	return prs, NextPRCursor(prs, filter.Sort, more), nil
 }
 
-func (r *PRRepositoryImpl) IsReviewerAssigned(prID, reviewerID string) (bool, error) {
+func (r *PRRepositoryImpl) IsReviewerAssigned(ctx context.Context, prID, reviewerID string) (bool, error) {
 	var assigned bool
 	query := `
 		SELECT EXISTS(
@@ -160,11 +372,11 @@ func (r *PRRepositoryImpl) IsReviewerAssigned(prID, reviewerID string) (bool, er
 			WHERE pull_request_id = $1 AND reviewer_id = $2 AND is_active = true
 		)
 	`
-	err := r.db.Get(&assigned, query, prID, reviewerID)
-	return assigned, err
+	err := r.db.GetContext(ctx, &assigned, query, prID, reviewerID)
+	return assigned, dbError(err)
 }
 
-func (r *PRRepositoryImpl) GetUserAssignmentStats() (map[string]int, error) {
+func (r *PRRepositoryImpl) GetUserAssignmentStats(ctx context.Context) (map[string]int, error) {
 	type statsResult struct {
 		ReviewerID      string `db:"reviewer_id"`
 		AssignmentCount int    `db:"assignment_count"`
@@ -178,9 +390,9 @@ func (r *PRRepositoryImpl) GetUserAssignmentStats() (map[string]int, error) {
 		GROUP BY reviewer_id
 	`
 
-	err := r.db.Select(&results, query)
+	err := r.db.SelectContext(ctx, &results, query)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	stats := make(map[string]int)
@@ -191,33 +403,83 @@ func (r *PRRepositoryImpl) GetUserAssignmentStats() (map[string]int, error) {
 	return stats, nil
 }
 
-func (r *PRRepositoryImpl) GetPRMetrics() (map[string]interface{}, error) {
+// GetOpenReviewLoad считает активные назначения пользователей только на открытые PR
+func (r *PRRepositoryImpl) GetOpenReviewLoad(ctx context.Context, userIDs []string) (map[string]int, error) {
+	load := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return load, nil
+	}
+
+	type loadResult struct {
+		ReviewerID      string `db:"reviewer_id"`
+		AssignmentCount int    `db:"assignment_count"`
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT prr.reviewer_id, COUNT(*) as assignment_count
+		FROM pr_reviewers prr
+		JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+		WHERE prr.is_active = true
+		AND pr.status = 'OPEN'
+		AND prr.reviewer_id IN (?)
+		GROUP BY prr.reviewer_id
+	`, userIDs)
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	var results []loadResult
+	if err := r.db.SelectContext(ctx, &results, r.db.Rebind(query), args...); err != nil {
+		return nil, dbError(err)
+	}
+
+	for _, result := range results {
+		load[result.ReviewerID] = result.AssignmentCount
+	}
+	return load, nil
+}
+
+func (r *PRRepositoryImpl) GetPRMetrics(ctx context.Context) (map[string]interface{}, error) {
 	metrics := make(map[string]interface{})
 
 	var totalPRs int
-	err := r.db.Get(&totalPRs, "SELECT COUNT(*) FROM pull_requests")
+	err := r.db.GetContext(ctx, &totalPRs, "SELECT COUNT(*) FROM pull_requests")
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	metrics["total_prs"] = totalPRs
 
 	var openPRs int
-	err = r.db.Get(&openPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN'")
+	err = r.db.GetContext(ctx, &openPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN'")
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	metrics["open_prs"] = openPRs
 
 	var mergedPRs int
-	err = r.db.Get(&mergedPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED'")
+	err = r.db.GetContext(ctx, &mergedPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED'")
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	metrics["merged_prs"] = mergedPRs
 
+	var draftPRs int
+	err = r.db.GetContext(ctx, &draftPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'DRAFT'")
+	if err != nil {
+		return nil, dbError(err)
+	}
+	metrics["draft_prs"] = draftPRs
+
+	var closedPRs int
+	err = r.db.GetContext(ctx, &closedPRs, "SELECT COUNT(*) FROM pull_requests WHERE status = 'CLOSED'")
+	if err != nil {
+		return nil, dbError(err)
+	}
+	metrics["closed_prs"] = closedPRs
+
 	// ср.кол-во ревьюеров на PR (учитываем только активные записи)
 	var avgReviewers float64
-	err = r.db.Get(&avgReviewers, `
+	err = r.db.GetContext(ctx, &avgReviewers, `
 		SELECT COALESCE(AVG(reviewer_count), 0) 
 		FROM (
 			SELECT pull_request_id, COUNT(*) as reviewer_count 
@@ -227,7 +489,7 @@ func (r *PRRepositoryImpl) GetPRMetrics() (map[string]interface{}, error) {
 		) pr_counts
 	`)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	metrics["avg_reviewers"] = avgReviewers
 
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -8,6 +11,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,11 +30,16 @@ func TestPRRepository_CreatePR(t *testing.T) {
 		AuthorID:        "u1",
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`INSERT INTO pull_requests`).
-		WithArgs("pr-1001", "Add search", "u1", "OPEN").
+		WithArgs("pr-1001", "Add search", "u1", "OPEN", "api", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("pr.created", "pr-1001", sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err = repo.CreatePR(pr)
+	err = repo.CreatePR(context.Background(), pr)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -43,30 +52,74 @@ func TestPRRepository_GetPRByID(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewPRRepository(sqlxDB)
 
-	prRows := sqlmock.NewRows([]string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at"}).
-		AddRow("pr-1001", "Add search", "u1", "OPEN", time.Now(), nil)
+	prRows := sqlmock.NewRows([]string{"pull_request_id", "pull_request_name", "author_id", "status", "source", "source_url", "created_at", "merged_at", "closed_at"}).
+		AddRow("pr-1001", "Add search", "u1", "OPEN", "api", "", time.Now(), nil, nil)
 
-	reviewerRows := sqlmock.NewRows([]string{"reviewer_id"}).
-		AddRow("u2").
-		AddRow("u3")
+	reviewRows := sqlmock.NewRows([]string{"reviewer_id", "review_state", "assigned_at", "reviewed_at"}).
+		AddRow("u2", "APPROVED", time.Now(), time.Now()).
+		AddRow("u3", "PENDING", time.Now(), nil)
 
-	mock.ExpectQuery(`SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at`).
+	mock.ExpectQuery(`SELECT pull_request_id, pull_request_name, author_id, status, source, source_url, created_at, merged_at, closed_at`).
 		WithArgs("pr-1001").
 		WillReturnRows(prRows)
 
-	mock.ExpectQuery(`SELECT reviewer_id FROM pr_reviewers`).
+	mock.ExpectQuery(`SELECT reviewer_id, review_state, assigned_at, reviewed_at FROM pr_reviewers`).
 		WithArgs("pr-1001").
-		WillReturnRows(reviewerRows)
+		WillReturnRows(reviewRows)
 
-	pr, err := repo.GetPRByID("pr-1001")
+	pr, err := repo.GetPRByID(context.Background(), "pr-1001")
 	require.NoError(t, err)
 	assert.Equal(t, "pr-1001", pr.PullRequestID)
 	assert.Equal(t, "Add search", pr.PullRequestName)
 	assert.Equal(t, "u1", pr.AuthorID)
 	assert.Equal(t, "OPEN", pr.Status)
+	assert.Equal(t, "api", pr.Source)
 	assert.Len(t, pr.AssignedReviewers, 2)
 	assert.Contains(t, pr.AssignedReviewers, "u2")
 	assert.Contains(t, pr.AssignedReviewers, "u3")
+	require.Len(t, pr.Reviews, 2)
+	assert.Equal(t, "APPROVED", pr.Reviews[0].State)
+	assert.NotNil(t, pr.Reviews[0].ReviewedAt)
+	assert.Equal(t, "PENDING", pr.Reviews[1].State)
+	assert.Nil(t, pr.Reviews[1].ReviewedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_GetPRByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT pull_request_id`).
+		WithArgs("pr-404").
+		WillReturnError(sql.ErrNoRows)
+
+	pr, err := repo.GetPRByID(context.Background(), "pr-404")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// отказ базы не должен выглядеть как отсутствующий PR
+func TestPRRepository_GetPRByID_Unavailable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectQuery(`SELECT pull_request_id`).
+		WithArgs("pr-1001").
+		WillReturnError(&pq.Error{Code: "08006", Message: "connection failure"})
+
+	pr, err := repo.GetPRByID(context.Background(), "pr-1001")
+	assert.Nil(t, pr)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.NotErrorIs(t, err, ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -78,16 +131,40 @@ func TestPRRepository_MergePR(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewPRRepository(sqlxDB)
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE pull_requests SET status = 'MERGED'`).
 		WithArgs("pr-1001").
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("pr.merged", "pr-1001", `{"pull_request_id":"pr-1001"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err = repo.MergePR("pr-1001")
+	err = repo.MergePR(context.Background(), "pr-1001")
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestPRRepository_AddPRReviewer(t *testing.T) {
+func TestPRRepository_MergePR_NotOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE pull_requests SET status = 'MERGED'.+AND status = 'OPEN'`).
+		WithArgs("pr-1001").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.MergePR(context.Background(), "pr-1001")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_AddPRReviewers(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
@@ -95,15 +172,45 @@ func TestPRRepository_AddPRReviewer(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := NewPRRepository(sqlxDB)
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`INSERT INTO pr_reviewers`).
 		WithArgs("pr-1001", "u2").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO pr_reviewers`).
+		WithArgs("pr-1001", "u3").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("reviewer.assigned", "pr-1001", `{"pull_request_id":"pr-1001","reviewer_ids":["u2","u3"]}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err = repo.AddPRReviewer("pr-1001", "u2")
+	err = repo.AddPRReviewers(context.Background(), "pr-1001", []string{"u2", "u3"})
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPRRepository_AddPRReviewers_RollbackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO pr_reviewers`).
+		WithArgs("pr-1001", "u2").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO pr_reviewers`).
+		WithArgs("pr-1001", "u3").
+		WillReturnError(fmt.Errorf("connection reset"))
+	mock.ExpectRollback()
+
+	err = repo.AddPRReviewers(context.Background(), "pr-1001", []string{"u2", "u3"})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestPRRepository_ReplacePRReviewer(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -122,9 +229,13 @@ func TestPRRepository_ReplacePRReviewer(t *testing.T) {
 		WithArgs("pr-1001", "u3").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("reviewer.replaced", "pr-1001", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mock.ExpectCommit()
 
-	err = repo.ReplacePRReviewer("pr-1001", "u2", "u3")
+	err = repo.ReplacePRReviewer(context.Background(), "pr-1001", "u2", "u3")
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -145,7 +256,7 @@ func TestPRRepository_GetAssignedPRs(t *testing.T) {
 		WithArgs("u2").
 		WillReturnRows(rows)
 
-	prs, err := repo.GetAssignedPRs("u2")
+	prs, err := repo.GetAssignedPRs(context.Background(), "u2")
 	require.NoError(t, err)
 	assert.Len(t, prs, 2)
 	assert.Equal(t, "pr-1001", prs[0].PullRequestID)
@@ -153,6 +264,73 @@ func TestPRRepository_GetAssignedPRs(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestPRRepository_ListPRs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at"}).
+		AddRow("pr-1008", "Refactor API", "u1", "OPEN", created).
+		AddRow("pr-1005", "Add search", "u1", "OPEN", created).
+		AddRow("pr-1003", "Old PR", "u2", "OPEN", created)
+
+	// фильтры в порядке полей PRFilter, последний аргумент — limit+1
+	mock.ExpectQuery(`FROM pull_requests pr JOIN users author ON author.user_id = pr.author_id WHERE pr.status = \$1 AND author.team_name = \$2 AND EXISTS \(.*prr.reviewer_id = \$3.*\) ORDER BY pr.created_at DESC, pr.pull_request_id DESC LIMIT \$4`).
+		WithArgs("OPEN", "backend", "u2", 3).
+		WillReturnRows(rows)
+
+	prs, next, err := repo.ListPRs(context.Background(), PRFilter{
+		Status:     "OPEN",
+		TeamName:   "backend",
+		ReviewerID: "u2",
+	}, Page{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, prs, 2)
+	assert.Equal(t, "pr-1005", prs[1].PullRequestID)
+	require.NotEmpty(t, next, "была лишняя строка — есть следующая страница")
+
+	after, err := DecodePRCursor(next, "")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1005", after.ID)
+
+	// следующая страница начинается строго после (created_at, id) из курсора
+	mock.ExpectQuery(`WHERE \(pr.created_at < \$1 OR \(pr.created_at = \$1 AND pr.pull_request_id < \$2\)\) ORDER BY`).
+		WithArgs("2025-03-01 12:00:00.000000000+00:00", "pr-1005", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"pull_request_id", "pull_request_name", "author_id", "status", "created_at"}).
+			AddRow("pr-1003", "Old PR", "u2", "OPEN", created))
+
+	prs, next, err = repo.ListPRs(context.Background(), PRFilter{}, Page{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Empty(t, next, "последняя страница")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_ListPRs_InvalidCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPRRepository(sqlx.NewDb(db, "sqlmock"))
+
+	idCursor := Cursor{Sort: PRSortID, ID: "pr-1005"}.Encode()
+	for name, page := range map[string]Page{
+		"garbage":      {Cursor: "not-a-cursor"},
+		"another sort": {Cursor: idCursor},
+	} {
+		_, _, err := repo.ListPRs(context.Background(), PRFilter{}, page)
+		assert.ErrorIs(t, err, ErrInvalidCursor, name)
+	}
+
+	_, _, err = repo.ListPRs(context.Background(), PRFilter{Sort: "-" + PRSortID}, Page{Cursor: idCursor})
+	assert.ErrorIs(t, err, ErrInvalidCursor, "направление сортировки тоже входит в курсор")
+	assert.NoError(t, mock.ExpectationsWereMet(), "до базы запрос не доходит")
+}
+
 func TestPRRepository_GetUserAssignmentStats(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -168,9 +346,123 @@ func TestPRRepository_GetUserAssignmentStats(t *testing.T) {
 	mock.ExpectQuery(`SELECT reviewer_id, COUNT`).
 		WillReturnRows(rows)
 
-	stats, err := repo.GetUserAssignmentStats()
+	stats, err := repo.GetUserAssignmentStats(context.Background())
 	require.NoError(t, err)
 	assert.Equal(t, 3, stats["u1"])
 	assert.Equal(t, 5, stats["u2"])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestPRRepository_GetOpenReviewLoad(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+	repo := NewPRRepository(sqlxDB)
+
+	rows := sqlmock.NewRows([]string{"reviewer_id", "assignment_count"}).
+		AddRow("u1", 8)
+
+	mock.ExpectQuery(`SELECT prr.reviewer_id, COUNT\(\*\) as assignment_count FROM pr_reviewers prr JOIN pull_requests pr .* AND pr.status = 'OPEN' AND prr.reviewer_id IN \(\$1, \$2\)`).
+		WithArgs("u1", "u2").
+		WillReturnRows(rows)
+
+	load, err := repo.GetOpenReviewLoad(context.Background(), []string{"u1", "u2"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, load["u1"])
+	assert.Equal(t, 0, load["u2"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_SetReviewState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE pr_reviewers SET review_state = \$1, reviewed_at = NOW\(\)`).
+		WithArgs("APPROVED", "pr-1001", "u2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetReviewState(context.Background(), "pr-1001", "u2", "APPROVED")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_SetReviewState_NotAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE pr_reviewers SET review_state`).
+		WithArgs("COMMENTED", "pr-1001", "u9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.SetReviewState(context.Background(), "pr-1001", "u9", "COMMENTED")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_TransitionPR(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE pull_requests SET status = \$1`).
+		WithArgs("CLOSED", true, "pr-1001", "OPEN").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("pr.status_changed", "pr-1001", `{"pull_request_id":"pr-1001","from":"OPEN","to":"CLOSED"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.TransitionPR(context.Background(), "pr-1001", "OPEN", "CLOSED")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_TransitionPR_StatusChanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE pull_requests SET status = \$1`).
+		WithArgs("OPEN", false, "pr-1001", "DRAFT").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.TransitionPR(context.Background(), "pr-1001", "DRAFT", "OPEN")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPRRepository_ReleaseReviewers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewPRRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE pr_reviewers SET is_active = false`).
+		WithArgs("pr-1001").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.ReleaseReviewers(context.Background(), "pr-1001")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"ReviewAssigner/internal/models"
@@ -10,38 +11,84 @@ import (
 
 // реализует TeamRepository интерфейс
 type TeamRepositoryImpl struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewTeamRepository(db *sqlx.DB) *TeamRepositoryImpl {
 	return &TeamRepositoryImpl{db: db}
 }
 
-func (r *TeamRepositoryImpl) CreateTeam(teamName string) error {
+func (r *TeamRepositoryImpl) CreateTeam(ctx context.Context, teamName string) error {
 	query := `INSERT INTO teams (team_name) VALUES ($1)`
-	_, err := r.db.Exec(query, teamName)
-	return err
+	_, err := r.db.ExecContext(ctx, query, teamName)
+	return dbError(err)
 }
 
-func (r *TeamRepositoryImpl) TeamExists(teamName string) (bool, error) {
+func (r *TeamRepositoryImpl) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
-	err := r.db.Get(&exists, query, teamName)
-	return exists, err
+	err := r.db.GetContext(ctx, &exists, query, teamName)
+	return exists, dbError(err)
 }
 
-func (r *TeamRepositoryImpl) GetTeam(teamName string) (*models.Team, error) {
-	exists, err := r.TeamExists(teamName)
+func (r *TeamRepositoryImpl) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	query := `
+		SELECT
+			selection_strategy,
+			reviewer_count,
+			required_approvals,
+			block_on_changes_requested
+		FROM teams
+		WHERE team_name = $1
+	`
+	err := r.db.GetContext(ctx, &settings, query, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settings for team %s: %w", teamName, dbError(err))
+	}
+	return &settings, nil
+}
+
+func (r *TeamRepositoryImpl) UpdateTeamSettings(ctx context.Context, teamName string, settings *models.TeamSettings) error {
+	query := `
+		UPDATE teams
+		SET
+			selection_strategy = $1,
+			reviewer_count = $2,
+			required_approvals = $3,
+			block_on_changes_requested = $4,
+			updated_at = NOW()
+		WHERE team_name = $5
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		settings.SelectionStrategy,
+		settings.ReviewerCount,
+		settings.RequiredApprovals,
+		settings.BlockOnChangesRequested,
+		teamName)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: team '%s'", ErrNotFound, teamName)
+	}
+	return nil
+}
+
+func (r *TeamRepositoryImpl) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	exists, err := r.TeamExists(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check team existence: %w", err)
+		return nil, fmt.Errorf("failed to check team existence: %w", dbError(err))
 	}
 	if !exists {
-		return nil, fmt.Errorf("team '%s' not found", teamName)
+		return nil, fmt.Errorf("%w: team '%s'", ErrNotFound, teamName)
 	}
 
-	users, err := r.GetUsersByTeam(teamName)
+	users, err := r.GetUsersByTeam(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team users: %w", err)
+		return nil, fmt.Errorf("failed to get team users: %w", dbError(err))
 	}
 
 	members := make([]models.TeamMember, len(users))
@@ -59,7 +106,7 @@ func (r *TeamRepositoryImpl) GetTeam(teamName string) (*models.Team, error) {
 	}, nil
 }
 
-func (r *TeamRepositoryImpl) GetUsersByTeam(teamName string) ([]models.User, error) {
+func (r *TeamRepositoryImpl) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
 	var users []models.User
 	query := `
         SELECT 
@@ -72,9 +119,137 @@ func (r *TeamRepositoryImpl) GetUsersByTeam(teamName string) ([]models.User, err
         FROM users 
         WHERE team_name = $1
     `
-	err := r.db.Select(&users, query, teamName)
+	err := r.db.SelectContext(ctx, &users, query, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users for team %s: %w", teamName, err)
+		return nil, fmt.Errorf("failed to get users for team %s: %w", teamName, dbError(err))
 	}
 	return users, nil
 }
+
+// ListTeams возвращает все команды с настройками и участниками
+func (r *TeamRepositoryImpl) ListTeams(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	query := `
+		SELECT
+			team_name,
+			selection_strategy,
+			reviewer_count,
+			required_approvals,
+			block_on_changes_requested
+		FROM teams
+		ORDER BY team_name
+	`
+	if err := r.db.SelectContext(ctx, &teams, query); err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", dbError(err))
+	}
+
+	var users []models.User
+	usersQuery := `
+		SELECT user_id, username, team_name, is_active, created_at, updated_at
+		FROM users
+		ORDER BY user_id
+	`
+	if err := r.db.SelectContext(ctx, &users, usersQuery); err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", dbError(err))
+	}
+
+	index := make(map[string]int, len(teams))
+	for i := range teams {
+		teams[i].Members = []models.TeamMember{}
+		index[teams[i].TeamName] = i
+	}
+	for _, user := range users {
+		if i, ok := index[user.TeamName]; ok {
+			teams[i].Members = append(teams[i].Members, models.TeamMember{
+				UserID:   user.UserID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+		}
+	}
+	return teams, nil
+}
+
+func (r *TeamRepositoryImpl) DeleteTeam(ctx context.Context, teamName string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM teams WHERE team_name = $1`, teamName)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: team '%s'", ErrNotFound, teamName)
+	}
+	return nil
+}
+
+// ListTeamMembers возвращает участников команды по возрастанию user_id
+// начиная после курсора. Существование команды проверяет вызывающий.
+func (r *TeamRepositoryImpl) ListTeamMembers(ctx context.Context, teamName string, page Page) ([]models.TeamMember, string, error) {
+	after, err := DecodeCursor(page.Cursor, MemberSort)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := PageLimit(page.Limit)
+
+	query := `SELECT user_id, username, is_active FROM users WHERE team_name = $1`
+	args := []interface{}{teamName}
+	if after != nil {
+		query += " AND user_id > $2"
+		args = append(args, after.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY user_id LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	members := []models.TeamMember{}
+	if err := r.db.SelectContext(ctx, &members, query, args...); err != nil {
+		return nil, "", dbError(err)
+	}
+
+	more := len(members) > limit
+	if more {
+		members = members[:limit]
+	}
+	return members, NextMemberCursor(members, more), nil
+}
+
+func (r *TeamRepositoryImpl) AddTeamLead(ctx context.Context, teamName, userID string) error {
+	query := `
+		INSERT INTO team_leads (team_name, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name, user_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, teamName, userID)
+	return dbError(err)
+}
+
+func (r *TeamRepositoryImpl) RemoveTeamLead(ctx context.Context, teamName, userID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM team_leads WHERE team_name = $1 AND user_id = $2`, teamName, userID)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: lead %s of team '%s'", ErrNotFound, userID, teamName)
+	}
+	return nil
+}
+
+func (r *TeamRepositoryImpl) GetTeamLeads(ctx context.Context, teamName string) ([]string, error) {
+	leads := []string{}
+	query := `SELECT user_id FROM team_leads WHERE team_name = $1 ORDER BY user_id`
+	if err := r.db.SelectContext(ctx, &leads, query, teamName); err != nil {
+		return nil, dbError(err)
+	}
+	return leads, nil
+}
+
+func (r *TeamRepositoryImpl) GetLedTeams(ctx context.Context, userID string) ([]string, error) {
+	teams := []string{}
+	query := `SELECT team_name FROM team_leads WHERE user_id = $1 ORDER BY team_name`
+	if err := r.db.SelectContext(ctx, &teams, query, userID); err != nil {
+		return nil, dbError(err)
+	}
+	return teams, nil
+}
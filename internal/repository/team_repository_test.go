@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"ReviewAssigner/internal/models"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
@@ -22,7 +25,7 @@ func TestTeamRepository_CreateTeam(t *testing.T) {
 		WithArgs("backend").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.CreateTeam("backend")
+	err = repo.CreateTeam(context.Background(), "backend")
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -40,7 +43,7 @@ func TestTeamRepository_TeamExists(t *testing.T) {
 		WithArgs("backend").
 		WillReturnRows(rows)
 
-	exists, err := repo.TeamExists("backend")
+	exists, err := repo.TeamExists(context.Background(), "backend")
 	require.NoError(t, err)
 	assert.True(t, exists)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -67,7 +70,7 @@ func TestTeamRepository_GetTeam(t *testing.T) {
 		WithArgs("backend").
 		WillReturnRows(userRows)
 
-	team, err := repo.GetTeam("backend")
+	team, err := repo.GetTeam(context.Background(), "backend")
 	require.NoError(t, err)
 	assert.Equal(t, "backend", team.TeamName)
 	assert.Len(t, team.Members, 2)
@@ -91,9 +94,158 @@ func TestTeamRepository_GetTeam_NotFound(t *testing.T) {
 		WithArgs("nonexistent").
 		WillReturnRows(existsRows)
 
-	team, err := repo.GetTeam("nonexistent")
+	team, err := repo.GetTeam(context.Background(), "nonexistent")
 	assert.Nil(t, team)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_GetTeamSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewTeamRepository(sqlxDB)
+
+	rows := sqlmock.NewRows([]string{"selection_strategy", "reviewer_count", "required_approvals", "block_on_changes_requested"}).
+		AddRow("least_loaded", 3, 2, true)
+	mock.ExpectQuery(`SELECT selection_strategy, reviewer_count, required_approvals, block_on_changes_requested FROM teams`).
+		WithArgs("backend").
+		WillReturnRows(rows)
+
+	settings, err := repo.GetTeamSettings(context.Background(), "backend")
+	require.NoError(t, err)
+	assert.Equal(t, "least_loaded", settings.SelectionStrategy)
+	assert.Equal(t, 3, settings.ReviewerCount)
+	assert.Equal(t, 2, settings.RequiredApprovals)
+	assert.True(t, settings.BlockOnChangesRequested)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_UpdateTeamSettings_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewTeamRepository(sqlxDB)
+
+	mock.ExpectExec(`UPDATE teams SET selection_strategy`).
+		WithArgs("round_robin", 2, 0, false, "nonexistent").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateTeamSettings(context.Background(), "nonexistent", &models.TeamSettings{SelectionStrategy: "round_robin", ReviewerCount: 2})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_ListTeams(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewTeamRepository(sqlxDB)
+
+	teamRows := sqlmock.NewRows([]string{"team_name", "selection_strategy", "reviewer_count", "required_approvals", "block_on_changes_requested"}).
+		AddRow("backend", "random", 2, 1, false).
+		AddRow("empty", "round_robin", 1, 0, true)
+	mock.ExpectQuery(`SELECT team_name, selection_strategy, reviewer_count, required_approvals, block_on_changes_requested FROM teams ORDER BY team_name`).
+		WillReturnRows(teamRows)
+
+	userRows := sqlmock.NewRows([]string{"user_id", "username", "team_name", "is_active", "created_at", "updated_at"}).
+		AddRow("u1", "Alice", "backend", true, time.Now(), time.Now()).
+		AddRow("u2", "Bob", "backend", false, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT user_id, username, team_name, is_active, created_at, updated_at FROM users ORDER BY user_id`).
+		WillReturnRows(userRows)
+
+	teams, err := repo.ListTeams(context.Background())
+	require.NoError(t, err)
+	require.Len(t, teams, 2)
+	assert.Equal(t, "backend", teams[0].TeamName)
+	assert.Equal(t, 1, teams[0].RequiredApprovals)
+	assert.Equal(t, []models.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: false},
+	}, teams[0].Members)
+	assert.Equal(t, "round_robin", teams[1].SelectionStrategy)
+	assert.Empty(t, teams[1].Members)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_ListTeamMembers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewTeamRepository(sqlxDB)
+
+	cursor := Cursor{Sort: MemberSort, ID: "u1"}.Encode()
+	rows := sqlmock.NewRows([]string{"user_id", "username", "is_active"}).
+		AddRow("u2", "Bob", true).
+		AddRow("u3", "Charlie", false)
+	mock.ExpectQuery(`SELECT user_id, username, is_active FROM users WHERE team_name = \$1 AND user_id > \$2 ORDER BY user_id LIMIT \$3`).
+		WithArgs("backend", "u1", 2).
+		WillReturnRows(rows)
+
+	members, next, err := repo.ListTeamMembers(context.Background(), "backend", Page{Limit: 1, Cursor: cursor})
+	require.NoError(t, err)
+	assert.Equal(t, []models.TeamMember{{UserID: "u2", Username: "Bob", IsActive: true}}, members)
+
+	after, err := DecodeCursor(next, MemberSort)
+	require.NoError(t, err)
+	assert.Equal(t, "u2", after.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_DeleteTeam_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewTeamRepository(sqlxDB)
+
+	mock.ExpectExec(`DELETE FROM teams`).
+		WithArgs("nonexistent").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteTeam(context.Background(), "nonexistent")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_GetLedTeams(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTeamRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT team_name FROM team_leads WHERE user_id = \$1 ORDER BY team_name`).
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"team_name"}).AddRow("backend").AddRow("payments"))
+
+	teams, err := repo.GetLedTeams(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend", "payments"}, teams)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeamRepository_RemoveTeamLead_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTeamRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`DELETE FROM team_leads`).
+		WithArgs("backend", "u2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.RemoveTeamLead(context.Background(), "backend", "u2")
+	assert.ErrorIs(t, err, ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
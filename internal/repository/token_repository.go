@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// реализует TokenRepository интерфейс
+type TokenRepositoryImpl struct {
+	db dbtx
+}
+
+func NewTokenRepository(db *sqlx.DB) *TokenRepositoryImpl {
+	return &TokenRepositoryImpl{db: db}
+}
+
+// tokenRow строка api_tokens, области доступа хранятся через запятую
+type tokenRow struct {
+	models.APIToken
+	Scopes string `db:"scopes"`
+}
+
+func (row tokenRow) toModel() models.APIToken {
+	token := row.APIToken
+	token.Scopes = splitList(row.Scopes)
+	return token
+}
+
+func (r *TokenRepositoryImpl) CreateToken(ctx context.Context, token *models.APIToken) error {
+	query := `
+		INSERT INTO api_tokens (name, token_hash, token_prefix, scopes, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, created_at
+	`
+	var created struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := r.db.GetContext(ctx, &created, query, token.Name, token.TokenHash, token.Prefix, strings.Join(token.Scopes, ","))
+	if err != nil {
+		return dbError(err)
+	}
+
+	token.ID = created.ID
+	token.CreatedAt = &created.CreatedAt
+	return nil
+}
+
+func (r *TokenRepositoryImpl) GetTokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var row tokenRow
+	query := `
+		SELECT id, name, token_hash, token_prefix, scopes, created_at, revoked_at
+		FROM api_tokens
+		WHERE token_hash = $1
+	`
+	if err := r.db.GetContext(ctx, &row, query, tokenHash); err != nil {
+		return nil, fmt.Errorf("api token: %w", dbError(err))
+	}
+
+	token := row.toModel()
+	return &token, nil
+}
+
+func (r *TokenRepositoryImpl) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	var rows []tokenRow
+	query := `
+		SELECT id, name, token_hash, token_prefix, scopes, created_at, revoked_at
+		FROM api_tokens
+		ORDER BY id
+	`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, dbError(err)
+	}
+
+	tokens := make([]models.APIToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = row.toModel()
+	}
+	return tokens, nil
+}
+
+func (r *TokenRepositoryImpl) RevokeToken(ctx context.Context, id int64) error {
+	query := `UPDATE api_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: api token %d", ErrNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRepository_GetTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTokenRepository(sqlx.NewDb(db, "sqlmock"))
+
+	created := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "name", "token_hash", "token_prefix", "scopes", "created_at", "revoked_at"}).
+		AddRow(1, "ci", "hash", "ra_abcdefg", "read,write", created, nil)
+	mock.ExpectQuery(`SELECT (.+) FROM api_tokens WHERE token_hash = \$1`).
+		WithArgs("hash").
+		WillReturnRows(rows)
+
+	token, err := repo.GetTokenByHash(context.Background(), "hash")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), token.ID)
+	assert.Equal(t, []string{"read", "write"}, token.Scopes)
+	assert.Nil(t, token.RevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_RevokeToken_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTokenRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`UPDATE api_tokens SET revoked_at = NOW\(\) WHERE id = \$1 AND revoked_at IS NULL`).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.RevokeToken(context.Background(), 7)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
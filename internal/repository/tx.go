@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dbtx общие методы *sqlx.DB и *sqlx.Tx, через которые работают репозитории
+type dbtx interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// withTx выполняет fn в транзакции. Если репозиторий уже работает внутри
+// транзакции TxManager, fn выполняется в ней, без вложенной транзакции.
+func withTx(ctx context.Context, db dbtx, fn func(tx dbtx) error) error {
+	if tx, ok := db.(*sqlx.Tx); ok {
+		return fn(tx)
+	}
+
+	sqlDB, ok := db.(*sqlx.DB)
+	if !ok {
+		return fmt.Errorf("unsupported database handle %T", db)
+	}
+
+	tx, err := sqlDB.BeginTxx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	// если не коммит — откатим
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return dbError(err)
+	}
+	return tx.Commit()
+}
+
+// Repositories набор репозиториев, работающих в одной транзакции
+type Repositories struct {
+	Users  UserRepository
+	Teams  TeamRepository
+	PRs    PRRepository
+	Tokens TokenRepository
+	// Audit журнал аудита: запись попадает в базу вместе с изменением
+	Audit AuditRepository
+}
+
+// TxManager выполняет несколько операций репозиториев атомарно
+type TxManager interface {
+	// WithinTx выполняет fn в транзакции: коммит, если fn вернула nil, иначе откат
+	WithinTx(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// реализует TxManager поверх sqlx
+type SQLTxManager struct {
+	db *sqlx.DB
+}
+
+func NewTxManager(db *sqlx.DB) *SQLTxManager {
+	return &SQLTxManager{db: db}
+}
+
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(repos Repositories) error) (err error) {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", dbError(err))
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	repos := Repositories{
+		Users:  &UserRepositoryImpl{db: tx},
+		Teams:  &TeamRepositoryImpl{db: tx},
+		PRs:    &PRRepositoryImpl{db: tx},
+		Tokens: &TokenRepositoryImpl{db: tx},
+		Audit:  &AuditRepositoryImpl{db: tx},
+	}
+
+	if err := fn(repos); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_WithinTx_Commit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	txManager := NewTxManager(sqlxDB)
+	ctx := context.Background()
+
+	// ReplacePRReviewer внутри WithinTx не открывает свою транзакцию
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET is_active`).
+		WithArgs(false, "u2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE pr_reviewers SET is_active = false`).
+		WithArgs("pr-1001", "u2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO pr_reviewers`).
+		WithArgs("pr-1001", "u3").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WithArgs("reviewer.replaced", "pr-1001", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = txManager.WithinTx(ctx, func(repos Repositories) error {
+		if err := repos.Users.SetUserActive(ctx, "u2", false); err != nil {
+			return err
+		}
+		return repos.PRs.ReplacePRReviewer(ctx, "pr-1001", "u2", "u3")
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManager_WithinTx_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	txManager := NewTxManager(sqlxDB)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO pull_requests`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO events`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	assignErr := fmt.Errorf("no reviewers")
+	err = txManager.WithinTx(ctx, func(repos Repositories) error {
+		if err := repos.PRs.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "x", AuthorID: "u1"}); err != nil {
+			return err
+		}
+		return assignErr
+	})
+	assert.ErrorIs(t, err, assignErr)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"ReviewAssigner/internal/models"
@@ -10,14 +11,14 @@ import (
 
 // реализует UserRepositoryImpl интерфейс
 type UserRepositoryImpl struct {
-	db *sqlx.DB
+	db dbtx
 }
 
 func NewUserRepository(db *sqlx.DB) *UserRepositoryImpl {
 	return &UserRepositoryImpl{db: db}
 }
 
-func (r *UserRepositoryImpl) CreateOrUpdateUser(user *models.User) error {
+func (r *UserRepositoryImpl) CreateOrUpdateUser(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (user_id, username, team_name, is_active, updated_at)
 		VALUES ($1, $2, $3, $4, NOW())
@@ -28,25 +29,25 @@ func (r *UserRepositoryImpl) CreateOrUpdateUser(user *models.User) error {
 			is_active = EXCLUDED.is_active,
 			updated_at = NOW()
 	`
-	_, err := r.db.Exec(query, user.UserID, user.Username, user.TeamName, user.IsActive)
-	return err
+	_, err := r.db.ExecContext(ctx, query, user.UserID, user.Username, user.TeamName, user.IsActive)
+	return dbError(err)
 }
 
-func (r *UserRepositoryImpl) SetUserActive(userID string, isActive bool) error {
+func (r *UserRepositoryImpl) SetUserActive(ctx context.Context, userID string, isActive bool) error {
 	query := `UPDATE users SET is_active = $1, updated_at = NOW() WHERE user_id = $2`
-	result, err := r.db.Exec(query, isActive, userID)
+	result, err := r.db.ExecContext(ctx, query, isActive, userID)
 	if err != nil {
-		return err
+		return dbError(err)
 	}
 
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("user not found")
+		return fmt.Errorf("%w: user %s", ErrNotFound, userID)
 	}
 	return nil
 }
 
-func (r *UserRepositoryImpl) GetUsersByTeam(teamName string) ([]models.User, error) {
+func (r *UserRepositoryImpl) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
 	var users []models.User
 	query := `
         SELECT 
@@ -59,11 +60,11 @@ func (r *UserRepositoryImpl) GetUsersByTeam(teamName string) ([]models.User, err
         FROM users 
         WHERE team_name = $1
     `
-	err := r.db.Select(&users, query, teamName)
-	return users, err
+	err := r.db.SelectContext(ctx, &users, query, teamName)
+	return users, dbError(err)
 }
 
-func (r *UserRepositoryImpl) GetActiveTeamMembers(teamName string, excludeUserID string) ([]models.User, error) {
+func (r *UserRepositoryImpl) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
 	var users []models.User
 	query := `
         SELECT 
@@ -79,11 +80,11 @@ func (r *UserRepositoryImpl) GetActiveTeamMembers(teamName string, excludeUserID
         AND user_id != $2
         ORDER BY RANDOM()
     `
-	err := r.db.Select(&users, query, teamName, excludeUserID)
-	return users, err
+	err := r.db.SelectContext(ctx, &users, query, teamName, excludeUserID)
+	return users, dbError(err)
 }
 
-func (r *UserRepositoryImpl) GetUserByID(userID string) (*models.User, error) {
+func (r *UserRepositoryImpl) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
 	query := `
         SELECT 
@@ -96,9 +97,9 @@ func (r *UserRepositoryImpl) GetUserByID(userID string) (*models.User, error) {
         FROM users 
         WHERE user_id = $1
     `
-	err := r.db.Get(&user, query, userID)
+	err := r.db.GetContext(ctx, &user, query, userID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, fmt.Errorf("user %s: %w", userID, dbError(err))
 	}
 	return &user, nil
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -31,7 +32,7 @@ func TestUserRepository_CreateOrUpdateUser(t *testing.T) {
 		WithArgs("u1", "Alice", "backend", true).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.CreateOrUpdateUser(user)
+	err = repo.CreateOrUpdateUser(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -48,7 +49,7 @@ func TestUserRepository_SetUserActive(t *testing.T) {
 		WithArgs(false, "u1").
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.SetUserActive("u1", false)
+	err = repo.SetUserActive(context.Background(), "u1", false)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -65,9 +66,8 @@ func TestUserRepository_SetUserActive_NotFound(t *testing.T) {
 		WithArgs(false, "nonexistent").
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = repo.SetUserActive("nonexistent", false)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "user not found")
+	err = repo.SetUserActive(context.Background(), "nonexistent", false)
+	assert.ErrorIs(t, err, ErrNotFound)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -86,7 +86,7 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 		WithArgs("u1").
 		WillReturnRows(rows)
 
-	user, err := repo.GetUserByID("u1")
+	user, err := repo.GetUserByID(context.Background(), "u1")
 	require.NoError(t, err)
 	assert.Equal(t, "u1", user.UserID)
 	assert.Equal(t, "Alice", user.Username)
@@ -111,7 +111,7 @@ func TestUserRepository_GetActiveTeamMembers(t *testing.T) {
 		WithArgs("backend", "u1").
 		WillReturnRows(rows)
 
-	users, err := repo.GetActiveTeamMembers("backend", "u1")
+	users, err := repo.GetActiveTeamMembers(context.Background(), "backend", "u1")
 	require.NoError(t, err)
 	assert.Len(t, users, 2)
 	assert.Equal(t, "u2", users[0].UserID)
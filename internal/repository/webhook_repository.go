@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// реализует WebhookRepository интерфейс
+type WebhookRepositoryImpl struct {
+	db dbtx
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+// subscriptionRow строка webhook_subscriptions, события хранятся через запятую
+type subscriptionRow struct {
+	models.WebhookSubscription
+	Events string `db:"events"`
+}
+
+func (row subscriptionRow) toModel() models.WebhookSubscription {
+	sub := row.WebhookSubscription
+	sub.Events = splitList(row.Events)
+	return sub
+}
+
+// splitList разбирает список через запятую: события подписки, области токена
+func splitList(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+	return strings.Split(list, ",")
+}
+
+func (r *WebhookRepositoryImpl) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, events, is_active, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, created_at
+	`
+	var created struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := r.db.GetContext(ctx, &created, query, sub.URL, sub.Secret, strings.Join(sub.Events, ","), sub.IsActive)
+	if err != nil {
+		return dbError(err)
+	}
+
+	sub.ID = created.ID
+	sub.CreatedAt = &created.CreatedAt
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var rows []subscriptionRow
+	query := `
+		SELECT id, url, secret, events, is_active, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, dbError(err)
+	}
+
+	subs := make([]models.WebhookSubscription, len(rows))
+	for i, row := range rows {
+		subs[i] = row.toModel()
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepositoryImpl) GetSubscriptionByID(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	var row subscriptionRow
+	query := `
+		SELECT id, url, secret, events, is_active, created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		return nil, fmt.Errorf("webhook subscription %d: %w", id, dbError(err))
+	}
+
+	sub := row.toModel()
+	return &sub, nil
+}
+
+func (r *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return dbError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: webhook subscription %d", ErrNotFound, id)
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	// повтор события из outbox не создаёт вторую доставку той же подписке
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING id, created_at
+	`
+	var created struct {
+		ID        int64     `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err := r.db.GetContext(ctx, &created, query,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		nullTimeParam(delivery.NextAttemptAt))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: delivery of event %d to subscription %d", ErrConflict, *delivery.EventID, delivery.SubscriptionID)
+	}
+	if err != nil {
+		return dbError(err)
+	}
+
+	delivery.ID = created.ID
+	delivery.CreatedAt = &created.CreatedAt
+	return nil
+}
+
+// ClaimDueDeliveries забирает доставки с наступившей попыткой. Условие повторяется
+// во внешнем UPDATE: конкурирующий процесс, дождавшись блокировки строки,
+// увидит уже отложенный next_attempt_at и строку пропустит
+func (r *WebhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $2
+		)
+		AND status = 'PENDING' AND next_attempt_at <= NOW()
+		RETURNING
+			id,
+			subscription_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempts,
+			response_code,
+			last_error,
+			created_at,
+			delivered_at,
+			next_attempt_at
+	`
+	err := r.db.SelectContext(ctx, &deliveries, query, timeParam(leaseUntil), limit)
+	return deliveries, dbError(err)
+}
+
+// UpdateDelivery сохраняет результат очередной попытки доставки
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			status = $1,
+			attempts = $2,
+			response_code = $3,
+			last_error = $4,
+			delivered_at = $5,
+			next_attempt_at = $6,
+			updated_at = NOW()
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		nullTimeParam(delivery.NextAttemptAt),
+		delivery.ID)
+	return dbError(err)
+}
+
+// GetDeliveries возвращает последние доставки подписки, новые первыми
+func (r *WebhookRepositoryImpl) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	query := `
+		SELECT
+			id,
+			subscription_id,
+			event_id,
+			event_type,
+			payload,
+			status,
+			attempts,
+			response_code,
+			last_error,
+			created_at,
+			delivered_at,
+			next_attempt_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+	err := r.db.SelectContext(ctx, &deliveries, query, subscriptionID, limit)
+	return deliveries, dbError(err)
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository_CreateSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	now := time.Now()
+	mock.ExpectQuery(`INSERT INTO webhook_subscriptions`).
+		WithArgs("https://example.com/hook", "s3cret", "reviewer.assigned,pr.merged", true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, now))
+
+	sub := &models.WebhookSubscription{
+		URL:      "https://example.com/hook",
+		Secret:   "s3cret",
+		Events:   []string{models.EventReviewerAssigned, models.EventPRMerged},
+		IsActive: true,
+	}
+	err = repo.CreateSubscription(context.Background(), sub)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), sub.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	rows := sqlmock.NewRows([]string{"id", "url", "secret", "events", "is_active", "created_at"}).
+		AddRow(1, "https://a.example.com", "s1", "", true, time.Now()).
+		AddRow(2, "https://b.example.com", "s2", "pr.merged", false, time.Now())
+	mock.ExpectQuery(`SELECT id, url, secret, events, is_active, created_at FROM webhook_subscriptions`).
+		WillReturnRows(rows)
+
+	subs, err := repo.GetSubscriptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Empty(t, subs[0].Events)
+	assert.Equal(t, "s1", subs[0].Secret)
+	assert.Equal(t, []string{"pr.merged"}, subs[1].Events)
+	assert.False(t, subs[1].IsActive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_DeleteSubscription_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	mock.ExpectExec(`DELETE FROM webhook_subscriptions`).
+		WithArgs(int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteSubscription(context.Background(), 42)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_UpdateDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	code := 502
+	next := time.Date(2025, 3, 2, 12, 0, 4, 0, time.UTC)
+	delivery := &models.WebhookDelivery{
+		ID:            3,
+		Status:        models.DeliveryStatusPending,
+		Attempts:      2,
+		ResponseCode:  &code,
+		LastError:     "unexpected response status 502",
+		NextAttemptAt: &next,
+	}
+
+	mock.ExpectExec(`UPDATE webhook_deliveries`).
+		WithArgs("PENDING", 2, &code, "unexpected response status 502", nil, timeParam(next), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateDelivery(context.Background(), delivery)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_CreateDelivery_AlreadyQueued(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	eventID := int64(11)
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO webhook_deliveries .* ON CONFLICT \(subscription_id, event_id\) DO NOTHING`).
+		WithArgs(int64(5), &eventID, models.EventPRMerged, `{}`, "PENDING", timeParam(now)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+
+	err = repo.CreateDelivery(context.Background(), &models.WebhookDelivery{
+		SubscriptionID: 5,
+		EventID:        &eventID,
+		EventType:      models.EventPRMerged,
+		Payload:        `{}`,
+		Status:         models.DeliveryStatusPending,
+		NextAttemptAt:  &now,
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_ClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewWebhookRepository(sqlxDB)
+
+	lease := time.Date(2025, 3, 2, 12, 1, 0, 0, time.UTC)
+	columns := []string{"id", "subscription_id", "event_id", "event_type", "payload", "status",
+		"attempts", "response_code", "last_error", "created_at", "delivered_at", "next_attempt_at"}
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at = \$1`).
+		WithArgs(timeParam(lease), 20).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, 5, 11, models.EventPRMerged, `{}`, "PENDING", 1, 502, "unexpected response status 502", lease, nil, lease))
+
+	deliveries, err := repo.ClaimDueDeliveries(context.Background(), 20, lease)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(3), deliveries[0].ID)
+	require.NotNil(t, deliveries[0].EventID)
+	assert.Equal(t, int64(11), *deliveries[0].EventID)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// AccessService решает, может ли автор запроса выполнить операцию над
+// командой, пользователем или PR. Область токена (read, write, admin)
+// проверяется раньше, на маршруте; здесь — роль и отношение к объекту.
+type AccessService struct {
+	userRepo repository.UserRepository
+	prRepo   repository.PRRepository
+	logger   *slog.Logger
+}
+
+func NewAccessService(
+	userRepo repository.UserRepository,
+	prRepo repository.PRRepository,
+	logger *slog.Logger,
+) *AccessService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AccessService{
+		userRepo: userRepo,
+		prRepo:   prRepo,
+		logger:   logger,
+	}
+}
+
+func (s *AccessService) deny(p *models.Principal, action string, keysAndValues ...interface{}) error {
+	role := ""
+	if p != nil {
+		role = p.Role
+	}
+	s.logger.Warn("operation denied",
+		append([]interface{}{"action", action, "subject", subjectOf(p), "role", role}, keysAndValues...)...)
+	return errors.ErrForbidden.WithDetails("action", action).WithDetails("role", role)
+}
+
+func subjectOf(p *models.Principal) string {
+	if p == nil {
+		return ""
+	}
+	return p.Subject
+}
+
+func isOrgAdmin(p *models.Principal) bool {
+	return p != nil && p.Role == models.RoleOrgAdmin
+}
+
+func isSelf(p *models.Principal, userID string) bool {
+	return p != nil && p.UserID != "" && p.UserID == userID
+}
+
+// leadsTeamOf проверяет, что автор запроса — лид команды пользователя userID
+func (s *AccessService) leadsTeamOf(ctx context.Context, p *models.Principal, userID string, notFound *errors.Error) (bool, error) {
+	if p == nil || len(p.LeadOf) == 0 {
+		return false, nil
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("failed to get user for access check", "user_id", userID, "error", err)
+		return false, repoError(err, notFound.WithDetails("user_id", userID), "failed to get user")
+	}
+	return p.Leads(user.TeamName), nil
+}
+
+// CanManageTeam настройки, состав и массовая деактивация: org admin или лид команды
+func (s *AccessService) CanManageTeam(p *models.Principal, teamName, action string) error {
+	if isOrgAdmin(p) || p != nil && p.Leads(teamName) {
+		return nil
+	}
+	return s.deny(p, action, "team_name", teamName)
+}
+
+// CanChangeUser изменение пользователя: он сам, лид его команды или org admin
+func (s *AccessService) CanChangeUser(ctx context.Context, p *models.Principal, userID, action string) error {
+	if isOrgAdmin(p) || isSelf(p, userID) {
+		return nil
+	}
+	ok, err := s.leadsTeamOf(ctx, p, userID, errors.ErrUserNotFound)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.deny(p, action, "user_id", userID)
+	}
+	return nil
+}
+
+// CanCreatePR PR от имени автора: сам автор, лид его команды, бот или org admin
+func (s *AccessService) CanCreatePR(ctx context.Context, p *models.Principal, authorID string) error {
+	if isOrgAdmin(p) || p != nil && p.Role == models.RoleBot || isSelf(p, authorID) {
+		return nil
+	}
+	ok, err := s.leadsTeamOf(ctx, p, authorID, errors.ErrAuthorNotFound)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.deny(p, "create_pr", "author_id", authorID)
+	}
+	return nil
+}
+
+// CanActOnPR мердж, смена статуса и замена ревьюера: автор PR, его активный
+// ревьюер, лид команды автора, бот или org admin
+func (s *AccessService) CanActOnPR(ctx context.Context, p *models.Principal, prID, action string) error {
+	if isOrgAdmin(p) || p != nil && p.Role == models.RoleBot {
+		return nil
+	}
+
+	pr, err := s.prRepo.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("failed to get PR for access check", "pr_id", prID, "error", err)
+		return repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+	}
+	if isSelf(p, pr.AuthorID) || p != nil && p.UserID != "" && slices.Contains(pr.AssignedReviewers, p.UserID) {
+		return nil
+	}
+
+	ok, err := s.leadsTeamOf(ctx, p, pr.AuthorID, errors.ErrAuthorNotFound)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.deny(p, action, "pull_request_id", prID)
+	}
+	return nil
+}
+
+// CanSubmitReview вердикт ставит сам ревьюер; бот и org admin — от его имени
+func (s *AccessService) CanSubmitReview(p *models.Principal, reviewerID string) error {
+	if isOrgAdmin(p) || p != nil && p.Role == models.RoleBot || isSelf(p, reviewerID) {
+		return nil
+	}
+	return s.deny(p, "submit_review", "reviewer_id", reviewerID)
+}
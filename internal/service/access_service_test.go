@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessFixture(t *testing.T) *AccessService {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	prs := memory.NewPRRepository(store)
+
+	for _, u := range []models.User{
+		{UserID: "lead", TeamName: "backend", IsActive: true},
+		{UserID: "author", TeamName: "backend", IsActive: true},
+		{UserID: "reviewer", TeamName: "backend", IsActive: true},
+		{UserID: "outsider", TeamName: "frontend", IsActive: true},
+	} {
+		require.NoError(t, users.CreateOrUpdateUser(ctx, &u))
+	}
+	require.NoError(t, prs.CreatePR(ctx, &models.PullRequest{
+		PullRequestID: "pr-1", PullRequestName: "Fix", AuthorID: "author", Status: models.PRStatusOpen,
+	}))
+	require.NoError(t, prs.AddPRReviewers(ctx, "pr-1", []string{"reviewer"}))
+
+	return NewAccessService(users, prs, nil)
+}
+
+func TestAccessService_Decisions(t *testing.T) {
+	ctx := context.Background()
+	srv := newAccessFixture(t)
+
+	admin := &models.Principal{Role: models.RoleOrgAdmin}
+	bot := &models.Principal{Role: models.RoleBot}
+	lead := &models.Principal{Role: models.RoleTeamLead, UserID: "lead", LeadOf: []string{"backend"}}
+	author := &models.Principal{Role: models.RoleMember, UserID: "author"}
+	reviewer := &models.Principal{Role: models.RoleMember, UserID: "reviewer"}
+	outsider := &models.Principal{Role: models.RoleMember, UserID: "outsider"}
+
+	tests := []struct {
+		name  string
+		check func() error
+		allow bool
+	}{
+		{"admin deactivates any team", func() error { return srv.CanManageTeam(admin, "frontend", "deactivate_users") }, true},
+		{"lead deactivates own team", func() error { return srv.CanManageTeam(lead, "backend", "deactivate_users") }, true},
+		{"lead cannot touch other team", func() error { return srv.CanManageTeam(lead, "frontend", "deactivate_users") }, false},
+		{"bot cannot manage team", func() error { return srv.CanManageTeam(bot, "backend", "deactivate_users") }, false},
+
+		{"member changes self", func() error { return srv.CanChangeUser(ctx, author, "author", "set_user_active") }, true},
+		{"member cannot change others", func() error { return srv.CanChangeUser(ctx, author, "reviewer", "set_user_active") }, false},
+		{"lead changes team member", func() error { return srv.CanChangeUser(ctx, lead, "author", "set_user_active") }, true},
+		{"lead cannot change outsider", func() error { return srv.CanChangeUser(ctx, lead, "outsider", "set_user_active") }, false},
+		{"bot cannot change users", func() error { return srv.CanChangeUser(ctx, bot, "author", "set_user_active") }, false},
+
+		{"author reassigns", func() error { return srv.CanActOnPR(ctx, author, "pr-1", "reassign_reviewer") }, true},
+		{"reviewer reassigns", func() error { return srv.CanActOnPR(ctx, reviewer, "pr-1", "reassign_reviewer") }, true},
+		{"lead of author's team reassigns", func() error { return srv.CanActOnPR(ctx, lead, "pr-1", "reassign_reviewer") }, true},
+		{"outsider cannot reassign", func() error { return srv.CanActOnPR(ctx, outsider, "pr-1", "reassign_reviewer") }, false},
+		{"bot reassigns", func() error { return srv.CanActOnPR(ctx, bot, "pr-1", "reassign_reviewer") }, true},
+
+		{"member creates own PR", func() error { return srv.CanCreatePR(ctx, author, "author") }, true},
+		{"member cannot create PR for others", func() error { return srv.CanCreatePR(ctx, outsider, "author") }, false},
+		{"reviewer submits own verdict", func() error { return srv.CanSubmitReview(reviewer, "reviewer") }, true},
+		{"lead cannot vote for reviewer", func() error { return srv.CanSubmitReview(lead, "reviewer") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check()
+			if tt.allow {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, errors.ErrForbidden), "got %v", err)
+			}
+		})
+	}
+}
+
+func TestAccessService_MissingObject(t *testing.T) {
+	srv := newAccessFixture(t)
+	outsider := &models.Principal{Role: models.RoleMember, UserID: "outsider"}
+
+	err := srv.CanActOnPR(context.Background(), outsider, "pr-404", "merge_pr")
+	assert.True(t, errors.Is(err, errors.ErrPRNotFound))
+}
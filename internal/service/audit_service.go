@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// actorSystem автор изменений, сделанных без запроса: сиды, фоновые задачи
+const actorSystem = "system"
+
+type actorKey struct{}
+
+// WithActor кладёт в контекст автора изменения для журнала аудита
+func WithActor(ctx context.Context, actor models.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) models.Actor {
+	if actor, ok := ctx.Value(actorKey{}).(models.Actor); ok && actor.Subject != "" {
+		return actor
+	}
+	return models.Actor{Subject: actorSystem}
+}
+
+// writeAudit пишет запись журнала через репозиторий транзакции операции:
+// если запись не удалась, откатывается и само изменение
+func writeAudit(ctx context.Context, repo repository.AuditRepository, operation, entityType, entityID string, before, after interface{}) error {
+	actor := actorFrom(ctx)
+	entry := &models.AuditEntry{
+		Actor:       actor.Subject,
+		ActorName:   actor.Name,
+		ActorMethod: actor.Method,
+		Operation:   operation,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		IP:          actor.IP,
+	}
+
+	var err error
+	if entry.Before, err = auditState(before); err != nil {
+		return fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	if entry.After, err = auditState(after); err != nil {
+		return fmt.Errorf("failed to encode audit state: %w", err)
+	}
+
+	if err := repo.AppendAudit(ctx, entry); err != nil {
+		return repoError(err, nil, "failed to write audit log")
+	}
+	return nil
+}
+
+// auditState состояние сущности в JSON, nil — состояния нет
+func auditState(state interface{}) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil || string(raw) == "null" {
+		return nil, err
+	}
+	return raw, nil
+}
+
+type AuditService struct {
+	auditRepo repository.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, logger *slog.Logger) *AuditService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// ListAudit возвращает страницу журнала аудита и курсор следующей страницы
+func (s *AuditService) ListAudit(ctx context.Context, filter repository.AuditFilter, page repository.Page) ([]models.AuditEntry, string, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", errors.NewError("INVALID_REQUEST", "from must be before to")
+	}
+
+	entries, next, err := s.auditRepo.ListAudit(ctx, filter, page)
+	if err != nil {
+		s.logger.Error("failed to list audit log", "filter", filter, "error", err)
+		return nil, "", repoError(err, nil, "failed to list audit log")
+	}
+
+	s.logger.Debug("listed audit log", "count", len(entries), "has_more", next != "")
+	return entries, next, nil
+}
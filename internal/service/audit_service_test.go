@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_RecordedWithChange(t *testing.T) {
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	tx := memory.NewTxManager(store)
+	userService := NewUserService(users, memory.NewTeamRepository(store), memory.NewPRRepository(store), nil, tx, nil)
+	auditService := NewAuditService(memory.NewAuditRepository(store), nil)
+
+	require.NoError(t, users.CreateOrUpdateUser(context.Background(), &models.User{UserID: "bob", TeamName: "backend", IsActive: true}))
+
+	ctx := WithActor(context.Background(), models.Actor{
+		Subject: "sso-alice", Name: "alice", Method: models.AuthMethodJWT, IP: "10.0.0.7",
+	})
+	_, err := userService.SetUserActive(ctx, "bob", false)
+	require.NoError(t, err)
+
+	// неудачная операция не оставляет записи
+	_, err = userService.SetUserActive(ctx, "ghost", false)
+	assert.True(t, errors.Is(err, errors.ErrUserNotFound))
+
+	entries, next, err := auditService.ListAudit(context.Background(), repository.AuditFilter{}, repository.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, next)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "sso-alice", entry.Actor)
+	assert.Equal(t, "alice", entry.ActorName)
+	assert.Equal(t, "10.0.0.7", entry.IP)
+	assert.Equal(t, models.AuditUserActiveSet, entry.Operation)
+	assert.Equal(t, "bob", entry.EntityID)
+	assert.Contains(t, string(entry.Before), `"is_active":true`)
+	assert.Contains(t, string(entry.After), `"is_active":false`)
+}
+
+func TestAudit_SystemActorAndFilters(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewAuditRepository(store)
+	auditService := NewAuditService(repo, nil)
+	ctx := context.Background()
+
+	for _, prID := range []string{"pr-1", "pr-2", "pr-1"} {
+		err := writeAudit(ctx, repo, models.AuditPRMerged, models.AuditEntityPR, prID, nil, map[string]string{"id": prID})
+		require.NoError(t, err)
+	}
+
+	entries, next, err := auditService.ListAudit(ctx,
+		repository.AuditFilter{EntityType: models.AuditEntityPR, EntityID: "pr-1"}, repository.Page{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(3), entries[0].ID, "сначала новые")
+	assert.Equal(t, actorSystem, entries[0].Actor, "без запроса автор — system")
+	assert.Nil(t, entries[0].Before)
+
+	entries, next, err = auditService.ListAudit(ctx,
+		repository.AuditFilter{EntityType: models.AuditEntityPR, EntityID: "pr-1"}, repository.Page{Limit: 1, Cursor: next})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(1), entries[0].ID)
+	assert.Empty(t, next)
+}
+
+func TestAudit_CoversLifecycleTeamAndTokens(t *testing.T) {
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	teams := memory.NewTeamRepository(store)
+	prs := memory.NewPRRepository(store)
+	tx := memory.NewTxManager(store)
+	prService := NewPRService(prs, users, teams, NewReviewService(users, prs, teams, nil), tx, nil)
+	teamService := NewTeamService(teams, users, tx, nil)
+	authService := NewAuthService(memory.NewTokenRepository(store), teams, tx, nil, nil)
+	auditService := NewAuditService(memory.NewAuditRepository(store), nil)
+	ctx := context.Background()
+
+	require.NoError(t, teams.CreateTeam(ctx, "backend"))
+	for _, id := range []string{"author", "r1"} {
+		require.NoError(t, users.CreateOrUpdateUser(ctx, &models.User{UserID: id, TeamName: "backend", IsActive: true}))
+	}
+	_, err := prService.CreatePR(ctx, &models.PullRequest{
+		PullRequestID: "pr-1", PullRequestName: "Draft", AuthorID: "author", Status: models.PRStatusDraft,
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = prService.MarkReady(ctx, "pr-1")
+	require.NoError(t, err)
+	_, err = prService.SubmitReview(ctx, "pr-1", "r1", models.ReviewStateApproved)
+	require.NoError(t, err)
+	_, err = prService.SetStatus(ctx, "pr-1", models.PRStatusClosed, false)
+	require.NoError(t, err)
+	_, err = prService.ReopenPR(ctx, "pr-1")
+	require.NoError(t, err)
+
+	approvals := 1
+	_, err = teamService.UpdateTeamSettings(ctx, "backend", TeamSettingsUpdate{RequiredApprovals: &approvals})
+	require.NoError(t, err)
+	require.NoError(t, teamService.AddLead(ctx, "backend", "r1"))
+	require.NoError(t, teamService.RemoveLead(ctx, "backend", "r1"))
+	require.NoError(t, teams.CreateTeam(ctx, "empty"))
+	require.NoError(t, teamService.DeleteTeam(ctx, "empty"))
+
+	_, token, err := authService.CreateToken(ctx, "ci", []string{models.ScopeWrite})
+	require.NoError(t, err)
+	require.NoError(t, authService.RevokeToken(ctx, token.ID))
+
+	// отклонённая операция не оставляет записи
+	_, err = prService.SubmitReview(ctx, "pr-1", "author", models.ReviewStateApproved)
+	assert.True(t, errors.Is(err, errors.ErrNotAssigned))
+
+	entries, _, err := auditService.ListAudit(ctx, repository.AuditFilter{}, repository.Page{Limit: 50})
+	require.NoError(t, err)
+	operations := make([]string, len(entries))
+	for i, entry := range entries {
+		operations[i] = entry.Operation
+	}
+	assert.Equal(t, []string{
+		models.AuditTokenRevoked,
+		models.AuditTokenCreated,
+		models.AuditTeamDeleted,
+		models.AuditTeamLeadRemoved,
+		models.AuditTeamLeadAdded,
+		models.AuditTeamSettings,
+		models.AuditPRReopened,
+		models.AuditPRClosed,
+		models.AuditPRReviewed,
+		models.AuditPRReady,
+		models.AuditPRCreated,
+	}, operations)
+
+	settings := entries[5]
+	assert.Contains(t, string(settings.Before), `"required_approvals":0`)
+	assert.Contains(t, string(settings.After), `"required_approvals":1`)
+	closed := entries[7]
+	assert.Contains(t, string(closed.Before), `"status":"OPEN"`)
+	assert.Contains(t, string(closed.After), `"status":"CLOSED"`)
+	assert.NotContains(t, string(entries[1].After), "hash")
+}
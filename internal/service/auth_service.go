@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/jwt"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// tokenPrefix начало каждого выданного токена, чтобы его узнавали сканеры секретов
+const tokenPrefix = "ra_"
+
+// длина видимой части токена в списке токенов
+const tokenDisplayLength = 10
+
+// SSOConfig приём JWT внутреннего SSO наравне с API-токенами
+type SSOConfig struct {
+	Verifier *jwt.Verifier
+	// GroupScopes область доступа участников группы из токена
+	GroupScopes map[string]string
+	// DefaultScope область любого действительного токена, пусто — только по группам
+	DefaultScope string
+}
+
+// AuthService выдаёт, проверяет и отзывает API-токены и проверяет JWT
+type AuthService struct {
+	tokenRepo repository.TokenRepository
+	teamRepo  repository.TeamRepository
+	tx        repository.TxManager
+	// sso nil, если JWT не настроены
+	sso    *SSOConfig
+	logger *slog.Logger
+}
+
+func NewAuthService(
+	tokenRepo repository.TokenRepository,
+	teamRepo repository.TeamRepository,
+	tx repository.TxManager,
+	sso *SSOConfig,
+	logger *slog.Logger,
+) *AuthService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &AuthService{
+		tokenRepo: tokenRepo,
+		teamRepo:  teamRepo,
+		tx:        tx,
+		sso:       sso,
+		logger:    logger,
+	}
+}
+
+// hashToken sha256 токена: токены случайные и длинные, соль не нужна
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func displayPrefix(token string) string {
+	if len(token) <= tokenDisplayLength {
+		return token
+	}
+	return token[:tokenDisplayLength]
+}
+
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.NewError(errors.ErrInvalidScope.Code, "At least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			return errors.ErrInvalidScope.WithDetails("scope", scope)
+		}
+	}
+	return nil
+}
+
+// CreateToken выдаёт новый токен. Открытое значение возвращается только здесь,
+// в хранилище остаётся хэш.
+func (s *AuthService) CreateToken(ctx context.Context, name string, scopes []string) (string, *models.APIToken, error) {
+	s.logger.Info("creating api token", "name", name, "scopes", scopes)
+
+	if err := validateScopes(scopes); err != nil {
+		return "", nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate api token: %w", err)
+	}
+	secret := tokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	// токен и запись аудита сохраняются вместе
+	var token *models.APIToken
+	err := s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		var err error
+		token, err = s.saveToken(ctx, repos.Tokens, name, secret, scopes)
+		if err != nil {
+			return err
+		}
+		return writeAudit(ctx, repos.Audit, models.AuditTokenCreated, models.AuditEntityToken,
+			strconv.FormatInt(token.ID, 10), nil, token)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, token, nil
+}
+
+// EnsureToken регистрирует заранее известный токен, если его ещё нет. Так
+// ADMIN_TOKEN из окружения становится первым админским токеном.
+func (s *AuthService) EnsureToken(ctx context.Context, name, secret string, scopes []string) error {
+	if err := validateScopes(scopes); err != nil {
+		return err
+	}
+
+	existing, err := s.tokenRepo.GetTokenByHash(ctx, hashToken(secret))
+	switch {
+	case err == nil:
+		if existing.RevokedAt != nil {
+			s.logger.Warn("bootstrap api token is revoked", "name", existing.Name, "id", existing.ID)
+		}
+		return nil
+	case !stderrors.Is(err, repository.ErrNotFound):
+		s.logger.Error("failed to look up bootstrap api token", "error", err)
+		return repoError(err, nil, "failed to look up api token")
+	}
+
+	_, err = s.saveToken(ctx, s.tokenRepo, name, secret, scopes)
+	return err
+}
+
+func (s *AuthService) saveToken(ctx context.Context, tokens repository.TokenRepository, name, secret string, scopes []string) (*models.APIToken, error) {
+	token := &models.APIToken{
+		Name:      name,
+		Prefix:    displayPrefix(secret),
+		Scopes:    scopes,
+		TokenHash: hashToken(secret),
+	}
+	if err := tokens.CreateToken(ctx, token); err != nil {
+		s.logger.Error("failed to save api token", "name", name, "error", err)
+		return nil, repoError(err, nil, "failed to save api token")
+	}
+
+	s.logger.Info("api token created", "id", token.ID, "name", name, "prefix", token.Prefix)
+	return token, nil
+}
+
+// Authenticate определяет, кто делает запрос: JWT проверяется по JWKS,
+// остальное ищется среди API-токенов
+func (s *AuthService) Authenticate(ctx context.Context, secret string) (*models.Principal, error) {
+	if s.sso != nil && jwt.LooksLikeJWT(secret) {
+		return s.authenticateJWT(ctx, secret)
+	}
+
+	token, err := s.tokenRepo.GetTokenByHash(ctx, hashToken(secret))
+	if err != nil {
+		if !stderrors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("failed to look up api token", "error", err)
+		}
+		return nil, repoError(err, errors.ErrUnauthorized, "failed to look up api token")
+	}
+	if token.RevokedAt != nil {
+		s.logger.Warn("revoked api token used", "id", token.ID, "prefix", token.Prefix)
+		return nil, errors.ErrUnauthorized
+	}
+
+	role := models.RoleBot
+	if token.HasScope(models.ScopeAdmin) {
+		role = models.RoleOrgAdmin
+	}
+	return &models.Principal{
+		Subject: fmt.Sprintf("token:%d", token.ID),
+		Name:    token.Name,
+		Method:  models.AuthMethodAPIToken,
+		Role:    role,
+		Scopes:  token.Scopes,
+		TokenID: token.ID,
+	}, nil
+}
+
+// authenticateJWT sub становится пользователем запроса, группы — областями доступа
+func (s *AuthService) authenticateJWT(ctx context.Context, raw string) (*models.Principal, error) {
+	claims, err := s.sso.Verifier.Verify(ctx, raw)
+	switch {
+	case stderrors.Is(err, jwt.ErrExpired):
+		s.logger.Info("expired jwt rejected", "error", err)
+		return nil, errors.WrapError(errors.ErrTokenExpired, err)
+	case stderrors.Is(err, jwt.ErrKeysUnavailable):
+		s.logger.Error("failed to load jwks", "error", err)
+		return nil, errors.WrapError(errors.ErrServiceUnavailable, err)
+	case err != nil:
+		s.logger.Warn("invalid jwt rejected", "error", err)
+		return nil, errors.WrapError(errors.ErrUnauthorized, err)
+	}
+
+	var scopes []string
+	if s.sso.DefaultScope != "" {
+		scopes = append(scopes, s.sso.DefaultScope)
+	}
+	for _, group := range claims.Groups {
+		if scope, ok := s.sso.GroupScopes[group]; ok && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Subject
+	}
+	expiresAt := claims.ExpiresAt
+	principal := &models.Principal{
+		Subject:   claims.Subject,
+		Name:      name,
+		Method:    models.AuthMethodJWT,
+		Role:      models.RoleMember,
+		UserID:    claims.Subject,
+		Groups:    claims.Groups,
+		Scopes:    scopes,
+		ExpiresAt: &expiresAt,
+	}
+	if principal.HasScope(models.ScopeAdmin) {
+		principal.Role = models.RoleOrgAdmin
+		return principal, nil
+	}
+
+	leadOf, err := s.teamRepo.GetLedTeams(ctx, claims.Subject)
+	if err != nil {
+		s.logger.Error("failed to get led teams", "user_id", claims.Subject, "error", err)
+		return nil, repoError(err, nil, "failed to get led teams")
+	}
+	if len(leadOf) > 0 {
+		principal.Role = models.RoleTeamLead
+		principal.LeadOf = leadOf
+	}
+	return principal, nil
+}
+
+func (s *AuthService) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	tokens, err := s.tokenRepo.ListTokens(ctx)
+	if err != nil {
+		s.logger.Error("failed to list api tokens", "error", err)
+		return nil, repoError(err, nil, "failed to list api tokens")
+	}
+	return tokens, nil
+}
+
+// tokenRevocation состояние отзываемого токена в журнале аудита
+type tokenRevocation struct {
+	Revoked bool `json:"revoked"`
+}
+
+// RevokeToken отзывает токен, запросы с ним сразу получают 401
+func (s *AuthService) RevokeToken(ctx context.Context, id int64) error {
+	s.logger.Info("revoking api token", "id", id)
+
+	return s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Tokens.RevokeToken(ctx, id); err != nil {
+			s.logger.Error("failed to revoke api token", "id", id, "error", err)
+			return repoError(err, errors.ErrTokenNotFound.WithDetails("token_id", id), "failed to revoke api token")
+		}
+		return writeAudit(ctx, repos.Audit, models.AuditTokenRevoked, models.AuditEntityToken,
+			strconv.FormatInt(id, 10), tokenRevocation{Revoked: false}, tokenRevocation{Revoked: true})
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/jwt"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthService(sso *SSOConfig, logger *slog.Logger) *AuthService {
+	store := memory.NewStore()
+	return NewAuthService(memory.NewTokenRepository(store), memory.NewTeamRepository(store), memory.NewTxManager(store), sso, logger)
+}
+
+func TestAuthService_CreateAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestAuthService(nil, nil)
+
+	secret, token, err := srv.CreateToken(ctx, "ci", []string{models.ScopeWrite})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, tokenPrefix))
+	assert.True(t, strings.HasPrefix(secret, token.Prefix))
+	assert.NotContains(t, token.TokenHash, secret, "хранится только хэш")
+
+	authed, err := srv.Authenticate(ctx, secret)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, authed.TokenID)
+	assert.True(t, authed.HasScope(models.ScopeRead), "write включает read")
+	assert.False(t, authed.HasScope(models.ScopeAdmin))
+
+	_, err = srv.Authenticate(ctx, secret+"x")
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized))
+
+	require.NoError(t, srv.RevokeToken(ctx, token.ID))
+	_, err = srv.Authenticate(ctx, secret)
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized), "отозванный токен")
+
+	err = srv.RevokeToken(ctx, token.ID)
+	assert.True(t, errors.Is(err, errors.ErrTokenNotFound), "повторный отзыв")
+}
+
+func TestAuthService_Scopes(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestAuthService(nil, nil)
+
+	_, _, err := srv.CreateToken(ctx, "ci", nil)
+	assert.True(t, errors.Is(err, errors.ErrInvalidScope))
+
+	_, _, err = srv.CreateToken(ctx, "ci", []string{models.ScopeRead, "root"})
+	assert.True(t, errors.Is(err, errors.ErrInvalidScope))
+}
+
+func TestAuthService_EnsureToken(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestAuthService(nil, nil)
+
+	require.NoError(t, srv.EnsureToken(ctx, "bootstrap-admin", "secret-admin", []string{models.ScopeAdmin}))
+	require.NoError(t, srv.EnsureToken(ctx, "bootstrap-admin", "secret-admin", []string{models.ScopeAdmin}))
+
+	tokens, err := srv.ListTokens(ctx)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1, "повторный старт не дублирует токен")
+
+	token, err := srv.Authenticate(ctx, "secret-admin")
+	require.NoError(t, err)
+	assert.True(t, token.HasScope(models.ScopeWrite))
+}
+
+func TestAuthService_JWT(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwk, err := jwt.NewJWK("sso-1", &key.PublicKey)
+	require.NoError(t, err)
+	data, err := json.Marshal(jwt.JWKS{Keys: []jwt.JWK{jwk}})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	verifier, err := jwt.NewVerifier(ctx, jwt.Config{JWKS: path})
+	require.NoError(t, err)
+	srv := newTestAuthService(&SSOConfig{
+		Verifier:    verifier,
+		GroupScopes: map[string]string{"leads": models.ScopeWrite},
+	}, nil)
+
+	sign := func(groups []string, exp time.Time) string {
+		token, err := jwt.Sign(map[string]interface{}{
+			"sub":    "u1",
+			"groups": groups,
+			"exp":    exp.Unix(),
+		}, "sso-1", key)
+		require.NoError(t, err)
+		return token
+	}
+
+	principal, err := srv.Authenticate(ctx, sign([]string{"leads", "backend"}, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, "u1", principal.UserID)
+	assert.Equal(t, models.AuthMethodJWT, principal.Method)
+	assert.Equal(t, []string{models.ScopeWrite}, principal.Scopes)
+
+	principal, err = srv.Authenticate(ctx, sign([]string{"backend"}, time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	assert.Empty(t, principal.Scopes, "без DefaultScope группы без сопоставления прав не дают")
+
+	_, err = srv.Authenticate(ctx, sign([]string{"leads"}, time.Now().Add(-time.Hour)))
+	assert.True(t, errors.Is(err, errors.ErrTokenExpired))
+
+	_, err = srv.Authenticate(ctx, "a.b.c")
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized))
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/repository"
+)
+
+// repoError переводит ошибку репозитория в доменную. notFound возвращается,
+// только если записи действительно нет, недоступность базы — ErrServiceUnavailable,
+// негодный курсор страницы — ErrInvalidCursor,
+// остальные ошибки оборачиваются сообщением msg и отдаются клиенту как внутренние.
+func repoError(err error, notFound *errors.Error, msg string) error {
+	switch {
+	case notFound != nil && stderrors.Is(err, repository.ErrNotFound):
+		return errors.WrapError(notFound, err)
+	case stderrors.Is(err, repository.ErrUnavailable):
+		return errors.WrapError(errors.ErrServiceUnavailable, err)
+	case stderrors.Is(err, repository.ErrInvalidCursor):
+		return errors.WrapError(errors.ErrInvalidCursor, err)
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
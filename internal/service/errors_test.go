@@ -0,0 +1,31 @@
+package service
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoError(t *testing.T) {
+	notFound := fmt.Errorf("%w: PR pr-1", repository.ErrNotFound)
+	err := repoError(notFound, errors.ErrPRNotFound, "failed to get PR")
+	assert.True(t, errors.Is(err, errors.ErrPRNotFound))
+
+	// отказ базы — не «PR не найден»
+	unavailable := fmt.Errorf("%w: connection refused", repository.ErrUnavailable)
+	err = repoError(unavailable, errors.ErrPRNotFound, "failed to get PR")
+	assert.True(t, errors.Is(err, errors.ErrServiceUnavailable))
+
+	// без notFound отсутствие записи считается внутренней ошибкой
+	err = repoError(notFound, nil, "failed to merge PR")
+	var domainErr *errors.Error
+	require.False(t, stderrors.As(err, &domainErr))
+	assert.EqualError(t, err, "failed to merge PR: not found: PR pr-1")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// DefaultIdempotencyLease сколько запрос держит ключ идемпотентности.
+// Ключ запроса, не завершившегося за это время, занимает повтор.
+const DefaultIdempotencyLease = time.Minute
+
+// IdempotencyService запоминает первый ответ на запрос с ключом идемпотентности,
+// чтобы повтор того же запроса получил этот ответ, а не выполнился ещё раз
+type IdempotencyService struct {
+	repo   repository.IdempotencyRepository
+	ttl    time.Duration
+	lease  time.Duration
+	logger *slog.Logger
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration, logger *slog.Logger) *IdempotencyService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &IdempotencyService{
+		repo:   repo,
+		ttl:    ttl,
+		lease:  DefaultIdempotencyLease,
+		logger: logger,
+	}
+}
+
+// SetLease задаёт, сколько запрос держит ключ до того, как его займёт повтор
+func (s *IdempotencyService) SetLease(lease time.Duration) {
+	if lease > 0 {
+		s.lease = lease
+	}
+}
+
+// Begin занимает ключ под запрос. Если запрос с этим ключом уже выполнен,
+// возвращает сохранённый ответ; nil означает, что запрос нужно выполнить и
+// затем вызвать Complete или Release. Тот же ключ с другим запросом —
+// ErrIdempotencyKeyReused, ещё не завершённый запрос — ErrIdempotencyInFlight.
+// Ключ запроса, который держит его дольше lease, занимает повтор с тем же телом.
+func (s *IdempotencyService) Begin(ctx context.Context, key, scope, requestHash string) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+	lockedUntil := now.Add(s.lease)
+	reservation := &models.IdempotencyRecord{
+		Key:         key,
+		Scope:       scope,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(s.ttl),
+		LockedUntil: &lockedUntil,
+	}
+
+	err := s.repo.ReserveKey(ctx, reservation)
+	if err == nil {
+		return nil, nil
+	}
+	if !stderrors.Is(err, repository.ErrConflict) {
+		s.logger.Error("failed to reserve idempotency key", "key", key, "scope", scope, "error", err)
+		return nil, repoError(err, nil, "failed to reserve idempotency key")
+	}
+
+	rec, err := s.repo.GetKey(ctx, key, scope)
+	if err != nil {
+		// ключ истёк и удалён между двумя запросами, клиенту достаточно повторить
+		s.logger.Error("failed to get idempotency key", "key", key, "scope", scope, "error", err)
+		return nil, repoError(err, errors.ErrIdempotencyInFlight.WithDetails("idempotency_key", key), "failed to get idempotency key")
+	}
+
+	switch {
+	case rec.RequestHash != requestHash:
+		s.logger.Warn("idempotency key reused with different request", "key", key, "scope", scope)
+		return nil, errors.ErrIdempotencyKeyReused.WithDetails("idempotency_key", key)
+	case rec.StatusCode == 0:
+		return nil, s.takeOver(ctx, rec, reservation)
+	}
+
+	s.logger.Info("replaying idempotent response",
+		"key", key, "scope", scope, "status_code", rec.StatusCode)
+	return rec, nil
+}
+
+// takeOver занимает ключ незавершённого запроса, если тот не уложился в lease:
+// такой запрос считается упавшим. nil — запрос нужно выполнить заново.
+func (s *IdempotencyService) takeOver(ctx context.Context, rec, reservation *models.IdempotencyRecord) error {
+	inFlight := errors.ErrIdempotencyInFlight.WithDetails("idempotency_key", rec.Key)
+	if rec.LockedUntil != nil && rec.LockedUntil.After(time.Now()) {
+		s.logger.Warn("idempotent request still in progress", "key", rec.Key, "scope", rec.Scope)
+		return inFlight
+	}
+
+	err := s.repo.TakeOverKey(ctx, reservation)
+	switch {
+	case err == nil:
+		s.logger.Warn("taking over idempotency key of stalled request",
+			"key", rec.Key, "scope", rec.Scope, "locked_until", rec.LockedUntil)
+		return nil
+	case stderrors.Is(err, repository.ErrConflict):
+		// ключ занял другой повтор или первый запрос успел завершиться
+		s.logger.Warn("idempotency key taken over concurrently", "key", rec.Key, "scope", rec.Scope)
+		return inFlight
+	default:
+		s.logger.Error("failed to take over idempotency key", "key", rec.Key, "scope", rec.Scope, "error", err)
+		return repoError(err, nil, "failed to take over idempotency key")
+	}
+}
+
+// Complete сохраняет ответ на запрос, занятый через Begin
+func (s *IdempotencyService) Complete(ctx context.Context, key, scope string, statusCode int, contentType string, body []byte) error {
+	if err := s.repo.CompleteKey(ctx, key, scope, statusCode, contentType, string(body)); err != nil {
+		s.logger.Error("failed to save idempotent response", "key", key, "scope", scope, "error", err)
+		return repoError(err, nil, "failed to save idempotent response")
+	}
+	return nil
+}
+
+// Release освобождает ключ без сохранения ответа: повтор выполнит запрос заново.
+// Нужен, когда запрос не удался по временной причине.
+func (s *IdempotencyService) Release(ctx context.Context, key, scope string) error {
+	if err := s.repo.DeleteKey(ctx, key, scope); err != nil {
+		s.logger.Error("failed to release idempotency key", "key", key, "scope", scope, "error", err)
+		return repoError(err, nil, "failed to release idempotency key")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService_Replay(t *testing.T) {
+	ctx := context.Background()
+	srv := NewIdempotencyService(memory.NewIdempotencyRepository(memory.NewStore()), time.Hour, nil)
+
+	saved, err := srv.Begin(ctx, "key-1", "POST /pullRequest/create", "hash-a")
+	require.NoError(t, err)
+	assert.Nil(t, saved, "первый запрос выполняется")
+
+	_, err = srv.Begin(ctx, "key-1", "POST /pullRequest/create", "hash-a")
+	assert.True(t, errors.Is(err, errors.ErrIdempotencyInFlight), "ответа ещё нет")
+
+	require.NoError(t, srv.Complete(ctx, "key-1", "POST /pullRequest/create", 201, "application/json", []byte(`{"pr":{}}`)))
+
+	saved, err = srv.Begin(ctx, "key-1", "POST /pullRequest/create", "hash-a")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, 201, saved.StatusCode)
+	assert.Equal(t, `{"pr":{}}`, saved.ResponseBody)
+
+	_, err = srv.Begin(ctx, "key-1", "POST /pullRequest/create", "hash-b")
+	assert.True(t, errors.Is(err, errors.ErrIdempotencyKeyReused), "другое тело с тем же ключом")
+
+	saved, err = srv.Begin(ctx, "key-1", "POST /pullRequest/reassign", "hash-b")
+	require.NoError(t, err)
+	assert.Nil(t, saved, "ключ действует в пределах маршрута")
+}
+
+func TestIdempotencyService_ReleaseAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewIdempotencyRepository(memory.NewStore())
+
+	srv := NewIdempotencyService(repo, time.Hour, nil)
+	_, err := srv.Begin(ctx, "key-1", "scope", "hash")
+	require.NoError(t, err)
+	require.NoError(t, srv.Release(ctx, "key-1", "scope"))
+
+	saved, err := srv.Begin(ctx, "key-1", "scope", "other-hash")
+	require.NoError(t, err)
+	assert.Nil(t, saved, "освобождённый ключ занимается заново")
+
+	expiring := NewIdempotencyService(repo, -time.Second, nil)
+	_, err = expiring.Begin(ctx, "key-2", "scope", "hash")
+	require.NoError(t, err)
+	require.NoError(t, expiring.Complete(ctx, "key-2", "scope", 200, "application/json", []byte(`{}`)))
+
+	saved, err = srv.Begin(ctx, "key-2", "scope", "other-hash")
+	require.NoError(t, err)
+	assert.Nil(t, saved, "истёкший ключ занимается заново")
+}
+
+func TestIdempotencyService_TakeOverStalledRequest(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewIdempotencyRepository(memory.NewStore())
+	srv := NewIdempotencyService(repo, time.Hour, nil)
+	srv.SetLease(time.Hour)
+
+	_, err := srv.Begin(ctx, "key-1", "scope", "hash")
+	require.NoError(t, err)
+	_, err = srv.Begin(ctx, "key-1", "scope", "hash")
+	assert.True(t, errors.Is(err, errors.ErrIdempotencyInFlight), "запрос ещё держит ключ")
+
+	// первый запрос упал, не освободив ключ: после lease ключ занимает повтор
+	crashed := NewIdempotencyService(repo, time.Hour, nil)
+	crashed.SetLease(time.Nanosecond)
+	_, err = crashed.Begin(ctx, "key-2", "scope", "hash")
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	_, err = srv.Begin(ctx, "key-2", "scope", "other-hash")
+	assert.True(t, errors.Is(err, errors.ErrIdempotencyKeyReused), "чужое тело ключ не занимает")
+
+	saved, err := srv.Begin(ctx, "key-2", "scope", "hash")
+	require.NoError(t, err)
+	assert.Nil(t, saved, "повтор выполняет запрос заново")
+
+	_, err = srv.Begin(ctx, "key-2", "scope", "hash")
+	assert.True(t, errors.Is(err, errors.ErrIdempotencyInFlight), "повтор держит ключ свой lease")
+
+	require.NoError(t, srv.Complete(ctx, "key-2", "scope", 201, "application/json", []byte(`{}`)))
+	saved, err = srv.Begin(ctx, "key-2", "scope", "hash")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, 201, saved.StatusCode)
+}
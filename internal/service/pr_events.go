@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/integration"
+	"ReviewAssigner/internal/models"
+)
+
+// ApplyEvent применяет событие внешней системы к PR.
+// Повторная доставка события не должна ломать состояние, поэтому создание
+// существующего PR и мердж уже мердженного PR не считаются ошибкой.
+func (s *PRService) ApplyEvent(ctx context.Context, event *integration.Event) (*models.PullRequest, error) {
+	s.logger.Info("applying external PR event",
+		"pr_id", event.PullRequestID, "action", event.Action, "source", event.Source)
+
+	switch event.Action {
+	case integration.ActionCreate:
+		return s.createFromEvent(ctx, event)
+	case integration.ActionReady, integration.ActionReopen:
+		exists, err := s.prRepo.PRExists(ctx, event.PullRequestID)
+		if err != nil {
+			s.logger.Error("failed to check PR existence", "pr_id", event.PullRequestID, "error", err)
+			return nil, repoError(err, nil, "failed to check PR existence")
+		}
+		if !exists && event.AuthorID == "" {
+			// без автора PR завёлся бы на того, кто вызвал событие
+			s.logger.Info("ignoring event for unknown PR without author",
+				"pr_id", event.PullRequestID, "action", event.Action)
+			return nil, errors.ErrPRNotFound.WithDetails("pull_request_id", event.PullRequestID)
+		}
+		if !exists {
+			// вебхук подключили к уже существующему PR — заводим его сразу открытым
+			s.logger.Info("creating unknown PR from event", "pr_id", event.PullRequestID, "action", event.Action)
+			opened := *event
+			opened.Draft = false
+			return s.createFromEvent(ctx, &opened)
+		}
+		if event.Action == integration.ActionReady {
+			return s.MarkReady(ctx, event.PullRequestID)
+		}
+		return s.ReopenPR(ctx, event.PullRequestID)
+	case integration.ActionMerge:
+		// мердж уже произошёл во внешней системе, политика команды не проверяется
+		return s.MergePR(ctx, event.PullRequestID, true)
+	case integration.ActionClose:
+		return s.ClosePR(ctx, event.PullRequestID)
+	case integration.ActionReplaceReviewer:
+		if _, err := s.ReplaceReviewer(ctx, event.PullRequestID, event.ReviewerID); err != nil {
+			return nil, err
+		}
+		return s.GetPRByID(ctx, event.PullRequestID)
+	default:
+		return nil, errors.NewError("INVALID_REQUEST",
+			fmt.Sprintf("Unsupported event action: %s", event.Action))
+	}
+}
+
+func (s *PRService) createFromEvent(ctx context.Context, event *integration.Event) (*models.PullRequest, error) {
+	pr := &models.PullRequest{
+		PullRequestID:   event.PullRequestID,
+		PullRequestName: event.Title,
+		AuthorID:        event.AuthorID,
+		Source:          event.Source,
+		SourceURL:       event.URL,
+	}
+	if event.Draft {
+		pr.Status = models.PRStatusDraft
+	}
+
+	created, err := s.CreatePR(ctx, pr, nil)
+	if errors.Is(err, errors.ErrPRExists) {
+		s.logger.Info("PR from event already exists", "pr_id", event.PullRequestID)
+		return s.GetPRByID(ctx, event.PullRequestID)
+	}
+	return created, err
+}
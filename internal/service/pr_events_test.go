@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/integration"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEvent_UnknownPR(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	prs := memory.NewPRRepository(store)
+	teams := memory.NewTeamRepository(store)
+	reviewService := NewReviewService(users, prs, teams, nil)
+	prService := NewPRService(prs, users, teams, reviewService, memory.NewTxManager(store), nil)
+
+	for _, id := range []string{"author", "reopener"} {
+		require.NoError(t, users.CreateOrUpdateUser(ctx, &models.User{UserID: id, TeamName: "backend", IsActive: true}))
+	}
+
+	// без автора неизвестный PR не заводится
+	_, err := prService.ApplyEvent(ctx, &integration.Event{
+		Action:        integration.ActionReopen,
+		PullRequestID: "gl-1-5",
+		Source:        models.PRSourceGitLab,
+	})
+	assert.True(t, errors.Is(err, errors.ErrPRNotFound))
+	exists, err := prs.PRExists(ctx, "gl-1-5")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	pr, err := prService.ApplyEvent(ctx, &integration.Event{
+		Action:        integration.ActionReady,
+		PullRequestID: "gh-5",
+		AuthorID:      "author",
+		Source:        models.PRSourceGitHub,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "author", pr.AuthorID)
+	assert.Equal(t, models.PRStatusOpen, pr.Status)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// prTransitions допустимые переходы статусов PR
+var prTransitions = map[string][]string{
+	models.PRStatusDraft:  {models.PRStatusOpen, models.PRStatusClosed},
+	models.PRStatusOpen:   {models.PRStatusMerged, models.PRStatusClosed},
+	models.PRStatusClosed: {models.PRStatusOpen},
+	models.PRStatusMerged: {},
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range prTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkReady переводит черновик в OPEN и назначает ревьюеров одной транзакцией
+func (s *PRService) MarkReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	s.logger.Info("marking PR ready for review", "pr_id", prID)
+
+	var ready *models.PullRequest
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := s.transition(ctx, repos, prID, models.PRStatusOpen)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.assignTeamReviewers(ctx, repos, before); err != nil {
+			s.logger.Error("failed to assign reviewers, keeping PR in draft",
+				"pr_id", prID, "error", err)
+			return err
+		}
+
+		ready, err = s.auditPRChange(ctx, repos, models.AuditPRReady, before)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("successfully marked PR ready", "pr_id", prID)
+	return ready, nil
+}
+
+// ClosePR закрывает PR без мерджа и снимает ревьюеров
+func (s *PRService) ClosePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	s.logger.Info("closing PR", "pr_id", prID)
+
+	var closed *models.PullRequest
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := s.transition(ctx, repos, prID, models.PRStatusClosed)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.PRs.ReleaseReviewers(ctx, prID); err != nil {
+			s.logger.Error("failed to release reviewers", "pr_id", prID, "error", err)
+			return repoError(err, nil, "failed to release reviewers")
+		}
+
+		closed, err = s.auditPRChange(ctx, repos, models.AuditPRClosed, before)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("successfully closed PR", "pr_id", prID)
+	return closed, nil
+}
+
+// ReopenPR открывает закрытый PR заново и назначает новых ревьюеров
+func (s *PRService) ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	s.logger.Info("reopening PR", "pr_id", prID)
+
+	var reopened *models.PullRequest
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := s.transition(ctx, repos, prID, models.PRStatusOpen)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.assignTeamReviewers(ctx, repos, before); err != nil {
+			s.logger.Error("failed to assign reviewers, keeping PR closed",
+				"pr_id", prID, "error", err)
+			return err
+		}
+
+		reopened, err = s.auditPRChange(ctx, repos, models.AuditPRReopened, before)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("successfully reopened PR", "pr_id", prID)
+	return reopened, nil
+}
+
+// auditPRChange читает PR после изменения в транзакции операции и пишет
+// в журнал его состояние до и после
+func (s *PRService) auditPRChange(ctx context.Context, repos repository.Repositories, operation string, before *models.PullRequest) (*models.PullRequest, error) {
+	after, err := repos.PRs.GetPRByID(ctx, before.PullRequestID)
+	if err != nil {
+		s.logger.Error("failed to get PR after change", "pr_id", before.PullRequestID, "error", err)
+		return nil, repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", before.PullRequestID), "failed to get PR")
+	}
+
+	if err := writeAudit(ctx, repos.Audit, operation, models.AuditEntityPR, before.PullRequestID, before, after); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// SetStatus переводит PR в статус status: OPEN (из DRAFT — MarkReady, из CLOSED —
+// ReopenPR), MERGED или CLOSED. Повторная установка текущего статуса ничего не меняет.
+func (s *PRService) SetStatus(ctx context.Context, prID, status string, force bool) (*models.PullRequest, error) {
+	pr, err := s.GetPRByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == status {
+		return pr, nil
+	}
+
+	switch status {
+	case models.PRStatusMerged:
+		return s.MergePR(ctx, prID, force)
+	case models.PRStatusClosed:
+		return s.ClosePR(ctx, prID)
+	case models.PRStatusOpen:
+		if pr.Status == models.PRStatusDraft {
+			return s.MarkReady(ctx, prID)
+		}
+		return s.ReopenPR(ctx, prID)
+	default:
+		return nil, errors.NewError(errors.ErrInvalidTransition.Code,
+			fmt.Sprintf("Cannot change PR status to %s", status))
+	}
+}
+
+// transition проверяет переход по конечному автомату и меняет статус PR.
+// Возвращает PR в состоянии до перехода.
+func (s *PRService) transition(ctx context.Context, repos repository.Repositories, prID, to string) (*models.PullRequest, error) {
+	pr, err := repos.PRs.GetPRByID(ctx, prID)
+	if err != nil {
+		s.logger.Error("failed to get PR for status change", "pr_id", prID, "error", err)
+		return nil, repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+	}
+
+	if !canTransition(pr.Status, to) {
+		s.logger.Warn("illegal PR status transition",
+			"pr_id", prID, "from", pr.Status, "to", to)
+		return nil, errors.NewError(errors.ErrInvalidTransition.Code,
+			fmt.Sprintf("Cannot change PR status from %s to %s", pr.Status, to))
+	}
+
+	if err := repos.PRs.TransitionPR(ctx, prID, pr.Status, to); err != nil {
+		s.logger.Error("failed to change PR status",
+			"pr_id", prID, "from", pr.Status, "to", to, "error", err)
+		// статус успели поменять параллельным запросом
+		if stderrors.Is(err, repository.ErrConflict) {
+			return nil, errors.WrapError(errors.ErrInvalidTransition, err)
+		}
+		return nil, repoError(err, nil, "failed to change PR status")
+	}
+
+	s.logger.Debug("PR status changed", "pr_id", prID, "from", pr.Status, "to", to)
+	return pr, nil
+}
+
+// assignTeamReviewers назначает ревьюеров из команды автора по настройкам команды
+func (s *PRService) assignTeamReviewers(ctx context.Context, repos repository.Repositories, pr *models.PullRequest) ([]string, error) {
+	author, err := repos.Users.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.logger.Error("failed to get author", "author_id", pr.AuthorID, "error", err)
+		return nil, repoError(err, errors.ErrAuthorNotFound.WithDetails("author_id", pr.AuthorID), "failed to get author")
+	}
+
+	count := s.teamReviewerCount(ctx, repos.Teams, author.TeamName)
+	reviewers, err := s.reviewService.withRepos(repos).AssignReviewers(ctx, author.TeamName, pr.AuthorID, pr.PullRequestID, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign reviewers: %w", err)
+	}
+	return reviewers, nil
+}
+
+// notAssignedError ошибка NOT_ASSIGNED с PR и ревьюером в деталях
+func notAssignedError(prID, reviewerID string) *errors.Error {
+	return errors.ErrNotAssigned.
+		WithDetails("pull_request_id", prID).
+		WithDetails("reviewer_id", reviewerID)
+}
+
+// openPRError возвращает ошибку для операций, доступных только на открытом PR
+func openPRError(status string, mergedErr *errors.Error) error {
+	switch status {
+	case models.PRStatusOpen:
+		return nil
+	case models.PRStatusMerged:
+		return mergedErr
+	default:
+		return errors.ErrPRNotOpen
+	}
+}
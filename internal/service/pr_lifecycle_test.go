@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"ReviewAssigner/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.PRStatusDraft, models.PRStatusOpen, true},
+		{models.PRStatusDraft, models.PRStatusClosed, true},
+		{models.PRStatusDraft, models.PRStatusMerged, false},
+		{models.PRStatusOpen, models.PRStatusMerged, true},
+		{models.PRStatusOpen, models.PRStatusClosed, true},
+		{models.PRStatusOpen, models.PRStatusDraft, false},
+		{models.PRStatusClosed, models.PRStatusOpen, true},
+		{models.PRStatusClosed, models.PRStatusMerged, false},
+		{models.PRStatusMerged, models.PRStatusOpen, false},
+		{models.PRStatusMerged, models.PRStatusClosed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, canTransition(tt.from, tt.to))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -13,14 +15,18 @@ import (
 type PRService struct {
 	prRepo        repository.PRRepository
 	userRepo      repository.UserRepository
+	teamRepo      repository.TeamRepository
 	reviewService *ReviewService
+	txManager     repository.TxManager
 	logger        *slog.Logger
 }
 
 func NewPRService(
 	prRepo repository.PRRepository,
 	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
 	reviewService *ReviewService,
+	txManager repository.TxManager,
 	logger *slog.Logger,
 ) *PRService {
 	if logger == nil {
@@ -30,70 +36,125 @@ func NewPRService(
 	return &PRService{
 		prRepo:        prRepo,
 		userRepo:      userRepo,
+		teamRepo:      teamRepo,
 		reviewService: reviewService,
+		txManager:     txManager,
 		logger:        logger,
 	}
 }
 
-func (s *PRService) GetPRByID(prID string) (*models.PullRequest, error) {
+func (s *PRService) GetPRByID(ctx context.Context, prID string) (*models.PullRequest, error) {
 	s.logger.Debug("getting PR by ID", "pr_id", prID)
 
-	pr, err := s.prRepo.GetPRByID(prID)
+	pr, err := s.prRepo.GetPRByID(ctx, prID)
 	if err != nil {
 		s.logger.Error("failed to get PR by ID", "pr_id", prID, "error", err)
-		return nil, errors.WrapError(errors.ErrPRNotFound, err)
+		return nil, repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
 	}
 
 	s.logger.Debug("successfully retrieved PR", "pr_id", prID, "status", pr.Status)
 	return pr, nil
 }
 
-func (s *PRService) CreatePR(pr *models.PullRequest) (*models.PullRequest, error) {
+// CreatePR создаёт PR и назначает ревьюеров. reviewerCount переопределяет
+// число ревьюеров команды автора, nil означает значение команды.
+// PR со статусом DRAFT создаётся без ревьюеров до перевода в OPEN.
+func (s *PRService) CreatePR(ctx context.Context, pr *models.PullRequest, reviewerCount *int) (*models.PullRequest, error) {
 	start := time.Now()
 	s.logger.Info("creating PR", "pr_id", pr.PullRequestID, "author_id", pr.AuthorID)
 
+	if reviewerCount != nil {
+		// черновик создаётся без ревьюеров, а при переходе в ревью берётся число команды
+		if pr.Status == models.PRStatusDraft {
+			s.logger.Warn("reviewer count for draft PR", "pr_id", pr.PullRequestID)
+			return nil, errors.ErrDraftReviewerCount.WithDetails("reviewer_count", *reviewerCount)
+		}
+		if err := validateReviewerCount(*reviewerCount); err != nil {
+			s.logger.Warn("invalid reviewer count",
+				"pr_id", pr.PullRequestID, "reviewer_count", *reviewerCount)
+			return nil, err
+		}
+	}
+
 	// проверка существования
-	exists, err := s.prRepo.PRExists(pr.PullRequestID)
+	exists, err := s.prRepo.PRExists(ctx, pr.PullRequestID)
 	if err != nil {
 		s.logger.Error("failed to check PR existence", "pr_id", pr.PullRequestID, "error", err)
-		return nil, fmt.Errorf("failed to check PR existence: %w", err)
+		return nil, repoError(err, nil, "failed to check PR existence")
 	}
 	if exists {
 		s.logger.Warn("PR already exists", "pr_id", pr.PullRequestID)
-		return nil, errors.ErrPRExists
+		return nil, errors.ErrPRExists.WithDetails("pull_request_id", pr.PullRequestID)
 	}
 
 	// проверка автора
-	author, err := s.userRepo.GetUserByID(pr.AuthorID)
+	author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
 	if err != nil {
-		s.logger.Error("author not found", "author_id", pr.AuthorID, "error", err)
-		return nil, errors.ErrAuthorNotFound
+		s.logger.Error("failed to get author", "author_id", pr.AuthorID, "error", err)
+		return nil, repoError(err, errors.ErrAuthorNotFound.WithDetails("author_id", pr.AuthorID), "failed to get author")
+	}
+
+	count := s.teamReviewerCount(ctx, s.teamRepo, author.TeamName)
+	if reviewerCount != nil {
+		count = *reviewerCount
 	}
 
-	pr.Status = "OPEN"
+	if pr.Status != models.PRStatusDraft {
+		pr.Status = models.PRStatusOpen
+	}
+	if pr.Source == "" {
+		pr.Source = models.PRSourceAPI
+	}
 	now := time.Now()
 	pr.CreatedAt = &now
 
-	// создаём PR
-	if err := s.prRepo.CreatePR(pr); err != nil {
-		s.logger.Error("failed to create PR", "pr_id", pr.PullRequestID, "error", err)
-		return nil, fmt.Errorf("failed to create PR: %w", err)
-	}
+	// PR и его ревьюеры создаются в одной транзакции
+	var reviewers []string
+	err = s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.PRs.CreatePR(ctx, pr); err != nil {
+			s.logger.Error("failed to create PR", "pr_id", pr.PullRequestID, "error", err)
+			// PR с тем же id создан параллельным запросом после проверки
+			if stderrors.Is(err, repository.ErrConflict) {
+				return errors.WrapError(errors.ErrPRExists.WithDetails("pull_request_id", pr.PullRequestID), err)
+			}
+			return repoError(err, nil, "failed to create PR")
+		}
+
+		if pr.Status == models.PRStatusDraft {
+			pr.AssignedReviewers = []string{}
+		} else {
+			assigned, err := s.reviewService.withRepos(repos).AssignReviewers(ctx, author.TeamName, pr.AuthorID, pr.PullRequestID, count)
+			if err != nil {
+				s.logger.Error("failed to assign reviewers, rolling back PR creation",
+					"pr_id", pr.PullRequestID, "error", err)
+				return fmt.Errorf("failed to assign reviewers: %w", err)
+			}
+			reviewers = assigned
+			pr.AssignedReviewers = assigned
+		}
 
-	// назначаем ревьюверов
-	reviewers, err := s.reviewService.AssignReviewers(author.TeamName, pr.AuthorID, pr.PullRequestID)
+		return writeAudit(ctx, repos.Audit, models.AuditPRCreated, models.AuditEntityPR, pr.PullRequestID, nil, pr)
+	})
 	if err != nil {
-		s.logger.Error("failed to assign reviewers, rolling back PR creation",
-			"pr_id", pr.PullRequestID, "error", err)
+		return nil, err
+	}
 
-		if delErr := s.prRepo.DeletePR(pr.PullRequestID); delErr != nil {
-			s.logger.Error("failed to delete PR during rollback",
-				"pr_id", pr.PullRequestID, "error", delErr)
-		}
-		return nil, fmt.Errorf("failed to assign reviewers: %w", err)
+	if pr.Status == models.PRStatusDraft {
+		pr.AssignedReviewers = []string{}
+		pr.Reviews = []models.Review{}
+		s.logger.Info("successfully created draft PR", "pr_id", pr.PullRequestID)
+		return pr, nil
 	}
 
 	pr.AssignedReviewers = reviewers
+	pr.Reviews = make([]models.Review, len(reviewers))
+	for i, reviewerID := range reviewers {
+		pr.Reviews[i] = models.Review{
+			ReviewerID: reviewerID,
+			State:      models.ReviewStatePending,
+			AssignedAt: &now,
+		}
+	}
 
 	duration := time.Since(start)
 	s.logger.Info("successfully created PR",
@@ -111,61 +172,160 @@ func (s *PRService) CreatePR(pr *models.PullRequest) (*models.PullRequest, error
 	return pr, nil
 }
 
-func (s *PRService) MergePR(prID string) (*models.PullRequest, error) {
-	s.logger.Info("merging PR", "pr_id", prID)
-
-	pr, err := s.prRepo.GetPRByID(prID)
+// teamReviewerCount возвращает число ревьюеров по умолчанию для команды
+func (s *PRService) teamReviewerCount(ctx context.Context, teamRepo repository.TeamRepository, teamName string) int {
+	settings, err := teamRepo.GetTeamSettings(ctx, teamName)
 	if err != nil {
-		s.logger.Error("PR not found for merge", "pr_id", prID, "error", err)
-		return nil, errors.WrapError(errors.ErrPRNotFound, err)
+		s.logger.Warn("failed to get team settings, using default reviewer count",
+			"team_name", teamName, "error", err)
+		return DefaultReviewerCount
 	}
-
-	if pr.Status == "MERGED" {
-		s.logger.Info("PR already merged", "pr_id", prID)
-		return pr, nil
+	if settings.ReviewerCount <= 0 {
+		return DefaultReviewerCount
 	}
+	return settings.ReviewerCount
+}
+
+// MergePR мерджит PR, если выполнена политика мерджа команды автора.
+// force пропускает проверку политики, право на него проверяется выше.
+// Статус и одобрения проверяются в транзакции мерджа, а мердж проходит,
+// только если PR всё ещё открыт: параллельное закрытие не превратится в MERGED.
+func (s *PRService) MergePR(ctx context.Context, prID string, force bool) (*models.PullRequest, error) {
+	s.logger.Info("merging PR", "pr_id", prID, "force", force)
+
+	var merged *models.PullRequest
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		pr, err := repos.PRs.GetPRByID(ctx, prID)
+		if err != nil {
+			s.logger.Error("failed to get PR for merge", "pr_id", prID, "error", err)
+			return repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+		}
+
+		if pr.Status == models.PRStatusMerged {
+			s.logger.Info("PR already merged", "pr_id", prID)
+			merged = pr
+			return nil
+		}
+
+		if !canTransition(pr.Status, models.PRStatusMerged) {
+			s.logger.Warn("cannot merge PR in current status", "pr_id", prID, "status", pr.Status)
+			return errors.NewError(errors.ErrInvalidTransition.Code,
+				fmt.Sprintf("Cannot merge PR in status %s", pr.Status))
+		}
+
+		if force {
+			s.logger.Warn("merge policy bypassed by force flag", "pr_id", prID)
+		} else if err := s.checkMergePolicy(ctx, repos, pr); err != nil {
+			return err
+		}
+
+		if err := repos.PRs.MergePR(ctx, prID); err != nil {
+			s.logger.Error("failed to merge PR", "pr_id", prID, "error", err)
+			// статус успели поменять параллельным запросом
+			if stderrors.Is(err, repository.ErrConflict) {
+				return errors.WrapError(errors.ErrInvalidTransition, err)
+			}
+			return repoError(err, nil, "failed to merge PR")
+		}
+
+		merged, err = repos.PRs.GetPRByID(ctx, prID)
+		if err != nil {
+			s.logger.Error("failed to get PR after merge", "pr_id", prID, "error", err)
+			return repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+		}
 
-	if err := s.prRepo.MergePR(prID); err != nil {
-		s.logger.Error("failed to merge PR", "pr_id", prID, "error", err)
-		return nil, fmt.Errorf("failed to merge PR: %w", err)
+		return writeAudit(ctx, repos.Audit, models.AuditPRMerged, models.AuditEntityPR, prID, pr, merged)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("successfully merged PR", "pr_id", prID)
-	return s.prRepo.GetPRByID(prID)
+	return merged, nil
 }
 
-func (s *PRService) ReplaceReviewer(prID, oldReviewerID string) (string, error) {
-	s.logger.Info("replacing reviewer",
-		"pr_id", prID,
-		"old_reviewer_id", oldReviewerID)
+// checkMergePolicy проверяет одобрения ревьюеров по политике команды автора
+func (s *PRService) checkMergePolicy(ctx context.Context, repos repository.Repositories, pr *models.PullRequest) error {
+	author, err := repos.Users.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		s.logger.Error("failed to get author for merge policy", "author_id", pr.AuthorID, "error", err)
+		return repoError(err, errors.ErrAuthorNotFound.WithDetails("author_id", pr.AuthorID), "failed to get author")
+	}
 
-	pr, err := s.prRepo.GetPRByID(prID)
+	settings, err := repos.Teams.GetTeamSettings(ctx, author.TeamName)
 	if err != nil {
-		s.logger.Error("PR not found for reviewer replacement", "pr_id", prID, "error", err)
-		return "", errors.WrapError(errors.ErrPRNotFound, err)
+		s.logger.Error("failed to get merge policy",
+			"team_name", author.TeamName, "error", err)
+		return repoError(err, nil, "failed to get merge policy")
 	}
 
-	if pr.Status == "MERGED" {
-		s.logger.Warn("attempted to replace reviewer on merged PR", "pr_id", prID)
-		return "", errors.ErrPRMerged
+	approvals := 0
+	changesRequested := false
+	for _, review := range pr.Reviews {
+		switch review.State {
+		case models.ReviewStateApproved:
+			approvals++
+		case models.ReviewStateChangesRequested:
+			changesRequested = true
+		}
 	}
 
-	assigned, err := s.prRepo.IsReviewerAssigned(prID, oldReviewerID)
-	if err != nil {
-		s.logger.Error("failed to check reviewer assignment",
-			"pr_id", prID, "reviewer_id", oldReviewerID, "error", err)
-		return "", fmt.Errorf("failed to check reviewer assignment: %w", err)
+	if settings.BlockOnChangesRequested && changesRequested {
+		s.logger.Warn("merge blocked by requested changes", "pr_id", pr.PullRequestID)
+		return errors.NewError(errors.ErrMergeBlocked.Code, "Merge blocked: changes requested by reviewer")
 	}
-	if !assigned {
-		s.logger.Warn("reviewer not assigned to PR",
-			"pr_id", prID, "reviewer_id", oldReviewerID)
-		return "", errors.ErrNotAssigned
+	if approvals < settings.RequiredApprovals {
+		s.logger.Warn("merge blocked by missing approvals",
+			"pr_id", pr.PullRequestID,
+			"approvals", approvals,
+			"required_approvals", settings.RequiredApprovals)
+		return errors.NewError(errors.ErrMergeBlocked.Code,
+			fmt.Sprintf("Merge blocked: %d of %d required approvals", approvals, settings.RequiredApprovals))
 	}
+	return nil
+}
+
+func (s *PRService) ReplaceReviewer(ctx context.Context, prID, oldReviewerID string) (string, error) {
+	s.logger.Info("replacing reviewer",
+		"pr_id", prID,
+		"old_reviewer_id", oldReviewerID)
+
+	var newReviewerID string
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		pr, err := repos.PRs.GetPRByID(ctx, prID)
+		if err != nil {
+			s.logger.Error("failed to get PR for reviewer replacement", "pr_id", prID, "error", err)
+			return repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+		}
+
+		if err := openPRError(pr.Status, errors.ErrPRMerged); err != nil {
+			s.logger.Warn("attempted to replace reviewer on PR that is not open",
+				"pr_id", prID, "status", pr.Status)
+			return err
+		}
+
+		assigned, err := repos.PRs.IsReviewerAssigned(ctx, prID, oldReviewerID)
+		if err != nil {
+			s.logger.Error("failed to check reviewer assignment",
+				"pr_id", prID, "reviewer_id", oldReviewerID, "error", err)
+			return repoError(err, nil, "failed to check reviewer assignment")
+		}
+		if !assigned {
+			s.logger.Warn("reviewer not assigned to PR",
+				"pr_id", prID, "reviewer_id", oldReviewerID)
+			return notAssignedError(prID, oldReviewerID)
+		}
+
+		newReviewerID, err = s.reviewService.withRepos(repos).ReplaceReviewer(ctx, prID, oldReviewerID)
+		if err != nil {
+			s.logger.Error("failed to replace reviewer",
+				"pr_id", prID, "old_reviewer_id", oldReviewerID, "error", err)
+			return err
+		}
 
-	newReviewerID, err := s.reviewService.ReplaceReviewer(prID, oldReviewerID)
+		return auditReviewerReplaced(ctx, repos, models.AuditReviewerReplaced, prID, pr.AssignedReviewers)
+	})
 	if err != nil {
-		s.logger.Error("failed to replace reviewer",
-			"pr_id", prID, "old_reviewer_id", oldReviewerID, "error", err)
 		return "", err
 	}
 
@@ -177,45 +337,144 @@ func (s *PRService) ReplaceReviewer(prID, oldReviewerID string) (string, error)
 	return newReviewerID, nil
 }
 
-func (s *PRService) GetAssignedPRs(userID string) ([]models.PullRequestShort, error) {
+// reviewersState ревьюеры PR в журнале аудита
+type reviewersState struct {
+	AssignedReviewers []string `json:"assigned_reviewers"`
+}
+
+// auditReviewerReplaced записывает замену ревьюера: before — ревьюеры до замены,
+// after читается из транзакции замены
+func auditReviewerReplaced(ctx context.Context, repos repository.Repositories, operation, prID string, before []string) error {
+	after, err := repos.PRs.GetPRReviewers(ctx, prID)
+	if err != nil {
+		return repoError(err, nil, "failed to get PR reviewers")
+	}
+	return writeAudit(ctx, repos.Audit, operation, models.AuditEntityPR, prID,
+		reviewersState{AssignedReviewers: before}, reviewersState{AssignedReviewers: after})
+}
+
+// SubmitReview фиксирует вердикт назначенного ревьюера
+func (s *PRService) SubmitReview(ctx context.Context, prID, reviewerID, verdict string) (*models.PullRequest, error) {
+	s.logger.Info("submitting review",
+		"pr_id", prID,
+		"reviewer_id", reviewerID,
+		"verdict", verdict)
+
+	switch verdict {
+	case models.ReviewStateApproved, models.ReviewStateChangesRequested, models.ReviewStateCommented:
+	default:
+		s.logger.Warn("invalid review verdict", "pr_id", prID, "verdict", verdict)
+		return nil, errors.ErrInvalidVerdict
+	}
+
+	// вердикт и запись аудита сохраняются вместе
+	var reviewed *models.PullRequest
+	err := s.txManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		pr, err := repos.PRs.GetPRByID(ctx, prID)
+		if err != nil {
+			s.logger.Error("failed to get PR for review", "pr_id", prID, "error", err)
+			return repoError(err, errors.ErrPRNotFound.WithDetails("pull_request_id", prID), "failed to get PR")
+		}
+
+		if err := openPRError(pr.Status, errors.ErrReviewOnMerged); err != nil {
+			s.logger.Warn("attempted to review PR that is not open",
+				"pr_id", prID, "status", pr.Status)
+			return err
+		}
+
+		assigned, err := repos.PRs.IsReviewerAssigned(ctx, prID, reviewerID)
+		if err != nil {
+			s.logger.Error("failed to check reviewer assignment",
+				"pr_id", prID, "reviewer_id", reviewerID, "error", err)
+			return repoError(err, nil, "failed to check reviewer assignment")
+		}
+		if !assigned {
+			s.logger.Warn("reviewer not assigned to PR",
+				"pr_id", prID, "reviewer_id", reviewerID)
+			return notAssignedError(prID, reviewerID)
+		}
+
+		if err := repos.PRs.SetReviewState(ctx, prID, reviewerID, verdict); err != nil {
+			s.logger.Error("failed to save review verdict",
+				"pr_id", prID, "reviewer_id", reviewerID, "error", err)
+			if stderrors.Is(err, repository.ErrNotFound) {
+				return errors.WrapError(notAssignedError(prID, reviewerID), err)
+			}
+			return repoError(err, nil, "failed to save review verdict")
+		}
+
+		reviewed, err = s.auditPRChange(ctx, repos, models.AuditPRReviewed, pr)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("successfully submitted review",
+		"pr_id", prID,
+		"reviewer_id", reviewerID,
+		"verdict", verdict)
+	return reviewed, nil
+}
+
+func (s *PRService) GetAssignedPRs(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	s.logger.Debug("getting assigned PRs for user", "user_id", userID)
 
-	_, err := s.userRepo.GetUserByID(userID)
+	_, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		s.logger.Error("user not found", "user_id", userID, "error", err)
-		return nil, errors.WrapError(errors.ErrUserNotFound, err)
+		return nil, repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
 	}
 
-	prs, err := s.prRepo.GetAssignedPRs(userID)
+	prs, err := s.prRepo.GetAssignedPRs(ctx, userID)
 	if err != nil {
 		s.logger.Error("failed to get assigned PRs", "user_id", userID, "error", err)
-		return nil, fmt.Errorf("failed to get assigned PRs: %w", err)
+		return nil, repoError(err, nil, "failed to get assigned PRs")
 	}
 
 	s.logger.Debug("retrieved assigned PRs", "user_id", userID, "count", len(prs))
 	return prs, nil
 }
 
-func (s *PRService) GetUserAssignmentStats() (map[string]int, error) {
+// ListPRs возвращает страницу PR по фильтру и курсор следующей страницы
+func (s *PRService) ListPRs(ctx context.Context, filter repository.PRFilter, page repository.Page) ([]models.PullRequestShort, string, error) {
+	if filter.Sort != "" && !repository.ValidPRSort(filter.Sort) {
+		return nil, "", errors.ErrInvalidSort.WithDetails("sort", filter.Sort)
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, "", errors.NewError("INVALID_REQUEST", "created_from must be before created_to")
+	}
+
+	prs, next, err := s.prRepo.ListPRs(ctx, filter, page)
+	if err != nil {
+		s.logger.Error("failed to list PRs", "filter", filter, "error", err)
+		return nil, "", repoError(err, nil, "failed to list PRs")
+	}
+
+	s.logger.Debug("listed PRs", "count", len(prs), "has_more", next != "")
+	return prs, next, nil
+}
+
+func (s *PRService) GetUserAssignmentStats(ctx context.Context) (map[string]int, error) {
 	s.logger.Debug("getting user assignment stats")
 
-	stats, err := s.prRepo.GetUserAssignmentStats()
+	stats, err := s.prRepo.GetUserAssignmentStats(ctx)
 	if err != nil {
 		s.logger.Error("failed to get user assignment stats", "error", err)
-		return nil, fmt.Errorf("failed to get user assignment stats: %w", err)
+		return nil, repoError(err, nil, "failed to get user assignment stats")
 	}
 
 	s.logger.Debug("retrieved user assignment stats", "user_count", len(stats))
 	return stats, nil
 }
 
-func (s *PRService) GetPRMetrics() (map[string]interface{}, error) {
+func (s *PRService) GetPRMetrics(ctx context.Context) (map[string]interface{}, error) {
 	s.logger.Debug("getting PR metrics")
 
-	metrics, err := s.prRepo.GetPRMetrics()
+	metrics, err := s.prRepo.GetPRMetrics(ctx)
 	if err != nil {
 		s.logger.Error("failed to get PR metrics", "error", err)
-		return nil, fmt.Errorf("failed to get PR metrics: %w", err)
+		return nil, repoError(err, nil, "failed to get PR metrics")
 	}
 
 	s.logger.Debug("retrieved PR metrics", "metrics_count", len(metrics))
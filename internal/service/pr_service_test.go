@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMergeFixture команда backend из автора и двух ревьюеров с политикой
+// мерджа settings и открытый PR pr-1 с обоими ревьюерами
+func newMergeFixture(t *testing.T, settings models.TeamSettings) *PRService {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	prs := memory.NewPRRepository(store)
+	teams := memory.NewTeamRepository(store)
+	prService := NewPRService(prs, users, teams, NewReviewService(users, prs, teams, nil), memory.NewTxManager(store), nil)
+
+	require.NoError(t, teams.CreateTeam(ctx, "backend"))
+	settings.ReviewerCount = 2
+	require.NoError(t, teams.UpdateTeamSettings(ctx, "backend", &settings))
+	for _, id := range []string{"author", "r1", "r2"} {
+		require.NoError(t, users.CreateOrUpdateUser(ctx, &models.User{UserID: id, TeamName: "backend", IsActive: true}))
+	}
+
+	pr, err := prService.CreatePR(ctx, &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add cache", AuthorID: "author"}, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"r1", "r2"}, pr.AssignedReviewers)
+	return prService
+}
+
+func TestMergePR_RequiredApprovals(t *testing.T) {
+	ctx := context.Background()
+	prService := newMergeFixture(t, models.TeamSettings{RequiredApprovals: 2})
+
+	_, err := prService.SubmitReview(ctx, "pr-1", "r1", models.ReviewStateApproved)
+	require.NoError(t, err)
+
+	_, err = prService.MergePR(ctx, "pr-1", false)
+	assert.True(t, errors.Is(err, errors.ErrMergeBlocked), "одно одобрение из двух")
+
+	_, err = prService.SubmitReview(ctx, "pr-1", "r2", models.ReviewStateApproved)
+	require.NoError(t, err)
+
+	merged, err := prService.MergePR(ctx, "pr-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusMerged, merged.Status)
+}
+
+func TestMergePR_BlockOnChangesRequested(t *testing.T) {
+	ctx := context.Background()
+	prService := newMergeFixture(t, models.TeamSettings{RequiredApprovals: 1, BlockOnChangesRequested: true})
+
+	_, err := prService.SubmitReview(ctx, "pr-1", "r1", models.ReviewStateApproved)
+	require.NoError(t, err)
+	_, err = prService.SubmitReview(ctx, "pr-1", "r2", models.ReviewStateChangesRequested)
+	require.NoError(t, err)
+
+	_, err = prService.MergePR(ctx, "pr-1", false)
+	assert.True(t, errors.Is(err, errors.ErrMergeBlocked))
+
+	// force пропускает политику
+	merged, err := prService.MergePR(ctx, "pr-1", true)
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusMerged, merged.Status)
+}
+
+func TestMergePR_ClosedPRStaysClosed(t *testing.T) {
+	ctx := context.Background()
+	prService := newMergeFixture(t, models.TeamSettings{})
+
+	_, err := prService.ClosePR(ctx, "pr-1")
+	require.NoError(t, err)
+
+	_, err = prService.MergePR(ctx, "pr-1", true)
+	assert.True(t, errors.Is(err, errors.ErrInvalidTransition))
+
+	pr, err := prService.GetPRByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.PRStatusClosed, pr.Status)
+}
+
+func TestCreatePR_DraftRejectsReviewerCount(t *testing.T) {
+	ctx := context.Background()
+	prService := newMergeFixture(t, models.TeamSettings{})
+
+	count := 1
+	_, err := prService.CreatePR(ctx, &models.PullRequest{
+		PullRequestID: "pr-2", PullRequestName: "WIP", AuthorID: "author", Status: models.PRStatusDraft,
+	}, &count)
+	assert.True(t, errors.Is(err, errors.ErrDraftReviewerCount))
+
+	_, err = prService.GetPRByID(ctx, "pr-2")
+	assert.True(t, errors.Is(err, errors.ErrPRNotFound))
+}
@@ -1,42 +1,117 @@
 package service
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
-	"math/rand"
 
 	"ReviewAssigner/internal/errors"
 	"ReviewAssigner/internal/models"
 	"ReviewAssigner/internal/repository"
 )
 
+// границы числа ревьюеров на один PR
+const (
+	DefaultReviewerCount = 2
+	MinReviewerCount     = 1
+	MaxReviewerCount     = 5
+)
+
+func validateReviewerCount(count int) error {
+	if count < MinReviewerCount || count > MaxReviewerCount {
+		return errors.WrapError(errors.ErrInvalidReviewerCount.WithDetails("reviewer_count", count),
+			fmt.Errorf("reviewer count must be between %d and %d, got %d", MinReviewerCount, MaxReviewerCount, count))
+	}
+	return nil
+}
+
 type ReviewService struct {
-	userRepo repository.UserRepository
-	prRepo   repository.PRRepository
-	logger   *slog.Logger
+	userRepo   repository.UserRepository
+	prRepo     repository.PRRepository
+	teamRepo   repository.TeamRepository
+	strategies map[string]SelectionStrategy
+	logger     *slog.Logger
 }
 
 func NewReviewService(
 	userRepo repository.UserRepository,
 	prRepo repository.PRRepository,
+	teamRepo repository.TeamRepository,
 	logger *slog.Logger,
 ) *ReviewService {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
-	return &ReviewService{
-		userRepo: userRepo,
-		prRepo:   prRepo,
-		logger:   logger,
+	s := &ReviewService{
+		userRepo:   userRepo,
+		prRepo:     prRepo,
+		teamRepo:   teamRepo,
+		strategies: make(map[string]SelectionStrategy),
+		logger:     logger,
+	}
+
+	s.RegisterStrategy(NewRandomStrategy())
+	s.RegisterStrategy(NewRoundRobinStrategy())
+	s.RegisterStrategy(NewLeastLoadedStrategy(prRepo))
+	s.RegisterStrategy(NewWeightedStrategy(prRepo))
+
+	return s
+}
+
+// withRepos возвращает копию сервиса, работающую с репозиториями транзакции
+func (s *ReviewService) withRepos(repos repository.Repositories) *ReviewService {
+	txService := *s
+	txService.userRepo = repos.Users
+	txService.prRepo = repos.PRs
+	txService.teamRepo = repos.Teams
+
+	// стратегиям, читающим нагрузку, тоже нужен репозиторий транзакции
+	txService.strategies = make(map[string]SelectionStrategy, len(s.strategies))
+	for name, strategy := range s.strategies {
+		if bound, ok := strategy.(prRepoBound); ok {
+			strategy = bound.withPRRepo(repos.PRs)
+		}
+		txService.strategies[name] = strategy
+	}
+	return &txService
+}
+
+// RegisterStrategy добавляет стратегию выбора или заменяет встроенную с тем же именем
+func (s *ReviewService) RegisterStrategy(strategy SelectionStrategy) {
+	s.strategies[strategy.Name()] = strategy
+}
+
+// strategyFor возвращает стратегию, настроенную для команды, или стратегию по умолчанию
+func (s *ReviewService) strategyFor(ctx context.Context, teamName string) SelectionStrategy {
+	name := DefaultStrategy
+
+	if s.teamRepo != nil {
+		settings, err := s.teamRepo.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			s.logger.Warn("failed to get team settings, using default strategy",
+				"team_name", teamName, "error", err)
+		} else if settings.SelectionStrategy != "" {
+			name = settings.SelectionStrategy
+		}
+	}
+
+	strategy, ok := s.strategies[name]
+	if !ok {
+		s.logger.Warn("unknown selection strategy, using default",
+			"team_name", teamName, "strategy", name)
+		return s.strategies[DefaultStrategy]
 	}
+	return strategy
 }
 
-func (s *ReviewService) AssignReviewers(teamName, authorID, prID string) ([]string, error) {
+func (s *ReviewService) AssignReviewers(ctx context.Context, teamName, authorID, prID string, count int) ([]string, error) {
 	s.logger.Info("assigning reviewers",
 		"team_name", teamName,
 		"author_id", authorID,
-		"pr_id", prID)
+		"pr_id", prID,
+		"reviewer_count", count)
 
 	if s.userRepo == nil {
 		s.logger.Error("user repository is not initialized")
@@ -47,11 +122,11 @@ func (s *ReviewService) AssignReviewers(teamName, authorID, prID string) ([]stri
 		return nil, fmt.Errorf("PR repository is not initialized")
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(teamName, authorID)
+	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, teamName, authorID)
 	if err != nil {
 		s.logger.Error("failed to get team members",
 			"team_name", teamName, "author_id", authorID, "error", err)
-		return nil, fmt.Errorf("failed to get team members: %w", err)
+		return nil, repoError(err, nil, "failed to get team members")
 	}
 
 	s.logger.Debug("retrieved candidate reviewers",
@@ -64,53 +139,60 @@ func (s *ReviewService) AssignReviewers(teamName, authorID, prID string) ([]stri
 		return []string{}, nil
 	}
 
-	selected := s.selectRandomReviewers(candidates, 2)
+	strategy := s.strategyFor(ctx, teamName)
+	selected, err := strategy.Select(ctx, teamName, candidates, count)
+	if err != nil {
+		s.logger.Error("failed to select reviewers",
+			"team_name", teamName, "strategy", strategy.Name(), "error", err)
+		return nil, repoError(err, nil, "failed to select reviewers")
+	}
 	reviewerIDs := make([]string, 0, len(selected))
-
 	for _, u := range selected {
-		if err := s.prRepo.AddPRReviewer(prID, u.UserID); err != nil {
-			s.logger.Error("failed to add PR reviewer",
-				"pr_id", prID, "reviewer_id", u.UserID, "error", err)
-			return nil, fmt.Errorf("failed to add reviewer %s: %w", u.UserID, err)
-		}
 		reviewerIDs = append(reviewerIDs, u.UserID)
 	}
 
+	if err := s.prRepo.AddPRReviewers(ctx, prID, reviewerIDs); err != nil {
+		s.logger.Error("failed to add PR reviewers",
+			"pr_id", prID, "reviewers", reviewerIDs, "error", err)
+		return nil, repoError(err, nil, "failed to add reviewers")
+	}
+
 	s.logger.Info("successfully assigned reviewers",
 		"pr_id", prID,
 		"reviewers", reviewerIDs,
+		"strategy", strategy.Name(),
 		"candidate_pool_size", len(candidates))
 
 	return reviewerIDs, nil
 }
 
-func (s *ReviewService) ReplaceReviewer(prID, oldReviewerID string) (string, error) {
+func (s *ReviewService) ReplaceReviewer(ctx context.Context, prID, oldReviewerID string) (string, error) {
 	s.logger.Info("replacing reviewer",
 		"pr_id", prID,
 		"old_reviewer_id", oldReviewerID)
 
 	// информация о старом ревьювере
-	oldReviewer, err := s.userRepo.GetUserByID(oldReviewerID)
+	oldReviewer, err := s.userRepo.GetUserByID(ctx, oldReviewerID)
 	if err != nil {
-		s.logger.Error("old reviewer not found",
+		s.logger.Error("failed to get old reviewer",
 			"reviewer_id", oldReviewerID, "error", err)
-		return "", errors.WrapError(errors.ErrUserNotFound, err)
+		return "", repoError(err, errors.ErrUserNotFound.WithDetails("user_id", oldReviewerID), "failed to get old reviewer")
 	}
 
 	// текущие ревьюеры PR
-	currentReviewers, err := s.prRepo.GetPRReviewers(prID)
+	currentReviewers, err := s.prRepo.GetPRReviewers(ctx, prID)
 	if err != nil {
 		s.logger.Error("failed to get PR reviewers",
 			"pr_id", prID, "error", err)
-		return "", fmt.Errorf("failed to get PR reviewers: %w", err)
+		return "", repoError(err, nil, "failed to get PR reviewers")
 	}
 
 	// кандидаты для замены
-	candidates, err := s.userRepo.GetActiveTeamMembers(oldReviewer.TeamName, oldReviewerID)
+	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, oldReviewer.TeamName, oldReviewerID)
 	if err != nil {
 		s.logger.Error("failed to get team members for replacement",
 			"team_name", oldReviewer.TeamName, "error", err)
-		return "", fmt.Errorf("failed to get team members: %w", err)
+		return "", repoError(err, nil, "failed to get team members")
 	}
 
 	filteredCandidates := s.excludeUsers(candidates, currentReviewers)
@@ -125,51 +207,42 @@ func (s *ReviewService) ReplaceReviewer(prID, oldReviewerID string) (string, err
 	if len(filteredCandidates) == 0 {
 		s.logger.Warn("no suitable candidates for reviewer replacement",
 			"pr_id", prID, "old_reviewer_id", oldReviewerID)
-		return "", errors.ErrNoCandidate
+		return "", errors.ErrNoCandidate.WithDetails("pull_request_id", prID)
 	}
 
-	newReviewer := s.selectRandomReviewer(filteredCandidates)
+	strategy := s.strategyFor(ctx, oldReviewer.TeamName)
+	selected, err := strategy.Select(ctx, oldReviewer.TeamName, filteredCandidates, 1)
+	if err != nil {
+		s.logger.Error("failed to select replacement reviewer",
+			"team_name", oldReviewer.TeamName, "strategy", strategy.Name(), "error", err)
+		return "", repoError(err, nil, "failed to select reviewer")
+	}
+	if len(selected) == 0 {
+		return "", errors.ErrNoCandidate.WithDetails("pull_request_id", prID)
+	}
+	newReviewer := selected[0]
 
-	if err := s.prRepo.ReplacePRReviewer(prID, oldReviewerID, newReviewer.UserID); err != nil {
+	if err := s.prRepo.ReplacePRReviewer(ctx, prID, oldReviewerID, newReviewer.UserID); err != nil {
 		s.logger.Error("failed to replace PR reviewer",
 			"pr_id", prID,
 			"old_reviewer_id", oldReviewerID,
 			"new_reviewer_id", newReviewer.UserID,
 			"error", err)
-		return "", fmt.Errorf("failed to replace reviewer: %w", err)
+		if stderrors.Is(err, repository.ErrNotFound) {
+			return "", errors.WrapError(notAssignedError(prID, oldReviewerID), err)
+		}
+		return "", repoError(err, nil, "failed to replace reviewer")
 	}
 
 	s.logger.Info("successfully replaced reviewer",
 		"pr_id", prID,
 		"old_reviewer_id", oldReviewerID,
-		"new_reviewer_id", newReviewer.UserID)
+		"new_reviewer_id", newReviewer.UserID,
+		"strategy", strategy.Name())
 
 	return newReviewer.UserID, nil
 }
 
-// остальные методы остаются без изменений, только добавить логирование в selectRandomReviewers если нужно
-func (s *ReviewService) selectRandomReviewers(candidates []models.User, max int) []models.User {
-	if len(candidates) == 0 {
-		return []models.User{}
-	}
-	if len(candidates) <= max {
-		return candidates
-	}
-	shuffled := make([]models.User, len(candidates))
-	copy(shuffled, candidates)
-	rand.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
-	return shuffled[:max]
-}
-
-func (s *ReviewService) selectRandomReviewer(candidates []models.User) models.User {
-	if len(candidates) == 0 {
-		return models.User{}
-	}
-	return candidates[rand.Intn(len(candidates))]
-}
-
 func (s *ReviewService) excludeUsers(candidates []models.User, excludeIDs []string) []models.User {
 	excludeMap := make(map[string]bool)
 	for _, id := range excludeIDs {
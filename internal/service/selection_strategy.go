@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// встроенные стратегии выбора ревьюеров
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+
+	DefaultStrategy = StrategyRandom
+)
+
+// SelectionStrategy выбирает до count ревьюеров из кандидатов команды.
+// Кандидаты уже отфильтрованы: активны, не автор и не текущие ревьюеры.
+type SelectionStrategy interface {
+	Name() string
+	Select(ctx context.Context, teamName string, candidates []models.User, count int) ([]models.User, error)
+}
+
+// prRepoBound стратегия, которая читает данные из PRRepository
+type prRepoBound interface {
+	withPRRepo(prRepo repository.PRRepository) SelectionStrategy
+}
+
+// randomStrategy равновероятный случайный выбор
+type randomStrategy struct{}
+
+func NewRandomStrategy() SelectionStrategy {
+	return randomStrategy{}
+}
+
+func (randomStrategy) Name() string { return StrategyRandom }
+
+func (randomStrategy) Select(_ context.Context, _ string, candidates []models.User, count int) ([]models.User, error) {
+	if len(candidates) <= count {
+		return candidates, nil
+	}
+	shuffled := make([]models.User, len(candidates))
+	copy(shuffled, candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:count], nil
+}
+
+// roundRobinStrategy выбирает кандидатов по кругу, позиция хранится отдельно для каждой команды
+type roundRobinStrategy struct {
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+func NewRoundRobinStrategy() SelectionStrategy {
+	return &roundRobinStrategy{cursors: make(map[string]int)}
+}
+
+func (s *roundRobinStrategy) Name() string { return StrategyRoundRobin }
+
+func (s *roundRobinStrategy) Select(_ context.Context, teamName string, candidates []models.User, count int) ([]models.User, error) {
+	if len(candidates) == 0 {
+		return []models.User{}, nil
+	}
+
+	// порядок кандидатов из БД случайный, для круга нужен стабильный
+	ordered := make([]models.User, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	if count > len(ordered) {
+		count = len(ordered)
+	}
+
+	s.mu.Lock()
+	start := s.cursors[teamName] % len(ordered)
+	s.cursors[teamName] = start + count
+	s.mu.Unlock()
+
+	selected := make([]models.User, 0, count)
+	for i := 0; i < count; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	return selected, nil
+}
+
+// leastLoadedStrategy выбирает кандидатов с наименьшим числом активных
+// назначений на открытые PR, при равной нагрузке выбор случайный
+type leastLoadedStrategy struct {
+	prRepo repository.PRRepository
+}
+
+func NewLeastLoadedStrategy(prRepo repository.PRRepository) SelectionStrategy {
+	return &leastLoadedStrategy{prRepo: prRepo}
+}
+
+func (s *leastLoadedStrategy) Name() string { return StrategyLeastLoaded }
+
+func (s *leastLoadedStrategy) withPRRepo(prRepo repository.PRRepository) SelectionStrategy {
+	return &leastLoadedStrategy{prRepo: prRepo}
+}
+
+func (s *leastLoadedStrategy) Select(ctx context.Context, _ string, candidates []models.User, count int) ([]models.User, error) {
+	if len(candidates) <= count {
+		return candidates, nil
+	}
+
+	load, err := openReviewLoad(ctx, s.prRepo, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	// перемешиваем до стабильной сортировки, чтобы равная нагрузка разрешалась случайно
+	ordered := make([]models.User, len(candidates))
+	copy(ordered, candidates)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i].UserID] < load[ordered[j].UserID]
+	})
+	return ordered[:count], nil
+}
+
+// weightedStrategy случайный выбор с весом 1/(1+нагрузка):
+// менее загруженные выбираются чаще, но не всегда
+type weightedStrategy struct {
+	prRepo repository.PRRepository
+}
+
+func NewWeightedStrategy(prRepo repository.PRRepository) SelectionStrategy {
+	return &weightedStrategy{prRepo: prRepo}
+}
+
+func (s *weightedStrategy) Name() string { return StrategyWeighted }
+
+func (s *weightedStrategy) withPRRepo(prRepo repository.PRRepository) SelectionStrategy {
+	return &weightedStrategy{prRepo: prRepo}
+}
+
+func (s *weightedStrategy) Select(ctx context.Context, _ string, candidates []models.User, count int) ([]models.User, error) {
+	if len(candidates) <= count {
+		return candidates, nil
+	}
+
+	load, err := openReviewLoad(ctx, s.prRepo, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := make([]models.User, len(candidates))
+	copy(pool, candidates)
+	weights := make([]float64, len(pool))
+	for i, u := range pool {
+		weights[i] = 1 / float64(1+load[u.UserID])
+	}
+
+	// выборка без возвращения
+	selected := make([]models.User, 0, count)
+	for len(selected) < count {
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+
+		idx := len(pool) - 1
+		point := rand.Float64() * total
+		for i, w := range weights {
+			if point < w {
+				idx = i
+				break
+			}
+			point -= w
+		}
+
+		selected = append(selected, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+		weights = append(weights[:idx], weights[idx+1:]...)
+	}
+	return selected, nil
+}
+
+// openReviewLoad возвращает число открытых ревью для каждого кандидата
+func openReviewLoad(ctx context.Context, prRepo repository.PRRepository, candidates []models.User) (map[string]int, error) {
+	ids := make([]string, len(candidates))
+	for i, u := range candidates {
+		ids[i] = u.UserID
+	}
+
+	load, err := prRepo.GetOpenReviewLoad(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open review load: %w", err)
+	}
+	return load, nil
+}
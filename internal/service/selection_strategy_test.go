@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadPRRepo отдаёт фиксированную нагрузку по открытым ревью
+type loadPRRepo struct {
+	repository.PRRepository
+	load map[string]int
+}
+
+func (r *loadPRRepo) GetOpenReviewLoad(_ context.Context, userIDs []string) (map[string]int, error) {
+	res := make(map[string]int, len(userIDs))
+	for _, id := range userIDs {
+		res[id] = r.load[id]
+	}
+	return res, nil
+}
+
+func users(ids ...string) []models.User {
+	res := make([]models.User, len(ids))
+	for i, id := range ids {
+		res[i] = models.User{UserID: id, IsActive: true}
+	}
+	return res
+}
+
+func userIDs(list []models.User) []string {
+	ids := make([]string, len(list))
+	for i, u := range list {
+		ids[i] = u.UserID
+	}
+	return ids
+}
+
+func TestRandomStrategy_Select(t *testing.T) {
+	strategy := NewRandomStrategy()
+
+	selected, err := strategy.Select(context.Background(), "backend", users("u1", "u2", "u3"), 2)
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+	assert.NotEqual(t, selected[0].UserID, selected[1].UserID)
+
+	selected, err = strategy.Select(context.Background(), "backend", users("u1"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, userIDs(selected))
+}
+
+func TestRoundRobinStrategy_Select(t *testing.T) {
+	strategy := NewRoundRobinStrategy()
+
+	first, err := strategy.Select(context.Background(), "backend", users("u3", "u1", "u2"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, userIDs(first))
+
+	second, err := strategy.Select(context.Background(), "backend", users("u2", "u3", "u1"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u1"}, userIDs(second))
+
+	// у другой команды свой счётчик
+	other, err := strategy.Select(context.Background(), "frontend", users("u4", "u5"), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, userIDs(other))
+}
+
+func TestLeastLoadedStrategy_Select(t *testing.T) {
+	repo := &loadPRRepo{load: map[string]int{"u1": 8, "u2": 0, "u3": 3}}
+	strategy := NewLeastLoadedStrategy(repo)
+
+	selected, err := strategy.Select(context.Background(), "backend", users("u1", "u2", "u3"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, userIDs(selected))
+}
+
+func TestLeastLoadedStrategy_Select_TieBreak(t *testing.T) {
+	repo := &loadPRRepo{load: map[string]int{"u1": 5}}
+	strategy := NewLeastLoadedStrategy(repo)
+
+	picked := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		selected, err := strategy.Select(context.Background(), "backend", users("u1", "u2", "u3"), 1)
+		require.NoError(t, err)
+		require.Len(t, selected, 1)
+		picked[selected[0].UserID] = true
+	}
+
+	assert.False(t, picked["u1"], "loaded reviewer should never be picked")
+	assert.True(t, picked["u2"] && picked["u3"], "ties should be broken randomly")
+}
+
+func TestWeightedStrategy_Select(t *testing.T) {
+	repo := &loadPRRepo{load: map[string]int{"u1": 8}}
+	strategy := NewWeightedStrategy(repo)
+
+	selected, err := strategy.Select(context.Background(), "backend", users("u1", "u2", "u3"), 2)
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+	assert.NotEqual(t, selected[0].UserID, selected[1].UserID)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
 
@@ -12,12 +14,14 @@ import (
 type TeamService struct {
 	teamRepo repository.TeamRepository
 	userRepo repository.UserRepository
+	tx       repository.TxManager
 	logger   *slog.Logger
 }
 
 func NewTeamService(
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
+	tx repository.TxManager,
 	logger *slog.Logger,
 ) *TeamService {
 	if logger == nil {
@@ -27,42 +31,72 @@ func NewTeamService(
 	return &TeamService{
 		teamRepo: teamRepo,
 		userRepo: userRepo,
+		tx:       tx,
 		logger:   logger,
 	}
 }
 
-func (s *TeamService) CreateTeam(team *models.Team) error {
+func (s *TeamService) CreateTeam(ctx context.Context, team *models.Team) error {
 	s.logger.Info("creating team", "team_name", team.TeamName, "member_count", len(team.Members))
 
-	exists, err := s.teamRepo.TeamExists(team.TeamName)
+	if team.SelectionStrategy == "" {
+		team.SelectionStrategy = DefaultStrategy
+	}
+	if team.ReviewerCount == 0 {
+		team.ReviewerCount = DefaultReviewerCount
+	}
+	if err := validateReviewerCount(team.ReviewerCount); err != nil {
+		s.logger.Warn("invalid team reviewer count",
+			"team_name", team.TeamName, "reviewer_count", team.ReviewerCount)
+		return err
+	}
+
+	exists, err := s.teamRepo.TeamExists(ctx, team.TeamName)
 	if err != nil {
 		s.logger.Error("failed to check team existence", "team_name", team.TeamName, "error", err)
-		return fmt.Errorf("failed to check team existence: %w", err)
+		return repoError(err, nil, "failed to check team existence")
 	}
 	if exists {
 		s.logger.Warn("team already exists", "team_name", team.TeamName)
-		return errors.ErrTeamExists
+		return errors.ErrTeamExists.WithDetails("team_name", team.TeamName)
 	}
 
-	if err := s.teamRepo.CreateTeam(team.TeamName); err != nil {
-		s.logger.Error("failed to create team", "team_name", team.TeamName, "error", err)
-		return fmt.Errorf("failed to create team: %w", err)
-	}
+	// команда, её настройки, участники и запись аудита сохраняются вместе
+	err = s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Teams.CreateTeam(ctx, team.TeamName); err != nil {
+			s.logger.Error("failed to create team", "team_name", team.TeamName, "error", err)
+			// команду создали параллельным запросом после проверки
+			if stderrors.Is(err, repository.ErrConflict) {
+				return errors.WrapError(errors.ErrTeamExists.WithDetails("team_name", team.TeamName), err)
+			}
+			return repoError(err, nil, "failed to create team")
+		}
 
-	for _, member := range team.Members {
-		user := &models.User{
-			UserID:   member.UserID,
-			Username: member.Username,
-			TeamName: team.TeamName,
-			IsActive: member.IsActive,
+		if err := repos.Teams.UpdateTeamSettings(ctx, team.TeamName, &team.TeamSettings); err != nil {
+			s.logger.Error("failed to save team settings", "team_name", team.TeamName, "error", err)
+			return repoError(err, nil, "failed to save team settings")
 		}
-		if err := s.userRepo.CreateOrUpdateUser(user); err != nil {
-			s.logger.Error("failed to create/update team member",
-				"team_name", team.TeamName,
-				"user_id", member.UserID,
-				"error", err)
-			return fmt.Errorf("failed to create/update user %s: %w", member.UserID, err)
+
+		for _, member := range team.Members {
+			user := &models.User{
+				UserID:   member.UserID,
+				Username: member.Username,
+				TeamName: team.TeamName,
+				IsActive: member.IsActive,
+			}
+			if err := repos.Users.CreateOrUpdateUser(ctx, user); err != nil {
+				s.logger.Error("failed to create/update team member",
+					"team_name", team.TeamName,
+					"user_id", member.UserID,
+					"error", err)
+				return repoError(err, nil, fmt.Sprintf("failed to create/update user %s", member.UserID))
+			}
 		}
+
+		return writeAudit(ctx, repos.Audit, models.AuditTeamCreated, models.AuditEntityTeam, team.TeamName, nil, team)
+	})
+	if err != nil {
+		return err
 	}
 
 	s.logger.Info("successfully created team",
@@ -71,17 +105,261 @@ func (s *TeamService) CreateTeam(team *models.Team) error {
 	return nil
 }
 
-func (s *TeamService) GetTeam(teamName string) (*models.Team, error) {
+func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
 	s.logger.Debug("getting team", "team_name", teamName)
 
-	team, err := s.teamRepo.GetTeam(teamName)
+	team, err := s.teamRepo.GetTeam(ctx, teamName)
+	if err != nil {
+		s.logger.Error("failed to get team", "team_name", teamName, "error", err)
+		return nil, repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to get team")
+	}
+
+	settings, err := s.teamRepo.GetTeamSettings(ctx, teamName)
 	if err != nil {
-		s.logger.Error("team not found", "team_name", teamName, "error", err)
-		return nil, errors.WrapError(errors.ErrTeamNotFound, err)
+		s.logger.Error("failed to get team settings", "team_name", teamName, "error", err)
+		return nil, repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to get team")
 	}
+	team.TeamSettings = *settings
 
 	s.logger.Debug("successfully retrieved team",
 		"team_name", teamName,
 		"member_count", len(team.Members))
 	return team, nil
 }
+
+// ListTeams возвращает все команды с настройками и участниками
+func (s *TeamService) ListTeams(ctx context.Context) ([]models.Team, error) {
+	s.logger.Debug("listing teams")
+
+	teams, err := s.teamRepo.ListTeams(ctx)
+	if err != nil {
+		s.logger.Error("failed to list teams", "error", err)
+		return nil, repoError(err, nil, "failed to list teams")
+	}
+	return teams, nil
+}
+
+// ListMembers возвращает страницу участников команды и курсор следующей страницы
+func (s *TeamService) ListMembers(ctx context.Context, teamName string, page repository.Page) ([]models.TeamMember, string, error) {
+	s.logger.Debug("listing team members", "team_name", teamName)
+
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		s.logger.Error("failed to check team existence", "team_name", teamName, "error", err)
+		return nil, "", repoError(err, nil, "failed to check team existence")
+	}
+	if !exists {
+		return nil, "", errors.ErrTeamNotFound.WithDetails("team_name", teamName)
+	}
+
+	members, next, err := s.teamRepo.ListTeamMembers(ctx, teamName, page)
+	if err != nil {
+		s.logger.Error("failed to list team members", "team_name", teamName, "error", err)
+		return nil, "", repoError(err, nil, "failed to list team members")
+	}
+	return members, next, nil
+}
+
+// DeleteTeam удаляет команду без участников. Пользователи остаются в истории PR,
+// поэтому команду с участниками удалить нельзя: их нужно сначала перенести.
+// Участника, добавленного параллельно с удалением, не пропустит внешний ключ.
+func (s *TeamService) DeleteTeam(ctx context.Context, teamName string) error {
+	s.logger.Info("deleting team", "team_name", teamName)
+
+	notEmpty := errors.ErrTeamNotEmpty.WithDetails("team_name", teamName)
+	err := s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		members, err := repos.Teams.GetUsersByTeam(ctx, teamName)
+		if err != nil {
+			s.logger.Error("failed to get team members", "team_name", teamName, "error", err)
+			return repoError(err, nil, "failed to get team members")
+		}
+		if len(members) > 0 {
+			s.logger.Warn("cannot delete team with members",
+				"team_name", teamName, "member_count", len(members))
+			return notEmpty
+		}
+
+		settings, err := repos.Teams.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			s.logger.Error("failed to get team settings", "team_name", teamName, "error", err)
+			return repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to get team")
+		}
+
+		if err := repos.Teams.DeleteTeam(ctx, teamName); err != nil {
+			s.logger.Error("failed to delete team", "team_name", teamName, "error", err)
+			if stderrors.Is(err, repository.ErrConflict) {
+				return errors.WrapError(notEmpty, err)
+			}
+			return repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to delete team")
+		}
+
+		before := models.Team{TeamName: teamName, TeamSettings: *settings}
+		return writeAudit(ctx, repos.Audit, models.AuditTeamDeleted, models.AuditEntityTeam, teamName, before, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("successfully deleted team", "team_name", teamName)
+	return nil
+}
+
+// TeamSettingsUpdate частичное обновление настроек, nil поля не меняются
+type TeamSettingsUpdate struct {
+	SelectionStrategy       *string
+	ReviewerCount           *int
+	RequiredApprovals       *int
+	BlockOnChangesRequested *bool
+}
+
+func (s *TeamService) UpdateTeamSettings(ctx context.Context, teamName string, update TeamSettingsUpdate) (*models.TeamSettings, error) {
+	s.logger.Info("updating team settings", "team_name", teamName)
+
+	if update.ReviewerCount != nil {
+		if err := validateReviewerCount(*update.ReviewerCount); err != nil {
+			s.logger.Warn("invalid team reviewer count",
+				"team_name", teamName, "reviewer_count", *update.ReviewerCount)
+			return nil, err
+		}
+	}
+	if update.RequiredApprovals != nil {
+		if *update.RequiredApprovals < 0 || *update.RequiredApprovals > MaxReviewerCount {
+			s.logger.Warn("invalid required approvals",
+				"team_name", teamName, "required_approvals", *update.RequiredApprovals)
+			return nil, errors.WrapError(errors.ErrInvalidMergePolicy,
+				fmt.Errorf("required approvals must be between 0 and %d", MaxReviewerCount))
+		}
+	}
+
+	// настройки и запись аудита сохраняются вместе
+	var settings *models.TeamSettings
+	err := s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := repos.Teams.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			s.logger.Error("failed to get team", "team_name", teamName, "error", err)
+			return repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to get team")
+		}
+
+		after := *before
+		if update.SelectionStrategy != nil {
+			after.SelectionStrategy = *update.SelectionStrategy
+		}
+		if update.ReviewerCount != nil {
+			after.ReviewerCount = *update.ReviewerCount
+		}
+		if update.RequiredApprovals != nil {
+			after.RequiredApprovals = *update.RequiredApprovals
+		}
+		if update.BlockOnChangesRequested != nil {
+			after.BlockOnChangesRequested = *update.BlockOnChangesRequested
+		}
+
+		if err := repos.Teams.UpdateTeamSettings(ctx, teamName, &after); err != nil {
+			s.logger.Error("failed to update team settings", "team_name", teamName, "error", err)
+			return repoError(err, errors.ErrTeamNotFound.WithDetails("team_name", teamName), "failed to update team settings")
+		}
+
+		settings = &after
+		return writeAudit(ctx, repos.Audit, models.AuditTeamSettings, models.AuditEntityTeam, teamName, before, settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("successfully updated team settings",
+		"team_name", teamName,
+		"selection_strategy", settings.SelectionStrategy,
+		"reviewer_count", settings.ReviewerCount,
+		"required_approvals", settings.RequiredApprovals,
+		"block_on_changes_requested", settings.BlockOnChangesRequested)
+	return settings, nil
+}
+
+// GetLeads возвращает user_id лидов команды
+func (s *TeamService) GetLeads(ctx context.Context, teamName string) ([]string, error) {
+	if err := s.checkTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	leads, err := s.teamRepo.GetTeamLeads(ctx, teamName)
+	if err != nil {
+		s.logger.Error("failed to get team leads", "team_name", teamName, "error", err)
+		return nil, repoError(err, nil, "failed to get team leads")
+	}
+	return leads, nil
+}
+
+// leadsState лиды команды в журнале аудита
+type leadsState struct {
+	Leads []string `json:"leads"`
+}
+
+// AddLead назначает пользователя лидом команды. Лид не обязан состоять в команде.
+func (s *TeamService) AddLead(ctx context.Context, teamName, userID string) error {
+	s.logger.Info("adding team lead", "team_name", teamName, "user_id", userID)
+
+	if err := s.checkTeamExists(ctx, teamName); err != nil {
+		return err
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		s.logger.Error("failed to get user", "user_id", userID, "error", err)
+		return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+	}
+
+	return s.changeLeads(ctx, teamName, models.AuditTeamLeadAdded, func(teams repository.TeamRepository) error {
+		if err := teams.AddTeamLead(ctx, teamName, userID); err != nil {
+			s.logger.Error("failed to add team lead", "team_name", teamName, "user_id", userID, "error", err)
+			return repoError(err, nil, "failed to add team lead")
+		}
+		return nil
+	})
+}
+
+// RemoveLead снимает пользователя с роли лида команды
+func (s *TeamService) RemoveLead(ctx context.Context, teamName, userID string) error {
+	s.logger.Info("removing team lead", "team_name", teamName, "user_id", userID)
+
+	return s.changeLeads(ctx, teamName, models.AuditTeamLeadRemoved, func(teams repository.TeamRepository) error {
+		if err := teams.RemoveTeamLead(ctx, teamName, userID); err != nil {
+			s.logger.Error("failed to remove team lead", "team_name", teamName, "user_id", userID, "error", err)
+			return repoError(err, errors.ErrLeadNotFound.WithDetails("team_name", teamName).WithDetails("user_id", userID),
+				"failed to remove team lead")
+		}
+		return nil
+	})
+}
+
+// changeLeads меняет лидов команды в транзакции и пишет в журнал лидов до и после
+func (s *TeamService) changeLeads(ctx context.Context, teamName, operation string, change func(teams repository.TeamRepository) error) error {
+	return s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := repos.Teams.GetTeamLeads(ctx, teamName)
+		if err != nil {
+			s.logger.Error("failed to get team leads", "team_name", teamName, "error", err)
+			return repoError(err, nil, "failed to get team leads")
+		}
+
+		if err := change(repos.Teams); err != nil {
+			return err
+		}
+
+		after, err := repos.Teams.GetTeamLeads(ctx, teamName)
+		if err != nil {
+			s.logger.Error("failed to get team leads", "team_name", teamName, "error", err)
+			return repoError(err, nil, "failed to get team leads")
+		}
+		return writeAudit(ctx, repos.Audit, operation, models.AuditEntityTeam, teamName,
+			leadsState{Leads: before}, leadsState{Leads: after})
+	})
+}
+
+func (s *TeamService) checkTeamExists(ctx context.Context, teamName string) error {
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		s.logger.Error("failed to check team existence", "team_name", teamName, "error", err)
+		return repoError(err, nil, "failed to check team existence")
+	}
+	if !exists {
+		return errors.ErrTeamNotFound.WithDetails("team_name", teamName)
+	}
+	return nil
+}
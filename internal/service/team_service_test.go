@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staleMembersTx транзакция, в которой проверка участников не видит
+// пользователя, добавленного параллельным запросом
+type staleMembersTx struct {
+	repository.TxManager
+}
+
+type staleMembersTeams struct {
+	repository.TeamRepository
+}
+
+func (staleMembersTeams) GetUsersByTeam(context.Context, string) ([]models.User, error) {
+	return nil, nil
+}
+
+func (m staleMembersTx) WithinTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return m.TxManager.WithinTx(ctx, func(repos repository.Repositories) error {
+		repos.Teams = staleMembersTeams{TeamRepository: repos.Teams}
+		return fn(repos)
+	})
+}
+
+func TestTeamService_DeleteTeam(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	teams := memory.NewTeamRepository(store)
+	users := memory.NewUserRepository(store)
+	s := NewTeamService(teams, users, memory.NewTxManager(store), nil)
+
+	require.NoError(t, teams.CreateTeam(ctx, "backend"))
+	require.NoError(t, users.CreateOrUpdateUser(ctx, &models.User{UserID: "u1", TeamName: "backend", IsActive: true}))
+
+	err := s.DeleteTeam(ctx, "backend")
+	assert.True(t, errors.Is(err, errors.ErrTeamNotEmpty))
+
+	// участника добавили после проверки: удаление отклоняет хранилище
+	racy := NewTeamService(teams, users, staleMembersTx{TxManager: memory.NewTxManager(store)}, nil)
+	err = racy.DeleteTeam(ctx, "backend")
+	assert.True(t, errors.Is(err, errors.ErrTeamNotEmpty))
+	exists, err := teams.TeamExists(ctx, "backend")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, teams.CreateTeam(ctx, "empty"))
+	require.NoError(t, s.DeleteTeam(ctx, "empty"))
+	err = s.DeleteTeam(ctx, "empty")
+	assert.True(t, errors.Is(err, errors.ErrTeamNotFound))
+}
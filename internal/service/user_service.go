@@ -1,9 +1,9 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"sync"
 	"time"
 
 	"ReviewAssigner/internal/errors"
@@ -16,6 +16,7 @@ type UserService struct {
 	teamRepo repository.TeamRepository
 	prRepo   repository.PRRepository
 	revSrv   *ReviewService
+	tx       repository.TxManager
 	logger   *slog.Logger
 }
 
@@ -24,6 +25,7 @@ func NewUserService(
 	teamRepo repository.TeamRepository,
 	prRepo repository.PRRepository,
 	revSrv *ReviewService,
+	tx repository.TxManager,
 	logger *slog.Logger,
 ) *UserService {
 	if logger == nil {
@@ -35,25 +37,52 @@ func NewUserService(
 		teamRepo: teamRepo,
 		prRepo:   prRepo,
 		revSrv:   revSrv,
+		tx:       tx,
 		logger:   logger,
 	}
 }
 
-func (s *UserService) SetUserActive(userID string, isActive bool) (*models.User, error) {
+func (s *UserService) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	s.logger.Debug("getting user", "user_id", userID)
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.logger.Error("failed to get user", "user_id", userID, "error", err)
+		return nil, repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+	}
+	return user, nil
+}
+
+func (s *UserService) SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
 	s.logger.Info("setting user active status",
 		"user_id", userID, "is_active", isActive)
 
-	if err := s.userRepo.SetUserActive(userID, isActive); err != nil {
-		s.logger.Error("failed to set user active status",
-			"user_id", userID, "is_active", isActive, "error", err)
-		return nil, errors.WrapError(errors.ErrUserNotFound, err)
-	}
+	var user *models.User
+	err := s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		before, err := repos.Users.GetUserByID(ctx, userID)
+		if err != nil {
+			s.logger.Error("failed to get user before status change",
+				"user_id", userID, "error", err)
+			return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+		}
 
-	user, err := s.userRepo.GetUserByID(userID)
+		if err := repos.Users.SetUserActive(ctx, userID, isActive); err != nil {
+			s.logger.Error("failed to set user active status",
+				"user_id", userID, "is_active", isActive, "error", err)
+			return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to set user active status")
+		}
+
+		user, err = repos.Users.GetUserByID(ctx, userID)
+		if err != nil {
+			s.logger.Error("failed to get user after status change",
+				"user_id", userID, "error", err)
+			return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+		}
+
+		return writeAudit(ctx, repos.Audit, models.AuditUserActiveSet, models.AuditEntityUser, userID, before, user)
+	})
 	if err != nil {
-		s.logger.Error("failed to get user after status change",
-			"user_id", userID, "error", err)
-		return nil, errors.WrapError(errors.ErrUserNotFound, err)
+		return nil, err
 	}
 
 	s.logger.Info("successfully changed user active status",
@@ -61,26 +90,35 @@ func (s *UserService) SetUserActive(userID string, isActive bool) (*models.User,
 	return user, nil
 }
 
-func (s *UserService) GetAssignedPRs(userID string) ([]models.PullRequestShort, error) {
+// GetAssignedPRs возвращает страницу открытых PR, где пользователь — активный ревьюер,
+// и курсор следующей страницы
+func (s *UserService) GetAssignedPRs(ctx context.Context, userID string, page repository.Page) ([]models.PullRequestShort, string, error) {
 	s.logger.Debug("getting assigned PRs for user", "user_id", userID)
 
-	_, err := s.userRepo.GetUserByID(userID)
+	_, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		s.logger.Error("user not found", "user_id", userID, "error", err)
-		return nil, errors.WrapError(errors.ErrUserNotFound, err)
+		s.logger.Error("failed to get user", "user_id", userID, "error", err)
+		return nil, "", repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
 	}
 
-	prs, err := s.prRepo.GetAssignedPRs(userID)
+	prs, next, err := s.prRepo.ListPRs(ctx, repository.PRFilter{
+		Status:     models.PRStatusOpen,
+		ReviewerID: userID,
+	}, page)
 	if err != nil {
 		s.logger.Error("failed to get assigned PRs", "user_id", userID, "error", err)
-		return nil, fmt.Errorf("failed to get assigned PRs: %w", err)
+		return nil, "", repoError(err, nil, "failed to get assigned PRs")
 	}
 
 	s.logger.Debug("retrieved assigned PRs", "user_id", userID, "count", len(prs))
-	return prs, nil
+	return prs, next, nil
 }
 
-func (s *UserService) BulkDeactivateUsers(teamName string, userIDs []string) (map[string]Reassignment, error) {
+// BulkDeactivateUsers деактивирует пользователей и переназначает их открытые PR
+// одной транзакцией: при ошибке БД или неизвестном пользователе ничего не меняется.
+// PR, для которого нет замены в команде, остаётся за прежним ревьюером и
+// отмечается в результате как неуспешный.
+func (s *UserService) BulkDeactivateUsers(ctx context.Context, teamName string, userIDs []string) (map[string]Reassignment, error) {
 	start := time.Now()
 	s.logger.Info("starting bulk deactivation",
 		"team_name", teamName,
@@ -88,91 +126,106 @@ func (s *UserService) BulkDeactivateUsers(teamName string, userIDs []string) (ma
 		"user_ids", userIDs)
 
 	result := make(map[string]Reassignment)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
 
-	// деактивируем пользователей
-	for _, userID := range userIDs {
-		if err := s.userRepo.SetUserActive(userID, false); err != nil {
-			s.logger.Warn("failed to deactivate user",
-				"user_id", userID, "error", err)
-			mu.Lock()
-			result[userID] = Reassignment{
-				OldReviewer: userID,
-				Success:     false,
-				PRID:        "",
-				NewReviewer: "",
+	err := s.tx.WithinTx(ctx, func(repos repository.Repositories) error {
+		revSrv := s.revSrv.withRepos(repos)
+
+		// деактивируем пользователей
+		for _, userID := range userIDs {
+			before, err := repos.Users.GetUserByID(ctx, userID)
+			if err != nil {
+				s.logger.Warn("failed to get user for deactivation",
+					"user_id", userID, "error", err)
+				return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+			}
+			// права проверены на команду из запроса, чужих пользователей не трогаем
+			if before.TeamName != teamName {
+				s.logger.Warn("user is not a member of team",
+					"user_id", userID, "team_name", teamName, "user_team", before.TeamName)
+				return errors.ErrUserNotFound.WithDetails("user_id", userID).WithDetails("team_name", teamName)
+			}
+			if err := repos.Users.SetUserActive(ctx, userID, false); err != nil {
+				s.logger.Warn("failed to deactivate user",
+					"user_id", userID, "error", err)
+				return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to deactivate user")
+			}
+			after, err := repos.Users.GetUserByID(ctx, userID)
+			if err != nil {
+				return repoError(err, errors.ErrUserNotFound.WithDetails("user_id", userID), "failed to get user")
+			}
+			if err := writeAudit(ctx, repos.Audit, models.AuditUsersDeactivated, models.AuditEntityUser, userID, before, after); err != nil {
+				return err
 			}
-			mu.Unlock()
-		} else {
 			s.logger.Debug("successfully deactivated user", "user_id", userID)
-			mu.Lock()
 			result[userID] = Reassignment{
 				OldReviewer: userID,
 				Success:     true,
 			}
-			mu.Unlock()
 		}
-	}
-
-	// для каждого пользователя получаем открытые PR и пытаемся заменить ревьюверов
-	for _, userID := range userIDs {
-		wg.Add(1)
-		go func(uid string) {
-			defer wg.Done()
 
-			prs, err := s.prRepo.GetAssignedPRs(uid)
+		// для каждого пользователя получаем открытые PR и заменяем ревьюверов
+		for _, userID := range userIDs {
+			prs, err := repos.PRs.GetAssignedPRs(ctx, userID)
 			if err != nil {
-				s.logger.Warn("failed to get assigned PRs for user",
-					"user_id", uid, "error", err)
-				mu.Lock()
-				result[uid] = Reassignment{
-					OldReviewer: uid,
-					Success:     false,
-					PRID:        "",
-					NewReviewer: "",
-				}
-				mu.Unlock()
-				return
+				s.logger.Error("failed to get assigned PRs for user",
+					"user_id", userID, "error", err)
+				return repoError(err, nil, fmt.Sprintf("failed to get assigned PRs for user %s", userID))
 			}
 
 			s.logger.Debug("found PRs assigned to user",
-				"user_id", uid, "pr_count", len(prs))
+				"user_id", userID, "pr_count", len(prs))
 
 			for _, pr := range prs {
-				newReviewer, err := s.revSrv.ReplaceReviewer(pr.PullRequestID, uid)
-				mu.Lock()
+				resultKey := userID + ":" + pr.PullRequestID
+
+				reviewersBefore, err := repos.PRs.GetPRReviewers(ctx, pr.PullRequestID)
 				if err != nil {
-					s.logger.Error("failed to replace reviewer in PR",
+					return repoError(err, nil, "failed to get PR reviewers")
+				}
+
+				newReviewer, err := revSrv.ReplaceReviewer(ctx, pr.PullRequestID, userID)
+				if errors.Is(err, errors.ErrNoCandidate) {
+					s.logger.Warn("no replacement candidate for PR",
 						"pr_id", pr.PullRequestID,
-						"old_reviewer_id", uid,
-						"error", err)
-					resultKey := uid + ":" + pr.PullRequestID
+						"old_reviewer_id", userID)
 					result[resultKey] = Reassignment{
-						OldReviewer: uid,
+						OldReviewer: userID,
 						PRID:        pr.PullRequestID,
 						Success:     false,
 					}
-				} else {
-					s.logger.Info("successfully replaced reviewer in PR",
+					continue
+				}
+				if err != nil {
+					s.logger.Error("failed to replace reviewer in PR",
 						"pr_id", pr.PullRequestID,
-						"old_reviewer_id", uid,
-						"new_reviewer_id", newReviewer)
-					resultKey := uid + ":" + pr.PullRequestID
-					result[resultKey] = Reassignment{
-						OldReviewer: uid,
-						PRID:        pr.PullRequestID,
-						NewReviewer: newReviewer,
-						Success:     true,
-					}
+						"old_reviewer_id", userID,
+						"error", err)
+					return fmt.Errorf("failed to replace reviewer %s in PR %s: %w", userID, pr.PullRequestID, err)
+				}
+				if err := auditReviewerReplaced(ctx, repos, models.AuditUsersDeactivated, pr.PullRequestID, reviewersBefore); err != nil {
+					return err
+				}
+
+				s.logger.Info("successfully replaced reviewer in PR",
+					"pr_id", pr.PullRequestID,
+					"old_reviewer_id", userID,
+					"new_reviewer_id", newReviewer)
+				result[resultKey] = Reassignment{
+					OldReviewer: userID,
+					PRID:        pr.PullRequestID,
+					NewReviewer: newReviewer,
+					Success:     true,
 				}
-				mu.Unlock()
 			}
-		}(userID)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("bulk deactivation rolled back",
+			"team_name", teamName, "error", err)
+		return nil, err
 	}
 
-	wg.Wait()
-
 	duration := time.Since(start)
 	s.logger.Info("completed bulk deactivation",
 		"team_name", teamName,
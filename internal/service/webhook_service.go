@@ -0,0 +1,421 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+)
+
+// параметры доставки вебхуков
+const (
+	DefaultWebhookMaxAttempts  = 5
+	DefaultWebhookBackoff      = time.Second
+	DefaultWebhookPollInterval = time.Second
+	DefaultDeliveriesLimit     = 50
+	MaxDeliveriesLimit         = 500
+
+	webhookTimeout = 10 * time.Second
+	// webhookBatchSize сколько доставок отправляется за один опрос
+	webhookBatchSize = 20
+	// webhookLease на сколько откладывается попытка забранной доставки: если
+	// процесс упал, не сохранив результат, доставку повторит следующий опрос
+	webhookLease = time.Minute
+	// webhookMaxBackoff предел задержки между попытками
+	webhookMaxBackoff = time.Hour
+)
+
+// заголовки исходящих вебхуков
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// webhookEnvelope тело исходящего вебхука
+type webhookEnvelope struct {
+	ID         int64           `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type WebhookService struct {
+	webhookRepo  repository.WebhookRepository
+	client       *http.Client
+	maxAttempts  int
+	backoff      time.Duration
+	pollInterval time.Duration
+	logger       *slog.Logger
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, logger *slog.Logger) *WebhookService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		client:       &http.Client{Timeout: webhookTimeout},
+		maxAttempts:  DefaultWebhookMaxAttempts,
+		backoff:      DefaultWebhookBackoff,
+		pollInterval: DefaultWebhookPollInterval,
+		logger:       logger,
+	}
+}
+
+// SetRetryPolicy задаёт число попыток и начальную задержку между ними.
+// Задержка удваивается после каждой неудачной попытки.
+func (s *WebhookService) SetRetryPolicy(maxAttempts int, backoff time.Duration) {
+	if maxAttempts > 0 {
+		s.maxAttempts = maxAttempts
+	}
+	if backoff > 0 {
+		s.backoff = backoff
+	}
+}
+
+// SetPollInterval задаёт период опроса очереди доставок
+func (s *WebhookService) SetPollInterval(interval time.Duration) {
+	if interval > 0 {
+		s.pollInterval = interval
+	}
+}
+
+// CreateSubscription создаёт подписку. Пустой secret генерируется автоматически
+// и возвращается вызывающему только в ответе на создание.
+func (s *WebhookService) CreateSubscription(ctx context.Context, rawURL, secret string, events []string) (*models.WebhookSubscription, error) {
+	s.logger.Info("creating webhook subscription", "url", rawURL, "events", events)
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		s.logger.Warn("invalid webhook url", "url", rawURL)
+		return nil, errors.NewError(errors.ErrInvalidWebhook.Code, "Webhook url must be an absolute http(s) URL")
+	}
+
+	for _, event := range events {
+		if !isWebhookEventType(event) {
+			s.logger.Warn("unknown webhook event", "event", event)
+			return nil, errors.NewError(errors.ErrInvalidWebhook.Code,
+				fmt.Sprintf("Unknown webhook event: %s", event))
+		}
+	}
+
+	if secret == "" {
+		secret, err = generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	if events == nil {
+		events = []string{}
+	}
+	sub := &models.WebhookSubscription{
+		URL:      rawURL,
+		Secret:   secret,
+		Events:   events,
+		IsActive: true,
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		s.logger.Error("failed to create webhook subscription", "url", rawURL, "error", err)
+		return nil, repoError(err, nil, "failed to create webhook subscription")
+	}
+
+	s.logger.Info("successfully created webhook subscription", "subscription_id", sub.ID)
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		s.logger.Error("failed to get webhook subscriptions", "error", err)
+		return nil, repoError(err, nil, "failed to get webhook subscriptions")
+	}
+	return subs, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int64) error {
+	s.logger.Info("deleting webhook subscription", "subscription_id", id)
+
+	if err := s.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+		s.logger.Warn("failed to delete webhook subscription", "subscription_id", id, "error", err)
+		return repoError(err, errors.ErrWebhookNotFound.WithDetails("subscription_id", id), "failed to delete webhook subscription")
+	}
+	return nil
+}
+
+// GetDeliveries возвращает журнал доставок подписки
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.GetSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, repoError(err, errors.ErrWebhookNotFound.WithDetails("subscription_id", subscriptionID), "failed to get webhook subscription")
+	}
+
+	if limit <= 0 {
+		limit = DefaultDeliveriesLimit
+	}
+	if limit > MaxDeliveriesLimit {
+		limit = MaxDeliveriesLimit
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		s.logger.Error("failed to get webhook deliveries", "subscription_id", subscriptionID, "error", err)
+		return nil, repoError(err, nil, "failed to get webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+// Name имя sink'а для диспетчера outbox
+func (s *WebhookService) Name() string {
+	return "webhooks"
+}
+
+// Handle ставит событие из outbox в очередь доставок всем подходящим подпискам.
+// Ошибка возвращается, только если доставки не удалось записать, — тогда
+// диспетчер повторит событие, а уже записанные доставки пропускаются.
+// Отправляет доставки Run.
+func (s *WebhookService) Handle(ctx context.Context, event models.Event) error {
+	subs, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		s.logger.Error("failed to get webhook subscriptions for event",
+			"event_id", event.ID, "event", event.EventType, "error", err)
+		return fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+
+	occurredAt := time.Now().UTC()
+	if event.CreatedAt != nil {
+		occurredAt = event.CreatedAt.UTC()
+	}
+	payload, err := json.Marshal(webhookEnvelope{
+		ID:         event.ID,
+		Event:      event.EventType,
+		OccurredAt: occurredAt,
+		Data:       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		s.logger.Error("failed to marshal webhook payload", "event_id", event.ID, "error", err)
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	eventID := event.ID
+	eventType := event.EventType
+	now := time.Now()
+
+	for _, sub := range subs {
+		if !sub.Matches(eventType) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        &eventID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  &now,
+		}
+		err := s.webhookRepo.CreateDelivery(ctx, delivery)
+		if stderrors.Is(err, repository.ErrConflict) {
+			s.logger.Debug("webhook delivery already queued",
+				"subscription_id", sub.ID, "event_id", eventID)
+			continue
+		}
+		if err != nil {
+			s.logger.Error("failed to create webhook delivery",
+				"subscription_id", sub.ID, "event", eventType, "error", err)
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+// Run отправляет доставки из очереди до отмены ctx
+func (s *WebhookService) Run(ctx context.Context) {
+	s.logger.Info("webhook delivery worker started", "interval", s.pollInterval)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverDue(ctx); err != nil {
+			s.logger.Error("failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue выполняет по одной попытке для доставок, чья очередь наступила,
+// и возвращает их число. Доставки пачки отправляются параллельно.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, webhookBatchSize, time.Now().Add(webhookLease))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	subs, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		// доставки вернутся в очередь, когда истечёт аренда
+		return 0, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	byID := make(map[int64]*models.WebhookSubscription, len(subs))
+	for i := range subs {
+		byID[subs[i].ID] = &subs[i]
+	}
+
+	// начатые попытки доводятся до конца и при остановке
+	attemptCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		sub, ok := byID[delivery.SubscriptionID]
+		if !ok {
+			// подписку удалили вместе с доставками
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.attempt(attemptCtx, sub, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// attempt выполняет одну попытку доставки и планирует следующую
+// с экспоненциальной задержкой
+func (s *WebhookService) attempt(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	code, err := s.send(ctx, sub, delivery)
+
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		s.saveDelivery(ctx, delivery)
+
+		s.logger.Info("webhook delivered",
+			"delivery_id", delivery.ID, "subscription_id", sub.ID, "attempts", delivery.Attempts)
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= s.maxAttempts {
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		s.saveDelivery(ctx, delivery)
+
+		s.logger.Error("webhook delivery failed",
+			"delivery_id", delivery.ID, "subscription_id", sub.ID, "attempts", delivery.Attempts, "error", err)
+		return
+	}
+
+	next := time.Now().Add(s.retryDelay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+	s.saveDelivery(ctx, delivery)
+
+	s.logger.Warn("webhook delivery attempt failed",
+		"delivery_id", delivery.ID,
+		"subscription_id", sub.ID,
+		"attempt", delivery.Attempts,
+		"next_attempt_at", next,
+		"error", err)
+}
+
+// retryDelay задержка после attempts неудачных попыток: backoff, дальше удваивается
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	delay := s.backoff
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxBackoff)
+}
+
+// send выполняет одну попытку доставки, успехом считается ответ 2xx
+func (s *WebhookService) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *WebhookService) saveDelivery(ctx context.Context, delivery *models.WebhookDelivery) {
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		s.logger.Error("failed to save webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// SignWebhookPayload возвращает подпись тела вебхука: sha256=<hex HMAC-SHA256>
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, known := range models.EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
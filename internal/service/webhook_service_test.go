@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/errors"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/repository"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWebhookFixture сервис с подписками в памяти
+func newWebhookFixture(t *testing.T, subs ...models.WebhookSubscription) (*WebhookService, *memory.WebhookRepository, []models.WebhookSubscription) {
+	repo := memory.NewWebhookRepository(memory.NewStore())
+	for i := range subs {
+		subs[i].IsActive = true
+		require.NoError(t, repo.CreateSubscription(context.Background(), &subs[i]))
+	}
+	s := NewWebhookService(repo, nil)
+	return s, repo, subs
+}
+
+// deliverAll опрашивает очередь, пока в ней есть доставки
+func deliverAll(t *testing.T, s *WebhookService) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		_, err := s.DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		if pendingDeliveries(t, s.webhookRepo) == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("webhook deliveries are still pending")
+}
+
+func pendingDeliveries(t *testing.T, repo repository.WebhookRepository) int {
+	subs, err := repo.GetSubscriptions(context.Background())
+	require.NoError(t, err)
+	pending := 0
+	for _, sub := range subs {
+		deliveries, err := repo.GetDeliveries(context.Background(), sub.ID, MaxDeliveriesLimit)
+		require.NoError(t, err)
+		for _, delivery := range deliveries {
+			if delivery.Status == models.DeliveryStatusPending {
+				pending++
+			}
+		}
+	}
+	return pending
+}
+
+func TestWebhookService_HandleSignsAndDelivers(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotSignature string
+		gotEvent     string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(WebhookSignatureHeader)
+		gotEvent = r.Header.Get(WebhookEventHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s, repo, subs := newWebhookFixture(t,
+		models.WebhookSubscription{URL: server.URL, Secret: "s3cret", Events: []string{models.EventReviewerAssigned}},
+		models.WebhookSubscription{URL: server.URL, Secret: "other", Events: []string{models.EventPRMerged}},
+	)
+
+	err := s.Handle(context.Background(), models.Event{
+		ID:        11,
+		EventType: models.EventReviewerAssigned,
+		Payload:   `{"pull_request_id":"pr-1","reviewer_ids":["u2"]}`,
+	})
+	require.NoError(t, err)
+	deliverAll(t, s)
+
+	deliveries, err := repo.GetDeliveries(context.Background(), subs[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+	require.NotNil(t, deliveries[0].ResponseCode)
+	assert.Equal(t, http.StatusOK, *deliveries[0].ResponseCode)
+
+	other, err := repo.GetDeliveries(context.Background(), subs[1].ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	assert.Equal(t, models.EventReviewerAssigned, gotEvent)
+	assert.Equal(t, SignWebhookPayload("s3cret", gotBody), gotSignature)
+
+	var envelope struct {
+		ID    int64                       `json:"id"`
+		Event string                      `json:"event"`
+		Data  models.ReviewerAssignedData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(gotBody, &envelope))
+	assert.Equal(t, int64(11), envelope.ID)
+	assert.Equal(t, models.EventReviewerAssigned, envelope.Event)
+	assert.Equal(t, []string{"u2"}, envelope.Data.ReviewerIDs)
+}
+
+func TestWebhookService_RetriesWithBackoff(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s, repo, subs := newWebhookFixture(t, models.WebhookSubscription{URL: server.URL, Secret: "s"})
+	s.SetRetryPolicy(5, time.Millisecond)
+
+	require.NoError(t, s.Handle(context.Background(), models.Event{ID: 1, EventType: models.EventPRMerged, Payload: `{}`}))
+
+	// одна попытка за опрос, следующая назначается в очереди
+	_, err := s.DeliverDue(context.Background())
+	require.NoError(t, err)
+	deliveries, err := repo.GetDeliveries(context.Background(), subs[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].NextAttemptAt)
+
+	deliverAll(t, s)
+
+	deliveries, err = repo.GetDeliveries(context.Background(), subs[0].ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookService_RetryDelay(t *testing.T) {
+	s := NewWebhookService(nil, nil)
+	s.SetRetryPolicy(30, time.Second)
+
+	assert.Equal(t, time.Second, s.retryDelay(1))
+	assert.Equal(t, 4*time.Second, s.retryDelay(3))
+	assert.Equal(t, webhookMaxBackoff, s.retryDelay(25))
+}
+
+func TestWebhookService_FailsAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s, repo, subs := newWebhookFixture(t, models.WebhookSubscription{URL: server.URL, Secret: "s"})
+	s.SetRetryPolicy(2, time.Millisecond)
+
+	require.NoError(t, s.Handle(context.Background(), models.Event{ID: 1, EventType: models.EventReviewerReplaced, Payload: `{}`}))
+	deliverAll(t, s)
+
+	deliveries, err := repo.GetDeliveries(context.Background(), subs[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryStatusFailed, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+	assert.Contains(t, deliveries[0].LastError, "500")
+}
+
+func TestWebhookService_PendingSurvivesRestart(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s, repo, subs := newWebhookFixture(t, models.WebhookSubscription{URL: server.URL, Secret: "s"})
+	require.NoError(t, s.Handle(context.Background(), models.Event{ID: 7, EventType: models.EventPRCreated, Payload: `{}`}))
+
+	// процесс остановился до отправки: доставку отправит новый экземпляр
+	restarted := NewWebhookService(repo, nil)
+	deliverAll(t, restarted)
+
+	deliveries, err := repo.GetDeliveries(context.Background(), subs[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+// flakyWebhookRepo отказывает в записи первой доставки подписке failFor
+type flakyWebhookRepo struct {
+	repository.WebhookRepository
+	failFor int64
+	failed  bool
+}
+
+func (r *flakyWebhookRepo) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.SubscriptionID == r.failFor && !r.failed {
+		r.failed = true
+		return repository.ErrUnavailable
+	}
+	return r.WebhookRepository.CreateDelivery(ctx, delivery)
+}
+
+func TestWebhookService_HandleRetryAfterPartialFailure(t *testing.T) {
+	_, repo, subs := newWebhookFixture(t,
+		models.WebhookSubscription{URL: "https://example.com/a", Secret: "a"},
+		models.WebhookSubscription{URL: "https://example.com/b", Secret: "b"},
+	)
+	s := NewWebhookService(&flakyWebhookRepo{WebhookRepository: repo, failFor: subs[1].ID}, nil)
+	event := models.Event{ID: 3, EventType: models.EventPRMerged, Payload: `{}`}
+
+	require.Error(t, s.Handle(context.Background(), event))
+	// диспетчер повторяет событие: первой подписке вторая доставка не создаётся
+	require.NoError(t, s.Handle(context.Background(), event))
+
+	for _, sub := range subs {
+		deliveries, err := repo.GetDeliveries(context.Background(), sub.ID, 10)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 1, "подписка %d", sub.ID)
+	}
+}
+
+func TestWebhookService_CreateSubscriptionValidation(t *testing.T) {
+	s, _, _ := newWebhookFixture(t)
+
+	_, err := s.CreateSubscription(context.Background(), "ftp://example.com", "", nil)
+	assert.True(t, errors.Is(err, errors.ErrInvalidWebhook))
+
+	_, err = s.CreateSubscription(context.Background(), "https://example.com/hook", "", []string{"pr.deleted"})
+	assert.True(t, errors.Is(err, errors.ErrInvalidWebhook))
+
+	sub, err := s.CreateSubscription(context.Background(), "https://example.com/hook", "", nil)
+	require.NoError(t, err)
+	assert.Len(t, sub.Secret, 64)
+	assert.Empty(t, sub.Events)
+}
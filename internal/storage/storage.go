@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+
+	"ReviewAssigner/internal/config"
+	"ReviewAssigner/internal/database"
+	"ReviewAssigner/internal/repository"
+	"ReviewAssigner/internal/repository/memory"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Storage репозитории выбранного хранилища
+type Storage struct {
+	Users    repository.UserRepository
+	Teams    repository.TeamRepository
+	PRs      repository.PRRepository
+	Webhooks repository.WebhookRepository
+	Events   repository.EventRepository
+	Tx       repository.TxManager
+	// Idempotency сохранённые ответы для заголовка Idempotency-Key
+	Idempotency repository.IdempotencyRepository
+	// Tokens API-токены для авторизации запросов
+	Tokens repository.TokenRepository
+	// Audit журнал аудита изменяющих операций
+	Audit repository.AuditRepository
+
+	db *sqlx.DB
+}
+
+// Open открывает хранилище по cfg.Storage: postgres, sqlite или memory
+func Open(cfg *config.Config) (*Storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		log.Println("Using in-memory storage, data will be lost on restart")
+		store := memory.NewStore()
+		memory.Seed(store)
+
+		return &Storage{
+			Users:    memory.NewUserRepository(store),
+			Teams:    memory.NewTeamRepository(store),
+			PRs:      memory.NewPRRepository(store),
+			Webhooks: memory.NewWebhookRepository(store),
+			Events:   memory.NewEventRepository(store),
+			Tx:       memory.NewTxManager(store),
+
+			Idempotency: memory.NewIdempotencyRepository(store),
+			Tokens:      memory.NewTokenRepository(store),
+			Audit:       memory.NewAuditRepository(store),
+		}, nil
+
+	case config.StoragePostgres:
+		db, err := database.NewPostgresDB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		return newSQLStorage(db), nil
+
+	case config.StorageSQLite:
+		db, err := database.NewSQLiteDB(cfg.SQLitePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		return newSQLStorage(db), nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
+
+// sqlx-репозитории общие для postgres и sqlite
+func newSQLStorage(db *sqlx.DB) *Storage {
+	return &Storage{
+		Users:    repository.NewUserRepository(db),
+		Teams:    repository.NewTeamRepository(db),
+		PRs:      repository.NewPRRepository(db),
+		Webhooks: repository.NewWebhookRepository(db),
+		Events:   repository.NewEventRepository(db),
+		Tx:       repository.NewTxManager(db),
+
+		Idempotency: repository.NewIdempotencyRepository(db),
+		Tokens:      repository.NewTokenRepository(db),
+		Audit:       repository.NewAuditRepository(db),
+		db:          db,
+	}
+}
+
+// Close закрывает соединение с базой, если оно есть
+func (s *Storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
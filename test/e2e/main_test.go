@@ -1,11 +1,20 @@
 package e2e
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"ReviewAssigner/internal/config"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -181,12 +190,14 @@ func (suite *E2ETestSuite) TestErrorScenarios() {
 
 	var errorResp struct {
 		Error struct {
-			Code    string `json:"code"`
-			Message string `json:"message"`
+			Code    string                 `json:"code"`
+			Message string                 `json:"message"`
+			Details map[string]interface{} `json:"details"`
 		} `json:"error"`
 	}
 	suite.parseResponse(resp, &errorResp)
 	assert.Equal(suite.T(), "NOT_FOUND", errorResp.Error.Code)
+	assert.Equal(suite.T(), "non-existent-user", errorResp.Error.Details["author_id"])
 
 	duplicatePRReq := map[string]interface{}{
 		"pull_request_id":   "pr-1005",
@@ -202,6 +213,62 @@ func (suite *E2ETestSuite) TestErrorScenarios() {
 	assert.Equal(suite.T(), "PR_EXISTS", errorResp.Error.Code)
 }
 
+func (suite *E2ETestSuite) TestProblemDetails() {
+	problemAccept := map[string]string{"Accept": "application/problem+json"}
+
+	resp, err := suite.makeRequestWithHeaders("POST", "/pullRequest/merge",
+		map[string]interface{}{"pull_request_id": "pr-missing"}, problemAccept)
+	suite.NoError(err)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	assert.Equal(suite.T(), "application/problem+json", resp.Header.Get("Content-Type"))
+
+	var problem struct {
+		Type     string                 `json:"type"`
+		Title    string                 `json:"title"`
+		Status   int                    `json:"status"`
+		Detail   string                 `json:"detail"`
+		Instance string                 `json:"instance"`
+		Code     string                 `json:"code"`
+		Details  map[string]interface{} `json:"details"`
+	}
+	suite.parseResponse(resp, &problem)
+	assert.Equal(suite.T(), "/problems/not-found", problem.Type)
+	assert.Equal(suite.T(), "Not Found", problem.Title)
+	assert.Equal(suite.T(), http.StatusNotFound, problem.Status)
+	assert.Equal(suite.T(), "PR not found", problem.Detail)
+	assert.Equal(suite.T(), "/pullRequest/merge", problem.Instance)
+	assert.Equal(suite.T(), "NOT_FOUND", problem.Code)
+	assert.Equal(suite.T(), "pr-missing", problem.Details["pull_request_id"])
+
+	// ошибки валидации перечисляются по полям
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/create",
+		map[string]interface{}{"pull_request_name": "No id"}, problemAccept)
+	suite.NoError(err)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	var validation struct {
+		Code          string `json:"code"`
+		InvalidParams []struct {
+			Name   string `json:"name"`
+			Reason string `json:"reason"`
+		} `json:"invalid_params"`
+	}
+	suite.parseResponse(resp, &validation)
+	assert.Equal(suite.T(), "INVALID_REQUEST", validation.Code)
+	names := make([]string, len(validation.InvalidParams))
+	for i, param := range validation.InvalidParams {
+		names[i] = param.Name
+		assert.Equal(suite.T(), "is required", param.Reason)
+	}
+	assert.ElementsMatch(suite.T(), []string{"pull_request_id", "author_id"}, names)
+
+	// без Accept ответ в прежнем формате
+	resp, err = suite.makeRequest("POST", "/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-missing"})
+	suite.NoError(err)
+	assert.Equal(suite.T(), "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+	resp.Body.Close()
+}
+
 func (suite *E2ETestSuite) TestTeamOperations() {
 	newTeamReq := map[string]interface{}{
 		"team_name": "devops-team",
@@ -262,8 +329,1051 @@ func (suite *E2ETestSuite) TestMassDeactivation() {
 }
 
 func TestE2ESuite(t *testing.T) {
-	if os.Getenv("E2E_TEST") == "" {
-		t.Skip("Skipping E2E tests. Set E2E_TEST=1 to run.")
+	suite.Run(t, &E2ETestSuite{storage: config.StorageMemory})
+}
+
+func TestE2ESuite_SQLite(t *testing.T) {
+	if os.Getenv("E2E_TEST") != "" {
+		t.Skip("Live service is covered by TestE2ESuite")
+	}
+	suite.Run(t, &E2ETestSuite{storage: config.StorageSQLite})
+}
+
+func (suite *E2ETestSuite) TestAPIV1Resources() {
+	t := suite.T()
+
+	resp, err := suite.makeRequest("POST", "/api/v1/teams", map[string]interface{}{
+		"team_name": "v1-team",
+		"members": []map[string]interface{}{
+			{"user_id": "v1-u1", "username": "Author", "is_active": true},
+			{"user_id": "v1-u2", "username": "Reviewer A", "is_active": true},
+			{"user_id": "v1-u3", "username": "Reviewer B", "is_active": true},
+		},
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var teamsResp struct {
+		Teams []struct {
+			TeamName string `json:"team_name"`
+		} `json:"teams"`
+	}
+	suite.parseResponse(resp, &teamsResp)
+	teamNames := make([]string, len(teamsResp.Teams))
+	for i, team := range teamsResp.Teams {
+		teamNames[i] = team.TeamName
+	}
+	assert.Contains(t, teamNames, "v1-team")
+
+	resp, err = suite.makeRequest("PATCH", "/api/v1/teams/v1-team", map[string]interface{}{"reviewer_count": 1})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// черновик создаётся без ревьюеров, их число не переопределить
+	resp, err = suite.makeRequest("POST", "/api/v1/pull-requests", map[string]interface{}{
+		"pull_request_id":   "v1-pr",
+		"pull_request_name": "Versioned API",
+		"author_id":         "v1-u1",
+		"draft":             true,
+		"reviewer_count":    3,
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("POST", "/api/v1/pull-requests", map[string]interface{}{
+		"pull_request_id":   "v1-pr",
+		"pull_request_name": "Versioned API",
+		"author_id":         "v1-u1",
+		"draft":             true,
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	var prResp struct {
+		PR struct {
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	resp, err = suite.makeRequest("PATCH", "/api/v1/pull-requests/v1-pr", map[string]interface{}{"status": "OPEN"})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	suite.parseResponse(resp, &prResp)
+	assert.Equal(t, "OPEN", prResp.PR.Status)
+	require.Len(t, prResp.PR.AssignedReviewers, 1)
+	reviewer := prResp.PR.AssignedReviewers[0]
+
+	resp, err = suite.makeRequest("GET", "/api/v1/pull-requests/v1-pr/reviewers", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var reviewersResp struct {
+		Reviewers []struct {
+			ReviewerID string `json:"reviewer_id"`
+		} `json:"reviewers"`
+	}
+	suite.parseResponse(resp, &reviewersResp)
+	require.Len(t, reviewersResp.Reviewers, 1)
+	assert.Equal(t, reviewer, reviewersResp.Reviewers[0].ReviewerID)
+
+	resp, err = suite.makeRequest("POST", "/api/v1/pull-requests/v1-pr/reviews",
+		map[string]interface{}{"reviewer_id": reviewer, "verdict": "COMMENTED"})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("DELETE", "/api/v1/pull-requests/v1-pr/reviewers/"+reviewer, nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var reassignResp struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+	suite.parseResponse(resp, &reassignResp)
+	assert.NotEqual(t, reviewer, reassignResp.ReplacedBy)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/users/"+reassignResp.ReplacedBy+"/reviews", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var userPRs struct {
+		PullRequests []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"pull_requests"`
+	}
+	suite.parseResponse(resp, &userPRs)
+	require.Len(t, userPRs.PullRequests, 1)
+	assert.Equal(t, "v1-pr", userPRs.PullRequests[0].PullRequestID)
+
+	resp, err = suite.makeRequest("PATCH", "/api/v1/users/"+reviewer, map[string]interface{}{"is_active": false})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("GET", "/api/v1/users/"+reviewer, nil)
+	suite.NoError(err)
+	var userResp struct {
+		User struct {
+			IsActive bool `json:"is_active"`
+		} `json:"user"`
+	}
+	suite.parseResponse(resp, &userResp)
+	assert.False(t, userResp.User.IsActive)
+
+	resp, err = suite.makeRequest("PATCH", "/api/v1/pull-requests/v1-pr", map[string]interface{}{"status": "CLOSED"})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	suite.parseResponse(resp, &prResp)
+	assert.Equal(t, "CLOSED", prResp.PR.Status)
+
+	// команду с участниками удалить нельзя
+	resp, err = suite.makeRequest("DELETE", "/api/v1/teams/v1-team", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("POST", "/api/v1/teams", map[string]interface{}{"team_name": "v1-empty", "members": []interface{}{}})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("DELETE", "/api/v1/teams/v1-empty", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams/v1-empty", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func (suite *E2ETestSuite) TestPagination() {
+	t := suite.T()
+
+	resp, err := suite.makeRequest("POST", "/api/v1/teams", map[string]interface{}{
+		"team_name": "page-team",
+		"members": []map[string]interface{}{
+			{"user_id": "page-u1", "username": "Author", "is_active": true},
+			{"user_id": "page-u2", "username": "Reviewer A", "is_active": true},
+			{"user_id": "page-u3", "username": "Reviewer B", "is_active": true},
+		},
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	for _, id := range []string{"page-pr-1", "page-pr-2", "page-pr-3"} {
+		resp, err = suite.makeRequest("POST", "/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   id,
+			"pull_request_name": "Paged " + id,
+			"author_id":         "page-u1",
+		})
+		suite.NoError(err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	type prPage struct {
+		PullRequests []struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"pull_requests"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	// обходим все страницы по курсору
+	var ids []string
+	path := "/pullRequests?author_id=page-u1&sort=pull_request_id&limit=2"
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3, "курсор должен закончиться")
+		target := path
+		if cursor != "" {
+			target += "&cursor=" + cursor
+		}
+		resp, err = suite.makeRequest("GET", target, nil)
+		suite.NoError(err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var page prPage
+		suite.parseResponse(resp, &page)
+		for _, pr := range page.PullRequests {
+			ids = append(ids, pr.PullRequestID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"page-pr-1", "page-pr-2", "page-pr-3"}, ids)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/pull-requests?team_name=page-team&created_to=2000-01-01T00:00:00Z", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var empty prPage
+	suite.parseResponse(resp, &empty)
+	assert.Empty(t, empty.PullRequests)
+	assert.Empty(t, empty.NextCursor)
+
+	// курсор сортировки по id не подходит к сортировке по дате
+	for _, target := range []string{
+		"/pullRequests?sort=created_at&cursor=" + cursor,
+		"/pullRequests?cursor=garbage",
+		"/pullRequests?limit=500",
+		"/pullRequests?sort=name",
+	} {
+		resp, err = suite.makeRequest("GET", target, nil)
+		suite.NoError(err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, target)
+		resp.Body.Close()
+	}
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams/page-team/members?limit=2", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	type memberPage struct {
+		Members []struct {
+			UserID string `json:"user_id"`
+		} `json:"members"`
+		NextCursor string `json:"next_cursor"`
+	}
+	var members memberPage
+	suite.parseResponse(resp, &members)
+	require.Len(t, members.Members, 2)
+	assert.Equal(t, "page-u1", members.Members[0].UserID)
+	require.NotEmpty(t, members.NextCursor)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams/page-team/members?limit=2&cursor="+members.NextCursor, nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var lastMembers memberPage
+	suite.parseResponse(resp, &lastMembers)
+	require.Len(t, lastMembers.Members, 1)
+	assert.Equal(t, "page-u3", lastMembers.Members[0].UserID)
+	assert.Empty(t, lastMembers.NextCursor)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams/no-such-team/members", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	// при двух ревьюерах в команде оба назначены на все три PR
+	resp, err = suite.makeRequest("GET", "/users/getReview?user_id=page-u2&limit=2", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reviews prPage
+	suite.parseResponse(resp, &reviews)
+	assert.Len(t, reviews.PullRequests, 2)
+	assert.NotEmpty(t, reviews.NextCursor)
+}
+
+func (suite *E2ETestSuite) TestIdempotencyKey() {
+	t := suite.T()
+
+	resp, err := suite.makeRequest("POST", "/team/add", map[string]interface{}{
+		"team_name": "idem-team",
+		"members": []map[string]interface{}{
+			{"user_id": "idem-u1", "username": "Author", "is_active": true},
+			{"user_id": "idem-u2", "username": "Reviewer A", "is_active": true},
+			{"user_id": "idem-u3", "username": "Reviewer B", "is_active": true},
+			{"user_id": "idem-u4", "username": "Reviewer C", "is_active": true},
+		},
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	createPR := map[string]interface{}{
+		"pull_request_id":   "idem-pr",
+		"pull_request_name": "Retried from CI",
+		"author_id":         "idem-u1",
+		"reviewer_count":    1,
+	}
+	headers := map[string]string{"Idempotency-Key": "ci-run-1"}
+
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/create", createPR, headers)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	type prResponse struct {
+		PR struct {
+			PullRequestID     string   `json:"pull_request_id"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	var first prResponse
+	suite.parseResponse(resp, &first)
+
+	// повтор отдаёт первый ответ вместо PR_EXISTS
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/create", createPR, headers)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	var replayed prResponse
+	suite.parseResponse(resp, &replayed)
+	assert.Equal(t, first, replayed)
+
+	createPR["pull_request_name"] = "Another payload"
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/create", createPR, headers)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	var errorResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errorResp.Error.Code)
+
+	// ключ действует в пределах клиента: чужой токен не получает сохранённый ответ
+	resp, err = suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name":   "idem-other-client",
+		"scopes": []string{"write"},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var other struct {
+		Token string `json:"token"`
+	}
+	suite.parseResponse(resp, &other)
+
+	createPR["pull_request_name"] = "Retried from CI"
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/create", createPR, map[string]string{
+		"Idempotency-Key": "ci-run-1",
+		"Authorization":   "Bearer " + other.Token,
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	resp.Body.Close()
+
+	// без ключа поведение прежнее
+	resp, err = suite.makeRequest("POST", "/pullRequest/create", createPR)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp.Body.Close()
+
+	require.Len(t, first.PR.AssignedReviewers, 1)
+	reassign := map[string]interface{}{
+		"pull_request_id":     "idem-pr",
+		"current_reviewer_id": first.PR.AssignedReviewers[0],
+	}
+	headers = map[string]string{"Idempotency-Key": "relay-42"}
+
+	var replacedBy []string
+	for i := 0; i < 2; i++ {
+		resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/reassign", reassign, headers)
+		suite.NoError(err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var reassigned struct {
+			ReplacedBy string `json:"replaced_by"`
+		}
+		suite.parseResponse(resp, &reassigned)
+		replacedBy = append(replacedBy, reassigned.ReplacedBy)
+	}
+	assert.Equal(t, replacedBy[0], replacedBy[1], "повтор не переназначает второй раз")
+
+	resp, err = suite.makeRequest("GET", "/api/v1/pull-requests/idem-pr/reviewers", nil)
+	suite.NoError(err)
+	var reviewers struct {
+		Reviewers []struct {
+			ReviewerID string `json:"reviewer_id"`
+		} `json:"reviewers"`
+	}
+	suite.parseResponse(resp, &reviewers)
+	require.Len(t, reviewers.Reviewers, 1)
+	assert.Equal(t, replacedBy[0], reviewers.Reviewers[0].ReviewerID)
+}
+
+func (suite *E2ETestSuite) TestAuth() {
+	t := suite.T()
+	noAuth := map[string]string{"Authorization": ""}
+
+	resp, err := suite.makeRequestWithHeaders("GET", "/team/get?team_name=backend", nil, noAuth)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+	resp.Body.Close()
+
+	// проверка работоспособности открыта
+	resp, err = suite.makeRequestWithHeaders("GET", "/health", nil, noAuth)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name":   "dashboard",
+		"scopes": []string{"read"},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created struct {
+		Token    string `json:"token"`
+		APIToken struct {
+			ID     int64    `json:"id"`
+			Prefix string   `json:"prefix"`
+			Scopes []string `json:"scopes"`
+		} `json:"api_token"`
+	}
+	suite.parseResponse(resp, &created)
+	require.NotEmpty(t, created.Token)
+	assert.Equal(t, []string{"read"}, created.APIToken.Scopes)
+	assert.True(t, len(created.Token) > len(created.APIToken.Prefix))
+
+	readToken := map[string]string{"Authorization": "Bearer " + created.Token}
+
+	resp, err = suite.makeRequestWithHeaders("GET", "/team/get?team_name=backend", nil, readToken)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequestWithHeaders("POST", "/users/setIsActive", map[string]interface{}{
+		"user_id":   "u1",
+		"is_active": true,
+	}, readToken)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	var errorResp struct {
+		Error struct {
+			Code    string                 `json:"code"`
+			Details map[string]interface{} `json:"details"`
+		} `json:"error"`
+	}
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "FORBIDDEN", errorResp.Error.Code)
+	assert.Equal(t, "write", errorResp.Error.Details["required_scope"])
+
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/tokens", nil, readToken)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("GET", "/api/v1/tokens", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list struct {
+		Tokens []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"tokens"`
+	}
+	suite.parseResponse(resp, &list)
+	var names []string
+	for _, token := range list.Tokens {
+		names = append(names, token.Name)
+	}
+	assert.Contains(t, names, "dashboard")
+
+	resp, err = suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name":   "bad",
+		"scopes": []string{"root"},
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/tokens/%d", created.APIToken.ID), nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp.Body.Close()
+
+	// отозванный токен больше не принимается
+	resp, err = suite.makeRequestWithHeaders("GET", "/team/get?team_name=backend", nil, readToken)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("DELETE", fmt.Sprintf("/api/v1/tokens/%d", created.APIToken.ID), nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	// прежний заголовок X-Admin-Token работает как Bearer
+	resp, err = suite.makeRequestWithHeaders("GET", "/webhooks/subscriptions/list", nil, map[string]string{
+		"Authorization": "",
+		"X-Admin-Token": suite.adminToken,
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func (suite *E2ETestSuite) TestJWT() {
+	t := suite.T()
+	if suite.ssoKey == nil {
+		t.Skip("JWKS сервиса неизвестен")
+	}
+
+	bearer := func(token string) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + token}
+	}
+	claims := func(groups []string, exp time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"sub":                "u2",
+			"preferred_username": "Bob",
+			"groups":             groups,
+			"aud":                "review-assigner",
+			"exp":                exp.Unix(),
+		}
+	}
+	type principalResponse struct {
+		Subject string   `json:"subject"`
+		Name    string   `json:"name"`
+		Method  string   `json:"method"`
+		UserID  string   `json:"user_id"`
+		Role    string   `json:"role"`
+		Groups  []string `json:"groups"`
+		Scopes  []string `json:"scopes"`
+	}
+
+	member := suite.signJWT(claims([]string{"backend"}, time.Now().Add(time.Hour)))
+
+	resp, err := suite.makeRequestWithHeaders("GET", "/api/v1/me", nil, bearer(member))
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var me principalResponse
+	suite.parseResponse(resp, &me)
+	assert.Equal(t, "u2", me.UserID)
+	assert.Equal(t, "Bob", me.Name)
+	assert.Equal(t, "jwt", me.Method)
+	assert.Equal(t, []string{"backend"}, me.Groups)
+	assert.Equal(t, []string{"write"}, me.Scopes)
+	assert.Equal(t, "member", me.Role)
+
+	resp, err = suite.makeRequestWithHeaders("GET", "/team/get?team_name=backend", nil, bearer(member))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/tokens", nil, bearer(member))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	// группа review-admins даёт область admin
+	admin := suite.signJWT(claims([]string{"backend", "review-admins"}, time.Now().Add(time.Hour)))
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/tokens", nil, bearer(admin))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	var errorResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	expired := suite.signJWT(claims([]string{"review-admins"}, time.Now().Add(-time.Hour)))
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/me", nil, bearer(expired))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "invalid_token")
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "UNAUTHORIZED", errorResp.Error.Code)
+	assert.Equal(t, "Token has expired", errorResp.Error.Message)
+
+	// подпись не сходится с телом
+	parts := strings.Split(member, ".")
+	forged := suite.signJWT(claims([]string{"review-admins"}, time.Now().Add(time.Hour)))
+	forgedParts := strings.Split(forged, ".")
+	tampered := parts[0] + "." + forgedParts[1] + "." + parts[2]
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/tokens", nil, bearer(tampered))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	wrongAudience := claims(nil, time.Now().Add(time.Hour))
+	wrongAudience["aud"] = "another-service"
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/me", nil, bearer(suite.signJWT(wrongAudience)))
+	suite.NoError(err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func (suite *E2ETestSuite) TestRoleBasedAccess() {
+	t := suite.T()
+	if suite.ssoKey == nil {
+		t.Skip("JWKS сервиса неизвестен")
+	}
+
+	as := func(userID string) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + suite.signJWT(map[string]interface{}{
+			"sub": userID,
+			"aud": "review-assigner",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})}
+	}
+	status := func(method, path string, body interface{}, headers map[string]string) int {
+		resp, err := suite.makeRequestWithHeaders(method, path, body, headers)
+		suite.NoError(err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for team, members := range map[string][]string{
+		"rbac-team":  {"rb-lead", "rb-dev1", "rb-dev2", "rb-dev3", "rb-dev4"},
+		"rbac-other": {"rb-x1", "rb-x2"},
+	} {
+		var list []map[string]interface{}
+		for _, id := range members {
+			list = append(list, map[string]interface{}{"user_id": id, "username": id, "is_active": true})
+		}
+		require.Equal(t, http.StatusCreated, status("POST", "/team/add", map[string]interface{}{
+			"team_name": team,
+			"members":   list,
+		}, nil))
+	}
+
+	// участник команды не управляет командой и чужими пользователями
+	dev := as("rb-dev1")
+	assert.Equal(t, http.StatusForbidden, status("POST", "/users/setIsActive",
+		map[string]interface{}{"user_id": "rb-dev2", "is_active": false}, dev))
+	assert.Equal(t, http.StatusOK, status("POST", "/users/setIsActive",
+		map[string]interface{}{"user_id": "rb-dev1", "is_active": true}, dev))
+	assert.Equal(t, http.StatusForbidden, status("POST", "/team/rbac-team/deactivate-users",
+		map[string]interface{}{"user_ids": []string{"rb-dev2"}}, dev))
+	assert.Equal(t, http.StatusForbidden, status("POST", "/pullRequest/create", map[string]interface{}{
+		"pull_request_id": "rbac-pr-foreign", "pull_request_name": "Not mine", "author_id": "rb-dev2",
+	}, dev))
+
+	resp, err := suite.makeRequestWithHeaders("POST", "/pullRequest/create", map[string]interface{}{
+		"pull_request_id": "rbac-pr", "pull_request_name": "Mine", "author_id": "rb-dev1",
+	}, dev)
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	suite.parseResponse(resp, &created)
+	require.NotEmpty(t, created.PR.AssignedReviewers)
+
+	// чужой PR нельзя переназначить или смерджить
+	outsider := as("rb-x1")
+	reassign := map[string]interface{}{
+		"pull_request_id":     "rbac-pr",
+		"current_reviewer_id": created.PR.AssignedReviewers[0],
+	}
+	resp, err = suite.makeRequestWithHeaders("POST", "/pullRequest/reassign", reassign, outsider)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	var errorResp struct {
+		Error struct {
+			Code    string                 `json:"code"`
+			Details map[string]interface{} `json:"details"`
+		} `json:"error"`
+	}
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "FORBIDDEN", errorResp.Error.Code)
+	assert.Equal(t, "reassign_reviewer", errorResp.Error.Details["action"])
+	assert.Equal(t, "member", errorResp.Error.Details["role"])
+	assert.Equal(t, http.StatusForbidden, status("POST", "/pullRequest/merge",
+		map[string]interface{}{"pull_request_id": "rbac-pr"}, outsider))
+	assert.Equal(t, http.StatusNotFound, status("POST", "/pullRequest/merge",
+		map[string]interface{}{"pull_request_id": "rbac-missing"}, outsider))
+
+	// ревьюер PR может его переназначить
+	assert.Equal(t, http.StatusOK, status("POST", "/pullRequest/reassign", reassign, as(created.PR.AssignedReviewers[0])))
+
+	// лид управляет только своей командой
+	require.Equal(t, http.StatusNoContent, status("PUT", "/api/v1/teams/rbac-team/leads/rb-lead", nil, nil))
+	assert.Equal(t, http.StatusForbidden, status("PUT", "/api/v1/teams/rbac-team/leads/rb-dev1", nil, dev))
+
+	resp, err = suite.makeRequest("GET", "/api/v1/teams/rbac-team/leads", nil)
+	suite.NoError(err)
+	var leads struct {
+		Leads []string `json:"leads"`
+	}
+	suite.parseResponse(resp, &leads)
+	assert.Equal(t, []string{"rb-lead"}, leads.Leads)
+
+	lead := as("rb-lead")
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/me", nil, lead)
+	suite.NoError(err)
+	var me struct {
+		Role   string   `json:"role"`
+		LeadOf []string `json:"lead_of"`
+	}
+	suite.parseResponse(resp, &me)
+	assert.Equal(t, "team_lead", me.Role)
+	assert.Equal(t, []string{"rbac-team"}, me.LeadOf)
+
+	assert.Equal(t, http.StatusOK, status("POST", "/users/setIsActive",
+		map[string]interface{}{"user_id": "rb-dev4", "is_active": false}, lead))
+	assert.Equal(t, http.StatusOK, status("POST", "/team/rbac-team/deactivate-users",
+		map[string]interface{}{"user_ids": []string{"rb-dev4"}}, lead))
+	assert.Equal(t, http.StatusForbidden, status("POST", "/team/rbac-other/deactivate-users",
+		map[string]interface{}{"user_ids": []string{"rb-x2"}}, lead))
+	// пользователь чужой команды в списке отменяет всю деактивацию
+	assert.Equal(t, http.StatusNotFound, status("POST", "/team/rbac-team/deactivate-users",
+		map[string]interface{}{"user_ids": []string{"rb-dev3", "rb-x2"}}, lead))
+	for _, userID := range []string{"rb-dev3", "rb-x2"} {
+		resp, err = suite.makeRequest("GET", "/api/v1/users/"+userID, nil)
+		suite.NoError(err)
+		var user struct {
+			User struct {
+				IsActive bool `json:"is_active"`
+			} `json:"user"`
+		}
+		suite.parseResponse(resp, &user)
+		assert.True(t, user.User.IsActive, "%s остался активным", userID)
+	}
+	assert.Equal(t, http.StatusForbidden, status("POST", "/users/setIsActive",
+		map[string]interface{}{"user_id": "rb-x2", "is_active": false}, lead))
+
+	// бот работает с PR, но не с людьми
+	resp, err = suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name":   "rbac-relay",
+		"scopes": []string{"write"},
+	})
+	suite.NoError(err)
+	var botToken struct {
+		Token string `json:"token"`
+	}
+	suite.parseResponse(resp, &botToken)
+	bot := map[string]string{"Authorization": "Bearer " + botToken.Token}
+
+	assert.Equal(t, http.StatusForbidden, status("POST", "/users/setIsActive",
+		map[string]interface{}{"user_id": "rb-dev2", "is_active": false}, bot))
+	assert.Equal(t, http.StatusCreated, status("POST", "/pullRequest/create", map[string]interface{}{
+		"pull_request_id": "rbac-pr-bot", "pull_request_name": "From relay", "author_id": "rb-dev2",
+	}, bot))
+
+	assert.Equal(t, http.StatusNoContent, status("DELETE", "/api/v1/teams/rbac-team/leads/rb-lead", nil, nil))
+	assert.Equal(t, http.StatusNotFound, status("DELETE", "/api/v1/teams/rbac-team/leads/rb-lead", nil, nil))
+}
+
+func (suite *E2ETestSuite) TestAuditLog() {
+	t := suite.T()
+
+	resp, err := suite.makeRequest("GET", "/api/v1/me", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var me struct {
+		Subject string `json:"subject"`
+	}
+	suite.parseResponse(resp, &me)
+
+	resp, err = suite.makeRequest("POST", "/team/add", map[string]interface{}{
+		"team_name": "audit-team",
+		"members": []map[string]interface{}{
+			{"user_id": "au1", "username": "Ann", "is_active": true},
+			{"user_id": "au2", "username": "Ben", "is_active": true},
+			{"user_id": "au3", "username": "Cid", "is_active": true},
+			{"user_id": "au4", "username": "Dan", "is_active": true},
+		},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("POST", "/pullRequest/create", map[string]interface{}{
+		"pull_request_id": "audit-pr", "pull_request_name": "Audited", "author_id": "au1",
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	suite.parseResponse(resp, &created)
+	require.NotEmpty(t, created.PR.AssignedReviewers)
+	removed := created.PR.AssignedReviewers[0]
+
+	resp, err = suite.makeRequest("POST", "/pullRequest/reassign", map[string]interface{}{
+		"pull_request_id": "audit-pr", "current_reviewer_id": removed,
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("POST", "/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": "audit-pr", "force": true,
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	type auditPage struct {
+		Entries []struct {
+			Actor      string          `json:"actor"`
+			Operation  string          `json:"operation"`
+			EntityType string          `json:"entity_type"`
+			Before     json.RawMessage `json:"before"`
+			After      json.RawMessage `json:"after"`
+			IP         string          `json:"ip"`
+		} `json:"entries"`
+		NextCursor string `json:"next_cursor"`
+	}
+
+	resp, err = suite.makeRequest("GET", "/api/v1/audit?entity_type=pull_request&entity_id=audit-pr", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page auditPage
+	suite.parseResponse(resp, &page)
+	require.Len(t, page.Entries, 3)
+	assert.Equal(t, "pr.merge", page.Entries[0].Operation)
+	assert.Equal(t, "pr.replace_reviewer", page.Entries[1].Operation)
+	assert.Equal(t, "pr.create", page.Entries[2].Operation)
+	for _, entry := range page.Entries {
+		assert.Equal(t, me.Subject, entry.Actor)
+		assert.NotEmpty(t, entry.IP)
+	}
+
+	// кто снял ревьюера: он есть в состоянии до замены и пропал после
+	var before, after struct {
+		AssignedReviewers []string `json:"assigned_reviewers"`
+	}
+	require.NoError(t, json.Unmarshal(page.Entries[1].Before, &before))
+	require.NoError(t, json.Unmarshal(page.Entries[1].After, &after))
+	assert.Contains(t, before.AssignedReviewers, removed)
+	assert.NotContains(t, after.AssignedReviewers, removed)
+	assert.Equal(t, "null", string(page.Entries[2].Before))
+
+	resp, err = suite.makeRequest("POST", "/team/audit-team/deactivate-users", map[string]interface{}{
+		"user_ids": []string{"au4"},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = suite.makeRequest("GET", "/audit?operation=team.deactivate_users&entity_id=au4", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	page = auditPage{}
+	suite.parseResponse(resp, &page)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "user", page.Entries[0].EntityType)
+	assert.Contains(t, string(page.Entries[0].After), `"is_active":false`)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/audit?operation=team.create&entity_id=audit-team&limit=1", nil)
+	suite.NoError(err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	page = auditPage{}
+	suite.parseResponse(resp, &page)
+	require.Len(t, page.Entries, 1)
+	assert.Empty(t, page.NextCursor)
+
+	resp, err = suite.makeRequest("GET", "/api/v1/audit?from=yesterday", nil)
+	suite.NoError(err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+
+	if suite.ssoKey == nil {
+		return
+	}
+	// журнал читает только admin
+	resp, err = suite.makeRequestWithHeaders("GET", "/api/v1/audit", nil, map[string]string{
+		"Authorization": "Bearer " + suite.signJWT(map[string]interface{}{
+			"sub": "au1",
+			"aud": "review-assigner",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}),
+	})
+	suite.NoError(err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func (suite *E2ETestSuite) TestMergePolicy() {
+	t := suite.T()
+
+	do := func(method, path string, body interface{}, headers map[string]string) (int, string) {
+		resp, err := suite.makeRequestWithHeaders(method, path, body, headers)
+		suite.NoError(err)
+		var errorResp struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		suite.parseResponse(resp, &errorResp)
+		return resp.StatusCode, errorResp.Error.Code
+	}
+
+	code, _ := do("POST", "/team/add", map[string]interface{}{
+		"team_name": "merge-team",
+		"members": []map[string]interface{}{
+			{"user_id": "mg1", "username": "Ann", "is_active": true},
+			{"user_id": "mg2", "username": "Ben", "is_active": true},
+			{"user_id": "mg3", "username": "Cid", "is_active": true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = do("POST", "/team/settings", map[string]interface{}{
+		"team_name":                  "merge-team",
+		"reviewer_count":             2,
+		"required_approvals":         1,
+		"block_on_changes_requested": true,
+	}, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = do("POST", "/pullRequest/create", map[string]interface{}{
+		"pull_request_id": "merge-pr", "pull_request_name": "Gated", "author_id": "mg1",
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	merge := map[string]interface{}{"pull_request_id": "merge-pr"}
+	code, errCode := do("POST", "/pullRequest/merge", merge, nil)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "MERGE_BLOCKED", errCode, "нет одобрений")
+
+	for reviewer, verdict := range map[string]string{"mg2": "APPROVED", "mg3": "CHANGES_REQUESTED"} {
+		code, _ = do("POST", "/pullRequest/review", map[string]interface{}{
+			"pull_request_id": "merge-pr", "reviewer_id": reviewer, "verdict": verdict,
+		}, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+	code, errCode = do("POST", "/pullRequest/merge", merge, nil)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "MERGE_BLOCKED", errCode, "запрошены изменения")
+
+	// force без области admin запрещен
+	resp, err := suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name": "merge-bot", "scopes": []string{"write"},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var bot struct {
+		Token string `json:"token"`
+	}
+	suite.parseResponse(resp, &bot)
+	force := map[string]interface{}{"pull_request_id": "merge-pr", "force": true}
+	code, _ = do("POST", "/pullRequest/merge", force, map[string]string{"Authorization": "Bearer " + bot.Token})
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _ = do("POST", "/pullRequest/merge", force, nil)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func (suite *E2ETestSuite) TestRateAndBodyLimits() {
+	t := suite.T()
+	if suite.store == nil {
+		t.Skip("лимиты проверяются на сервисе в процессе")
+	}
+
+	// отдельный сервис с маленькими лимитами поверх того же хранилища
+	cfg := &config.Config{
+		Storage:             suite.storage,
+		AdminToken:          e2eAdminToken,
+		RateLimitRead:       100,
+		RateLimitReadBurst:  100,
+		RateLimitWrite:      1,
+		RateLimitWriteBurst: 2,
+		MaxBodyBytes:        512,
+
+		RateLimitAuthFailures:      1,
+		RateLimitAuthFailuresBurst: 3,
+	}
+	limited := httptest.NewServer(newInProcessRouter(cfg, suite.store))
+	defer limited.Close()
+
+	send := func(method, path, token string, body io.Reader) *http.Response {
+		req, err := http.NewRequest(method, limited.URL+path, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := suite.client.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+	merge := `{"pull_request_id":"limits-missing-pr"}`
+
+	for i := 0; i < 2; i++ {
+		resp := send("POST", "/pullRequest/merge", suite.adminToken, strings.NewReader(merge))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "запрос %d из запаса", i+1)
+		resp.Body.Close()
+	}
+	resp := send("POST", "/pullRequest/merge", suite.adminToken, strings.NewReader(merge))
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	var errorResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "RATE_LIMITED", errorResp.Error.Code)
+
+	// чтение считается отдельно
+	resp = send("GET", "/api/v1/me", suite.adminToken, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// у другого токена своя корзина
+	resp, err := suite.makeRequest("POST", "/api/v1/tokens", map[string]interface{}{
+		"name": "limits", "scopes": []string{"write"},
+	})
+	suite.NoError(err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created struct {
+		Token string `json:"token"`
+	}
+	suite.parseResponse(resp, &created)
+
+	oversized := `{"team_name":"limits-team","members":[],"padding":"` + strings.Repeat("x", 1024) + `"}`
+	resp = send("POST", "/team/add", created.Token, strings.NewReader(oversized))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode, "Content-Length больше предела")
+	suite.parseResponse(resp, &errorResp)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", errorResp.Error.Code)
+
+	// без Content-Length тело обрывается на пределе
+	resp = send("POST", "/team/add", created.Token, io.NopCloser(strings.NewReader(oversized)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode, "тело без Content-Length")
+	resp.Body.Close()
+
+	// неверные токены расходуют свою корзину IP, дальше запрос отклоняется до их проверки
+	for i := 0; i < 3; i++ {
+		resp = send("GET", "/api/v1/me", "ra_invalid", nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "попытка %d из запаса", i+1)
+		resp.Body.Close()
 	}
-	suite.Run(t, new(E2ETestSuite))
+	resp = send("GET", "/api/v1/me", "ra_invalid", nil)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	resp.Body.Close()
 }
@@ -2,27 +2,151 @@ package e2e
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"time"
 
+	"ReviewAssigner/internal/config"
+	"ReviewAssigner/internal/handler"
+	"ReviewAssigner/internal/jwt"
+	"ReviewAssigner/internal/models"
+	"ReviewAssigner/internal/service"
+	"ReviewAssigner/internal/storage"
+
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
 )
 
+// e2eAdminToken админский токен сервиса в процессе
+const e2eAdminToken = "e2e-admin-token"
+
+// e2eKeyID kid ключа SSO сервиса в процессе
+const e2eKeyID = "e2e-sso"
+
 type E2ETestSuite struct {
 	suite.Suite
 	baseURL string
 	client  *http.Client
+	server  *httptest.Server
+	store   *storage.Storage
+	// adminToken токен области admin, с ним по умолчанию уходят все запросы
+	adminToken string
+	// ssoKey ключ, которым тесты подписывают JWT вместо SSO; nil — JWT не настроены
+	ssoKey *ecdsa.PrivateKey
+
+	// storage хранилище сервиса в процессе: memory или sqlite
+	storage string
 }
 
+// SetupSuite с E2E_TEST=1 гоняет сценарии против запущенного сервиса на localhost:8080,
+// иначе поднимает роутер в процессе поверх хранилища suite.storage
 func (suite *E2ETestSuite) SetupSuite() {
-	suite.baseURL = "http://localhost:8080"
 	suite.client = &http.Client{
 		Timeout: 30 * time.Second,
 	}
 
-	suite.waitForService()
+	if os.Getenv("E2E_TEST") != "" {
+		suite.baseURL = "http://localhost:8080"
+		suite.adminToken = os.Getenv("ADMIN_TOKEN")
+		suite.waitForService()
+		return
+	}
+
+	suite.adminToken = e2eAdminToken
+	cfg := &config.Config{Storage: suite.storage, SQLitePath: ":memory:", AdminToken: e2eAdminToken}
+	suite.setupSSO(cfg)
+	store, err := storage.Open(cfg)
+	suite.Require().NoError(err)
+	suite.store = store
+
+	suite.server = httptest.NewServer(newInProcessRouter(cfg, store))
+	suite.baseURL = suite.server.URL
+}
+
+func (suite *E2ETestSuite) TearDownSuite() {
+	if suite.server != nil {
+		suite.server.Close()
+	}
+	if suite.store != nil {
+		suite.store.Close()
+	}
+}
+
+// setupSSO публикует JWKS с локальным ключом, как это делает SSO
+func (suite *E2ETestSuite) setupSSO(cfg *config.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	jwk, err := jwt.NewJWK(e2eKeyID, &key.PublicKey)
+	suite.Require().NoError(err)
+	data, err := json.Marshal(jwt.JWKS{Keys: []jwt.JWK{jwk}})
+	suite.Require().NoError(err)
+
+	path := filepath.Join(suite.T().TempDir(), "jwks.json")
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+
+	suite.ssoKey = key
+	cfg.JWKS = path
+	cfg.JWTAudience = "review-assigner"
+	cfg.JWTGroupScopes = map[string]string{"review-admins": models.ScopeAdmin}
+	cfg.JWTDefaultScope = models.ScopeWrite
+}
+
+// signJWT выпускает токен от имени SSO
+func (suite *E2ETestSuite) signJWT(claims map[string]interface{}) string {
+	token, err := jwt.Sign(claims, e2eKeyID, suite.ssoKey)
+	suite.Require().NoError(err)
+	return token
+}
+
+// newInProcessRouter собирает сервис так же, как cmd/api
+func newInProcessRouter(cfg *config.Config, store *storage.Storage) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	reviewService := service.NewReviewService(store.Users, store.PRs, store.Teams, log)
+	prService := service.NewPRService(store.PRs, store.Users, store.Teams, reviewService, store.Tx, log)
+	userService := service.NewUserService(store.Users, store.Teams, store.PRs, reviewService, store.Tx, log)
+	teamService := service.NewTeamService(store.Teams, store.Users, store.Tx, log)
+	webhookService := service.NewWebhookService(store.Webhooks, log)
+	idempotencyService := service.NewIdempotencyService(store.Idempotency, time.Hour, log)
+	var sso *service.SSOConfig
+	if cfg.JWKS != "" {
+		verifier, err := jwt.NewVerifier(context.Background(), jwt.Config{
+			JWKS:     cfg.JWKS,
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		})
+		if err != nil {
+			panic(err)
+		}
+		sso = &service.SSOConfig{
+			Verifier:     verifier,
+			GroupScopes:  cfg.JWTGroupScopes,
+			DefaultScope: cfg.JWTDefaultScope,
+		}
+	}
+	authService := service.NewAuthService(store.Tokens, store.Teams, store.Tx, sso, log)
+	accessService := service.NewAccessService(store.Users, store.PRs, log)
+	auditService := service.NewAuditService(store.Audit, log)
+	if err := authService.EnsureToken(context.Background(), "bootstrap-admin", cfg.AdminToken, []string{models.ScopeAdmin}); err != nil {
+		panic(err)
+	}
+
+	handlers := handler.NewHandler(teamService, userService, prService, webhookService, authService, accessService, auditService, idempotencyService, cfg)
+
+	router := gin.New()
+	handlers.SetupRoutes(router)
+	return router
 }
 
 func (suite *E2ETestSuite) waitForService() {
@@ -41,6 +165,10 @@ func (suite *E2ETestSuite) waitForService() {
 }
 
 func (suite *E2ETestSuite) makeRequest(method, path string, body interface{}) (*http.Response, error) {
+	return suite.makeRequestWithHeaders(method, path, body, nil)
+}
+
+func (suite *E2ETestSuite) makeRequestWithHeaders(method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
 	var reqBody []byte
 	var err error
 
@@ -52,6 +180,17 @@ func (suite *E2ETestSuite) makeRequest(method, path string, body interface{}) (*
 	req, err := http.NewRequest(method, suite.baseURL+path, bytes.NewBuffer(reqBody))
 	suite.NoError(err)
 	req.Header.Set("Content-Type", "application/json")
+	if suite.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+suite.adminToken)
+	}
+	// пустое значение убирает заголовок, например Authorization
+	for key, value := range headers {
+		if value == "" {
+			req.Header.Del(key)
+			continue
+		}
+		req.Header.Set(key, value)
+	}
 
 	return suite.client.Do(req)
 }